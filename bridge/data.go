@@ -63,6 +63,24 @@ func (data Data[T]) Flatten() []expr.Expression[T] {
 	return append(left, right...)
 }
 
+// converts data into an application pattern, e.g., `(Data x y)` becomes
+// `((Data x) y)`
+func (data Data[T]) ToAlmostPattern() (pat expr.AlmostPattern[T], ok bool) {
+	if pat, ok = data.tag.ToAlmostPattern(); !ok {
+		return
+	}
+
+	for _, member := range data.Members {
+		var mem expr.AlmostPattern[T]
+		if mem, ok = member.ToAlmostPattern(); !ok {
+			return
+		}
+		seq := expr.MakeSequence[T](expr.PatternSequenceApplication, pat.GetPattern(), mem.GetPattern())
+		pat, _ = seq.ToAlmostPattern()
+	}
+	return
+}
+
 func (data Data[T]) GetReferred() T {
 	return data.tag.GetReferred()
 }
@@ -18,6 +18,16 @@ func (judgment JudgmentAsExpression[T, E]) Flatten() []expr.Expression[T] {
 	return e.Flatten()
 }
 
+// converts judged expression into a pattern; the judgment's type is dropped
+func (judgment JudgmentAsExpression[T, E]) ToAlmostPattern() (pat expr.AlmostPattern[T], ok bool) {
+	_, e := judgment.TypeAndExpr()
+	p, ok := expr.Expression[T](e).(expr.Patternable[T])
+	if !ok {
+		return
+	}
+	return p.ToAlmostPattern()
+}
+
 func (judgment JudgmentAsExpression[N, E]) MakeJudgment(E, types.Type[N]) types.ExpressionJudgment[N, E] {
 	return judgment
 }
@@ -163,6 +173,7 @@ func (judgment JudgmentAsExpression[T, _]) Collect() (out []T) {
 }
 
 func (judgment JudgmentAsExpression[T, E]) BodyAbstract(v expr.Variable[T], name expr.Const[T]) expr.Expression[T] {
-	ty, e := judgment.TypeAndExpr()
-	return JudgmentAsExpression[T, E](types.Judgment(e, ty))
+	return judgment.expressionAction(
+		func(e expr.Expression[T]) expr.Expression[T] { return e.BodyAbstract(v, name) },
+	)
 }
@@ -21,4 +21,10 @@ func ToPattern[N nameable.Nameable](p expr.AlmostPattern[N], ty types.Monotyped[
 // Match tries to match two patterns. Match returns true on success, else false
 func (this Pattern[N]) Match(that Pattern[N]) bool {
 	return this.ty.Equals(that.ty) && this.pattern.Match(that.pattern)
-}
\ No newline at end of file
+}
+
+// returns type of values the pattern describes
+func (p Pattern[N]) GetType() types.Monotyped[N] { return p.ty }
+
+// returns layout of pattern
+func (p Pattern[N]) GetPattern() expr.AlmostPattern[N] { return p.pattern }
@@ -20,10 +20,13 @@ func (prim Prim[T]) Flatten() []expr.Expression[T] {
 	return []expr.Expression[T]{prim}
 }
 
+func (prim Prim[T]) ToAlmostPattern() (expr.AlmostPattern[T], bool) {
+	return expr.MakeElem[T](expr.PatternElementLiteral, prim.Val.Val()).ToAlmostPattern()
+}
+
 func (prim Prim[T]) ToPattern() Pattern[T] {
-	val := prim.Val.Val()
 	ty := prim.Val.GetType()
-	almost, _ := expr.MakeElem[T](expr.PatternElementLiteral, val).ToAlmostPattern()
+	almost, _ := prim.ToAlmostPattern()
 	pat, _ := ToPattern[T](almost, ty)
 	return pat
 }
@@ -57,4 +57,52 @@ func TestGetTail(t *testing.T) {
 			t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", ti+1, test.expect, actual)
 		}
 	}
-}
\ No newline at end of file
+}
+
+type testReport struct {
+	path       string
+	line, char int
+	rng        int
+	msg        string
+}
+
+func (r testReport) GetLocation() (string, int, int) { return r.path, r.line, r.char }
+func (r testReport) GetRange() int                   { return r.rng }
+func (r testReport) GetSubtype() string              { return "Type" }
+func (r testReport) Message() string                 { return r.msg }
+
+func TestFromReport(t *testing.T) {
+	lines := func(path string, line int) (string, bool) {
+		if path == "test" && line == 1 {
+			return "x = 1 + True", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		in     Report
+		expect string
+	}{
+		{
+			testReport{"test", 1, 9, 4, "cannot unify Int with Bool"},
+			"[test:1:9] Error (Type): cannot unify Int with Bool\n" +
+				"  1 | x = 1 + True\n" +
+				"              ^^^^",
+		},
+		{
+			testReport{"test", 2, 1, 1, "x is not defined"},
+			"[test:2:1] Error (Type): x is not defined",
+		},
+		{
+			testReport{"", 0, 0, 1, "x is not defined"},
+			"Error (Type): x is not defined",
+		},
+	}
+
+	for ti, test := range tests {
+		actual := FromReport(test.in, lines).Error()
+		if actual != test.expect {
+			t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", ti+1, test.expect, actual)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package errors
+
+// reports made by other packages that can be rendered as located
+// notifications, e.g., the error reports of package inf
+type Report interface {
+	// path, line, and char of report; line is 0 when report has no location
+	GetLocation() (path string, line, char int)
+	// number of characters of source code report is about
+	GetRange() int
+	// kind of report, e.g., "Type"
+	GetSubtype() string
+	// human-readable description of report
+	Message() string
+}
+
+// returns source code on line `line` (starting at 1) of file at `path`; returns
+// false if the line is not available
+type SourceLines func(path string, line int) (source string, found bool)
+
+func notifyReport(report Report, lines SourceLines) Notification {
+	path, line, char := report.GetLocation()
+	if line == 0 {
+		return Fnotify("tm", report.GetSubtype(), report.Message())
+	}
+
+	var source string
+	var found bool
+	if lines != nil {
+		source, found = lines(path, line)
+	}
+	if !found {
+		return Fnotify("tflcm", report.GetSubtype(), path, line, char, report.Message())
+	}
+	return Fnotify("tflcmsr", report.GetSubtype(), path, line, char, report.Message(), source, report.GetRange())
+}
+
+// renders `report` as an error. If `lines` is non-nil and has the source line
+// `report` is located at, the line is shown w/ the range of the report pointed
+// to
+func FromReport(report Report, lines SourceLines) Err {
+	return Err{notifyReport(report, lines)}
+}
+
+// renders `report` as a warning; see FromReport
+func WarningFromReport(report Report, lines SourceLines) Warning {
+	return Warning{notifyReport(report, lines)}
+}
@@ -44,6 +44,37 @@ func (a Case[T]) Collect() []T {
 	return res
 }
 
+// returns variables bound by the case
+func (c Case[T]) GetBinders() []Variable[T] { return c.binders }
+
+// returns the pattern matched by the case
+func (c Case[T]) GetPattern() Expression[T] { return c.pattern }
+
+// returns the expression the case evaluates to when its pattern is matched
+func (c Case[T]) GetExpression() Expression[T] { return c.expression }
+
+// replaces the variables bound by the case with `es` (the i-th binder is
+// replaced by the i-th expression), returning the resulting pattern and
+// expression
+//
+// NOTE: panics if len(es) != len(c.GetBinders())
+func (c Case[T]) Instantiate(es ...Expression[T]) (pattern, expression Expression[T]) {
+	if len(es) != len(c.binders) {
+		panic("illegal arguments: len(es) != len(binders)")
+	}
+
+	pattern, expression = c.pattern, c.expression
+	if len(c.binders) == 0 {
+		return
+	}
+
+	var p, e Expression[T] = makeFunction(c.binders, c.pattern), makeFunction(c.binders, c.expression)
+	for _, x := range es {
+		p, e = p.(Function[T]).Instantiate(x), e.(Function[T]).Instantiate(x)
+	}
+	return p, e
+}
+
 type PartialCase_when[T nameable.Nameable] Case[T]
 
 func (bs BindersOnly[T]) InCase(when Expression[T], then Expression[T]) Case[T] {
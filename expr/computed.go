@@ -0,0 +1,90 @@
+package expr
+
+import "github.com/petersalex27/yew-packages/nameable"
+
+// expression that can be used wherever a referable expression is expected but
+// whose value must be computed first, e.g., the index `n + 1` of a dependent
+// type `(Vec a; n + 1)`
+type Computed[T nameable.Nameable] struct{ e Expression[T] }
+
+// creates referable expression w/ the value of `e`
+func Compute[T nameable.Nameable](e Expression[T]) Computed[T] {
+	if c, ok := e.(Computed[T]); ok {
+		return c
+	}
+	return Computed[T]{e}
+}
+
+// returns expression that computes the value
+func (c Computed[T]) GetExpression() Expression[T] { return c.e }
+
+// returns the name at the head of the expression, e.g., `+` for `n + 1`; the
+// zero value is returned when the head is not named
+func (c Computed[T]) GetReferred() (name T) {
+	head := c.e
+	for app, ok := head.(Application[T]); ok; app, ok = head.(Application[T]) {
+		head = app.left
+	}
+	if r, ok := head.(Referable[T]); ok {
+		return r.GetReferred()
+	}
+	return
+}
+
+func (c Computed[T]) remake(f func(Expression[T]) Expression[T]) Expression[T] {
+	return Compute(f(c.e))
+}
+
+func (c Computed[T]) Flatten() []Expression[T] { return c.e.Flatten() }
+
+func (c Computed[T]) BodyAbstract(v Variable[T], name Const[T]) Expression[T] {
+	return Compute(c.e.BodyAbstract(v, name))
+}
+
+func (c Computed[T]) ExtractVariables(gt int) []Variable[T] { return c.e.ExtractVariables(gt) }
+
+func (c Computed[T]) Collect() []T { return c.e.Collect() }
+
+func (c Computed[T]) Copy() Expression[T] { return c.remake((Expression[T]).Copy) }
+
+func (c Computed[T]) String() string { return c.e.String() }
+
+func (c Computed[T]) StrictString() string { return c.e.StrictString() }
+
+func (c Computed[T]) Equals(cxt *Context[T], e Expression[T]) bool {
+	if c2, ok := e.(Computed[T]); ok {
+		e = c2.e
+	}
+	return c.e.Equals(cxt, e)
+}
+
+func (c Computed[T]) StrictEquals(e Expression[T]) bool {
+	if c2, ok := e.(Computed[T]); ok {
+		return c.e.StrictEquals(c2.e)
+	}
+	return false
+}
+
+func (c Computed[T]) Replace(v Variable[T], e Expression[T]) (Expression[T], bool) {
+	res, again := c.e.Replace(v, e)
+	return Compute(res), again
+}
+
+func (c Computed[T]) UpdateVars(gt int, by int) Expression[T] {
+	return Compute(c.e.UpdateVars(gt, by))
+}
+
+func (c Computed[T]) Again() (Expression[T], bool) {
+	res, again := c.e.Again()
+	return Compute(res), again
+}
+
+func (c Computed[T]) Bind(bs BindersOnly[T]) Expression[T] { return Compute(c.e.Bind(bs)) }
+
+func (c Computed[T]) Find(v Variable[T]) bool { return c.e.Find(v) }
+
+func (c Computed[T]) PrepareAsRHS() Expression[T] { return c.remake((Expression[T]).PrepareAsRHS) }
+
+func (c Computed[T]) Rebind() Expression[T] { return c.remake((Expression[T]).Rebind) }
+
+func (c Computed[T]) ForceRequest() Expression[T] { return c.remake((Expression[T]).ForceRequest) }
@@ -74,3 +74,7 @@ func (c Const[T]) PrepareAsRHS() Expression[T] { return c }
 func (c Const[T]) Rebind() Expression[T] { return c }
 
 func (c Const[T]) Copy() Expression[T] { return c }
+
+func (cxt *Context[T]) Const(name string) Const[T] {
+	return Const[T]{cxt.makeName(name)}
+}
@@ -8,6 +8,7 @@ import (
 
 type Context[T nameable.Nameable] struct {
 	varCounter uint32
+	varStride uint32 // difference between consecutive counters, 0 iff 1
 	table    map[string]Expression[T]
 	inverses map[string]Const[T]
 	makeName func(string)T
@@ -19,10 +20,59 @@ func freeVarName(n uint32) string {
 
 func (cxt *Context[T]) NewVar() Variable[T] {
 	n := cxt.varCounter
-	cxt.varCounter++
+	cxt.varCounter += cxt.stride()
 	return cxt.Var(freeVarName(n))
 }
 
+// returns difference between the counters of consecutive new variables
+func (cxt *Context[T]) stride() uint32 {
+	if cxt.varStride == 0 {
+		return 1
+	}
+	return cxt.varStride
+}
+
+// returns `n` contexts that can create variables concurrently: no two of them
+// create variables w/ the same name, and none creates a variable w/ the name
+// of a variable `cxt` has already created. Child `i` counts from the current
+// counter plus `i` in steps of `n` (times the step of `cxt`). The children
+// share the definitions of `cxt`, which must not change until the children are
+// given back w/ Merge
+func (cxt *Context[T]) Fork(n int) []*Context[T] {
+	children := make([]*Context[T], n)
+	for i := range children {
+		children[i] = &Context[T]{
+			varCounter: cxt.varCounter + uint32(i)*cxt.stride(),
+			varStride:  uint32(n) * cxt.stride(),
+			table:      cxt.table,
+			inverses:   cxt.inverses,
+			makeName:   cxt.makeName,
+		}
+	}
+	return children
+}
+
+// continues counting after every variable created by `children`, contexts
+// returned by Fork
+func (cxt *Context[T]) Merge(children ...*Context[T]) {
+	for _, child := range children {
+		if child.varCounter > cxt.varCounter {
+			cxt.varCounter = child.varCounter
+		}
+	}
+}
+
+// returns number of variables created by NewVar
+func (cxt *Context[T]) GetVarCounter() uint32 {
+	return cxt.varCounter
+}
+
+// sets number of variables created by NewVar; the next variable NewVar creates
+// is named after `n`
+func (cxt *Context[T]) SetVarCounter(n uint32) {
+	cxt.varCounter = n
+}
+
 func (cxt *Context[T]) SetNameMaker(f func(string)T) *Context[T] {
 	cxt.makeName = f
 	return cxt
@@ -194,3 +194,30 @@ func TestGetInverse(t *testing.T) {
 		}
 	}
 }
+
+func TestFork(t *testing.T) {
+	cxt := NewContext[test_named]().SetNameMaker(nameMaker)
+	cxt.NewVar() // $0
+
+	children := cxt.Fork(2)
+	tests := []struct {
+		child  *Context[test_named]
+		expect []string
+	}{
+		{children[0], []string{"$1", "$3", "$5"}},
+		{children[1], []string{"$2", "$4"}},
+	}
+
+	for testIndex, test := range tests {
+		for _, expect := range test.expect {
+			if actual := test.child.NewVar().String(); actual != expect {
+				t.Fatalf("failed test #%d: expected %s, got %s\n", testIndex+1, expect, actual)
+			}
+		}
+	}
+
+	cxt.Merge(children...)
+	if actual := cxt.NewVar().String(); actual != "$7" {
+		t.Fatalf("failed test: expected $7 after merge, got %s\n", actual)
+	}
+}
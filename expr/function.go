@@ -356,6 +356,26 @@ func (f Function[T]) Apply(e Expression[T]) Expression[T] {
 	return res // no binders left
 }
 
+// replaces the outer-most binder of `f` with `e`. Unlike Apply, the result is
+// not reduced any further, so the structure of the function's body is kept
+// as-is
+func (f Function[T]) Instantiate(e Expression[T]) Expression[T] {
+	lookFor := f.BindDepth()
+	e2 := e.
+		PrepareAsRHS().
+		UpdateVars(0, lookFor)
+	res, _ := f.e.Replace(f.vars[0], e2)
+	res = res.UpdateVars(lookFor, -1)
+
+	if lookFor > 1 {
+		return Function[T]{
+			vars: f.vars[1:],
+			e:    res,
+		}
+	}
+	return res
+}
+
 func (f Function[T]) DoApplication(e Expression[T]) Expression[T] {
 	return f.Apply(e)
 }
@@ -0,0 +1,62 @@
+package expr
+
+import "github.com/petersalex27/yew-packages/nameable"
+
+var hole_mark string = "?"
+
+// creates thunk for init. hole related strings
+func GenSetHole(mark string) func() {
+	return func() { hole_mark = mark }
+}
+
+// placeholder for an expression that has not been written yet. A hole can
+// stand for an expression of any type
+//
+//	?name
+type Hole[T nameable.Nameable] struct{ Name T }
+
+// creates hole `?name`
+func MakeHole[T nameable.Nameable](name T) Hole[T] {
+	return Hole[T]{name}
+}
+
+func (h Hole[T]) Flatten() []Expression[T] { return []Expression[T]{h} }
+
+func (h Hole[T]) BodyAbstract(Variable[T], Const[T]) Expression[T] { return h }
+
+func (Hole[T]) ExtractVariables(int) []Variable[T] { return []Variable[T]{} }
+
+func (h Hole[T]) Collect() []T { return []T{h.Name} }
+
+func (h Hole[T]) ForceRequest() Expression[T] { return h }
+
+func (h Hole[T]) Equals(_ *Context[T], e Expression[T]) bool {
+	return h.StrictEquals(e.ForceRequest())
+}
+
+func (h Hole[T]) String() string { return hole_mark + h.Name.GetName() }
+
+func (h Hole[T]) StrictString() string { return h.String() }
+
+func (h Hole[T]) Replace(Variable[T], Expression[T]) (Expression[T], bool) { return h, false }
+
+func (h Hole[T]) StrictEquals(e Expression[T]) bool {
+	if h2, ok := e.(Hole[T]); ok {
+		return h.Name.GetName() == h2.Name.GetName()
+	}
+	return false
+}
+
+func (h Hole[T]) UpdateVars(int, int) Expression[T] { return h }
+
+func (h Hole[T]) Again() (Expression[T], bool) { return h, false }
+
+func (h Hole[T]) Bind(BindersOnly[T]) Expression[T] { return h }
+
+func (Hole[T]) Find(Variable[T]) bool { return false }
+
+func (h Hole[T]) PrepareAsRHS() Expression[T] { return h }
+
+func (h Hole[T]) Rebind() Expression[T] { return h }
+
+func (h Hole[T]) Copy() Expression[T] { return h }
@@ -131,3 +131,33 @@ func (p PatternElement[N]) ToAlmostPattern() (AlmostPattern[N], bool) {
 func (p PatternSequence[N]) ToAlmostPattern() (AlmostPattern[N], bool) {
 	return AlmostPattern[N]{pattern: p}, true
 }
+
+// returns the element type of the pattern element
+func (p PatternElement[N]) GetElementType() patternElementType { return p.ty }
+
+// returns the name of the pattern element
+func (p PatternElement[N]) GetElement() N { return p.element }
+
+// returns the sequence type of the pattern sequence
+func (p PatternSequence[N]) GetSequenceType() patternSequenceType { return p.ty }
+
+// returns each pattern in the sequence
+func (p PatternSequence[N]) GetSequence() []AlmostPattern[N] {
+	out := make([]AlmostPattern[N], len(p.sequence))
+	for i, m := range p.sequence {
+		out[i] = AlmostPattern[N]{pattern: m}
+	}
+	return out
+}
+
+// returns wrapped pattern as a pattern element if it is one
+func (pat AlmostPattern[N]) AsElement() (elem PatternElement[N], ok bool) {
+	elem, ok = pat.pattern.(PatternElement[N])
+	return
+}
+
+// returns wrapped pattern as a pattern sequence if it is one
+func (pat AlmostPattern[N]) AsSequence() (seq PatternSequence[N], ok bool) {
+	seq, ok = pat.pattern.(PatternSequence[N])
+	return
+}
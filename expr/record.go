@@ -0,0 +1,350 @@
+package expr
+
+import (
+	"strings"
+
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+var record_open string = "{"
+var record_close string = "}"
+var record_sep string = ", "
+var field_assign string = " = "
+var field_access string = "."
+var record_extend string = " | "
+
+// creates thunk for init. record expression related strings
+func GenSetRecord(open, close, sep, assign, access, extend string) func() {
+	return func() {
+		record_open, record_close, record_sep = open, close, sep
+		field_assign, field_access, record_extend = assign, access, extend
+	}
+}
+
+// labeled member of a record
+type Field[T nameable.Nameable] struct {
+	Label T
+	Value Expression[T]
+}
+
+// creates field `label = value`
+func MakeField[T nameable.Nameable](label T, value Expression[T]) Field[T] {
+	return Field[T]{label, value}
+}
+
+func (f Field[T]) mapValue(g func(Expression[T]) Expression[T]) Field[T] {
+	return Field[T]{f.Label, g(f.Value)}
+}
+
+func (f Field[T]) string(valueString func(Expression[T]) string) string {
+	return f.Label.GetName() + field_assign + valueString(f.Value)
+}
+
+func (f Field[T]) equals(g Field[T], valueEquals func(a, b Expression[T]) bool) bool {
+	return f.Label.GetName() == g.Label.GetName() && valueEquals(f.Value, g.Value)
+}
+
+// record of labeled values
+//
+//	{l1 = e1, l2 = e2, .., lN = eN}
+type Record[T nameable.Nameable] []Field[T]
+
+// returns field labeled `label`; second return value is false iff `r` has no
+// such field
+func (r Record[T]) Get(label T) (field Field[T], found bool) {
+	for _, field = range r {
+		if field.Label.GetName() == label.GetName() {
+			return field, true
+		}
+	}
+	return field, false
+}
+
+func (r Record[T]) mapValues(g func(Expression[T]) Expression[T]) Record[T] {
+	return fun.FMap(r, func(f Field[T]) Field[T] { return f.mapValue(g) })
+}
+
+func (r Record[T]) values() []Expression[T] {
+	return fun.FMap(r, func(f Field[T]) Expression[T] { return f.Value })
+}
+
+func (r Record[T]) Flatten() []Expression[T] {
+	return List[T](r.values()).Flatten()
+}
+
+func (r Record[T]) BodyAbstract(v Variable[T], name Const[T]) Expression[T] {
+	return r.mapValues(func(e Expression[T]) Expression[T] { return e.BodyAbstract(v, name) })
+}
+
+func (r Record[T]) ExtractVariables(gt int) []Variable[T] {
+	return List[T](r.values()).ExtractVariables(gt)
+}
+
+func (r Record[T]) Collect() []T {
+	res := []T{}
+	for _, f := range r {
+		res = append(res, f.Label)
+		res = append(res, f.Value.Collect()...)
+	}
+	return res
+}
+
+func (r Record[T]) Copy() Expression[T] {
+	return r.mapValues((Expression[T]).Copy)
+}
+
+func (r Record[T]) string(valueString func(Expression[T]) string) string {
+	fields := fun.FMap(r, func(f Field[T]) string { return f.string(valueString) })
+	return record_open + strings.Join(fields, record_sep) + record_close
+}
+
+// Record{MakeField("x", Const("0"))}.String() == "{x = 0}"
+func (r Record[T]) String() string {
+	return r.string((Expression[T]).String)
+}
+
+func (r Record[T]) StrictString() string {
+	return r.string((Expression[T]).StrictString)
+}
+
+func (r Record[T]) equals(e Expression[T], valueEquals func(a, b Expression[T]) bool) bool {
+	r2, ok := e.(Record[T])
+	if !ok || len(r) != len(r2) {
+		return false
+	}
+	for i := range r {
+		if !r[i].equals(r2[i], valueEquals) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Record[T]) Equals(cxt *Context[T], e Expression[T]) bool {
+	return r.equals(e.ForceRequest(), func(a, b Expression[T]) bool { return a.Equals(cxt, b) })
+}
+
+func (r Record[T]) StrictEquals(e Expression[T]) bool {
+	return r.equals(e, (Expression[T]).StrictEquals)
+}
+
+func (r Record[T]) Replace(v Variable[T], e Expression[T]) (Expression[T], bool) {
+	return r.mapValues(func(value Expression[T]) Expression[T] {
+		res, _ := value.Replace(v, e) // again is ignored, see (List).Replace
+		return res
+	}), false
+}
+
+func (r Record[T]) UpdateVars(gt int, by int) Expression[T] {
+	return r.mapValues(func(e Expression[T]) Expression[T] { return e.UpdateVars(gt, by) })
+}
+
+func (r Record[T]) Again() (Expression[T], bool) { return r, false }
+
+func (r Record[T]) Bind(bs BindersOnly[T]) Expression[T] {
+	return r.mapValues(func(e Expression[T]) Expression[T] { return e.Bind(bs) })
+}
+
+func (r Record[T]) Find(v Variable[T]) bool {
+	return List[T](r.values()).Find(v)
+}
+
+func (r Record[T]) PrepareAsRHS() Expression[T] {
+	return r.mapValues((Expression[T]).PrepareAsRHS)
+}
+
+func (r Record[T]) Rebind() Expression[T] {
+	return r.mapValues((Expression[T]).Rebind)
+}
+
+func (r Record[T]) ForceRequest() Expression[T] { return r }
+
+// selection of the field labeled `label` from a record
+//
+//	e.label
+type Access[T nameable.Nameable] struct {
+	record Expression[T]
+	label  T
+}
+
+// creates `record.label`
+func AccessField[T nameable.Nameable](record Expression[T], label T) Access[T] {
+	return Access[T]{record, label}
+}
+
+// returns expression whose field is selected
+func (a Access[T]) GetRecord() Expression[T] { return a.record }
+
+// returns label of selected field
+func (a Access[T]) GetLabel() T { return a.label }
+
+func (a Access[T]) remake(record Expression[T]) Access[T] {
+	return Access[T]{record, a.label}
+}
+
+func (a Access[T]) Flatten() []Expression[T] { return a.record.Flatten() }
+
+func (a Access[T]) BodyAbstract(v Variable[T], name Const[T]) Expression[T] {
+	return a.remake(a.record.BodyAbstract(v, name))
+}
+
+func (a Access[T]) ExtractVariables(gt int) []Variable[T] {
+	return a.record.ExtractVariables(gt)
+}
+
+func (a Access[T]) Collect() []T {
+	return append(a.record.Collect(), a.label)
+}
+
+func (a Access[T]) Copy() Expression[T] { return a.remake(a.record.Copy()) }
+
+// AccessField(Var("r"), "name").String() == "r.name"
+func (a Access[T]) String() string {
+	return a.record.String() + field_access + a.label.GetName()
+}
+
+func (a Access[T]) StrictString() string {
+	return a.record.StrictString() + field_access + a.label.GetName()
+}
+
+func (a Access[T]) Equals(cxt *Context[T], e Expression[T]) bool {
+	a2, ok := e.ForceRequest().(Access[T])
+	return ok && a.label.GetName() == a2.label.GetName() && a.record.Equals(cxt, a2.record)
+}
+
+func (a Access[T]) StrictEquals(e Expression[T]) bool {
+	a2, ok := e.(Access[T])
+	return ok && a.label.GetName() == a2.label.GetName() && a.record.StrictEquals(a2.record)
+}
+
+func (a Access[T]) Replace(v Variable[T], e Expression[T]) (Expression[T], bool) {
+	record, _ := a.record.Replace(v, e)
+	return a.remake(record), false
+}
+
+func (a Access[T]) UpdateVars(gt int, by int) Expression[T] {
+	return a.remake(a.record.UpdateVars(gt, by))
+}
+
+func (a Access[T]) Again() (Expression[T], bool) { return a, false }
+
+func (a Access[T]) Bind(bs BindersOnly[T]) Expression[T] { return a.remake(a.record.Bind(bs)) }
+
+func (a Access[T]) Find(v Variable[T]) bool { return a.record.Find(v) }
+
+func (a Access[T]) PrepareAsRHS() Expression[T] { return a.remake(a.record.PrepareAsRHS()) }
+
+func (a Access[T]) Rebind() Expression[T] { return a.remake(a.record.Rebind()) }
+
+// selects field from record when `a`'s record is forced to a record with the
+// field
+func (a Access[T]) ForceRequest() Expression[T] {
+	record := a.record.ForceRequest()
+	if r, ok := record.(Record[T]); ok {
+		if field, found := r.Get(a.label); found {
+			return field.Value.ForceRequest()
+		}
+	}
+	return a.remake(record)
+}
+
+// record extended w/ a new field
+//
+//	{label = value | record}
+type Extension[T nameable.Nameable] struct {
+	field  Field[T]
+	record Expression[T]
+}
+
+// creates `{label = value | record}`
+func Extend[T nameable.Nameable](record Expression[T], label T, value Expression[T]) Extension[T] {
+	return Extension[T]{Field[T]{label, value}, record}
+}
+
+// returns field `record` is extended w/
+func (x Extension[T]) GetField() Field[T] { return x.field }
+
+// returns record being extended
+func (x Extension[T]) GetRecord() Expression[T] { return x.record }
+
+func (x Extension[T]) remake(f func(Expression[T]) Expression[T]) Extension[T] {
+	return Extension[T]{x.field.mapValue(f), f(x.record)}
+}
+
+func (x Extension[T]) Flatten() []Expression[T] {
+	return append(x.field.Value.Flatten(), x.record.Flatten()...)
+}
+
+func (x Extension[T]) BodyAbstract(v Variable[T], name Const[T]) Expression[T] {
+	return x.remake(func(e Expression[T]) Expression[T] { return e.BodyAbstract(v, name) })
+}
+
+func (x Extension[T]) ExtractVariables(gt int) []Variable[T] {
+	return append(x.field.Value.ExtractVariables(gt), x.record.ExtractVariables(gt)...)
+}
+
+func (x Extension[T]) Collect() []T {
+	res := append([]T{x.field.Label}, x.field.Value.Collect()...)
+	return append(res, x.record.Collect()...)
+}
+
+func (x Extension[T]) Copy() Expression[T] { return x.remake((Expression[T]).Copy) }
+
+func (x Extension[T]) string(exprString func(Expression[T]) string) string {
+	return record_open + x.field.string(exprString) + record_extend + exprString(x.record) + record_close
+}
+
+// Extend(Var("r"), "x", Const("0")).String() == "{x = 0 | r}"
+func (x Extension[T]) String() string {
+	return x.string((Expression[T]).String)
+}
+
+func (x Extension[T]) StrictString() string {
+	return x.string((Expression[T]).StrictString)
+}
+
+func (x Extension[T]) Equals(cxt *Context[T], e Expression[T]) bool {
+	x2, ok := e.ForceRequest().(Extension[T])
+	equals := func(a, b Expression[T]) bool { return a.Equals(cxt, b) }
+	return ok && x.field.equals(x2.field, equals) && x.record.Equals(cxt, x2.record)
+}
+
+func (x Extension[T]) StrictEquals(e Expression[T]) bool {
+	x2, ok := e.(Extension[T])
+	return ok && x.field.equals(x2.field, (Expression[T]).StrictEquals) && x.record.StrictEquals(x2.record)
+}
+
+func (x Extension[T]) Replace(v Variable[T], e Expression[T]) (Expression[T], bool) {
+	return x.remake(func(y Expression[T]) Expression[T] {
+		res, _ := y.Replace(v, e) // again is ignored, see (List).Replace
+		return res
+	}), false
+}
+
+func (x Extension[T]) UpdateVars(gt int, by int) Expression[T] {
+	return x.remake(func(e Expression[T]) Expression[T] { return e.UpdateVars(gt, by) })
+}
+
+func (x Extension[T]) Again() (Expression[T], bool) { return x, false }
+
+func (x Extension[T]) Bind(bs BindersOnly[T]) Expression[T] {
+	return x.remake(func(e Expression[T]) Expression[T] { return e.Bind(bs) })
+}
+
+func (x Extension[T]) Find(v Variable[T]) bool {
+	return x.field.Value.Find(v) || x.record.Find(v)
+}
+
+func (x Extension[T]) PrepareAsRHS() Expression[T] { return x.remake((Expression[T]).PrepareAsRHS) }
+
+func (x Extension[T]) Rebind() Expression[T] { return x.remake((Expression[T]).Rebind) }
+
+// adds field to record when `x`'s record is forced to a record
+func (x Extension[T]) ForceRequest() Expression[T] {
+	record := x.record.ForceRequest()
+	if r, ok := record.(Record[T]); ok {
+		return append(Record[T]{x.field}, r...)
+	}
+	return Extension[T]{x.field, record}
+}
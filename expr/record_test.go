@@ -0,0 +1,55 @@
+package expr
+
+import "testing"
+
+func TestRecord(t *testing.T) {
+	r_ := _Var("r")
+	x, y := test_named("x"), test_named("y")
+	// {x = a, y = b}
+	record := Record[test_named]{MakeField[test_named](x, _Const("a")), MakeField[test_named](y, _Const("b"))}
+	// (λr . r.x)
+	getX := Bind[test_named](r_).In(AccessField[test_named](r_, x))
+
+	tests := []struct {
+		input  Expression[test_named]
+		str    string
+		expect Expression[test_named]
+	}{
+		{
+			record,
+			"{x = a, y = b}",
+			record,
+		},
+		{
+			AccessField[test_named](record, y),
+			"{x = a, y = b}.y",
+			_Const("b"),
+		},
+		{
+			Extend[test_named](record, test_named("z"), _Const("c")),
+			"{z = c | {x = a, y = b}}",
+			append(Record[test_named]{MakeField[test_named](test_named("z"), _Const("c"))}, record...),
+		},
+		{
+			AccessField[test_named](Extend[test_named](record, test_named("z"), _Const("c")), test_named("z")),
+			"{z = c | {x = a, y = b}}.z",
+			_Const("c"),
+		},
+		{
+			// forcing an application does not force the result of the application
+			_Apply(getX, record),
+			"((λr . r.x) {x = a, y = b})",
+			AccessField[test_named](record, x),
+		},
+	}
+
+	for i, test := range tests {
+		if str := test.input.String(); str != test.str {
+			t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", i+1, test.str, str)
+		}
+		actual := test.input.ForceRequest()
+		if !actual.StrictEquals(test.expect) {
+			t.Fatalf("failed test #%d:\nexpected:\n%v\nactual:\n%v\n", i+1, test.expect.StrictString(), actual.StrictString())
+		}
+	}
+}
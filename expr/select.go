@@ -54,6 +54,12 @@ func Select[T nameable.Nameable](selector Expression[T], selections ...Case[T])
 	return Selection[T]{selector: selector, selections: selections}
 }
 
+// returns the expression being matched against
+func (s Selection[T]) GetSelector() Expression[T] { return s.selector }
+
+// returns all cases of the selection
+func (s Selection[T]) GetCases() []Case[T] { return s.selections }
+
 func (s Selection[T]) Merge(selections ...Case[T]) Selection[T] {
 	length_s := len(s.selections)
 	newSelec := make([]Case[T], length_s+len(selections))
@@ -0,0 +1,273 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: [Case] rule--typing of select expressions and the patterns of their
+// cases
+//
+// Notes: -
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// opens a case by replacing its binders w/ constants named after them; the
+// binders are added to the context as monomorphic assumptions. Returns the
+// opened pattern and expression, the constants that replaced the binders, and
+// a function that closes an opened case again
+func (cxt *Context[N]) openCase(c expr.Case[N]) (pattern, expression expr.Expression[N], consts []expr.Const[N], closeCase func(pattern, expression expr.Expression[N]) expr.Case[N]) {
+	binders := c.GetBinders()
+	n := len(binders)
+	vs := make([]expr.Variable[N], n)
+	consts = make([]expr.Const[N], n)
+	es := make([]expr.Expression[N], n)
+	// binders opened so far are mentioned by the scope of the rest
+	scope := []expr.Expression[N]{c.GetPattern(), c.GetExpression()}
+	for i := range vs {
+		vs[i], consts[i] = cxt.binderName(binders[i], scope...)
+		scope = append(scope, consts[i])
+		es[i] = consts[i]
+		cxt.Shadow(consts[i], cxt.TypeContext.NewVar())
+	}
+
+	pattern, expression = c.Instantiate(es...)
+
+	closeCase = func(pattern, expression expr.Expression[N]) expr.Case[N] {
+		for i, name := range consts {
+			cxt.Remove(name)
+			pattern = pattern.BodyAbstract(vs[i], name)
+			expression = expression.BodyAbstract(vs[i], name)
+		}
+		return expr.Bind(vs...).InCase(pattern, expression)
+	}
+	return
+}
+
+// all patterns must have the same type as the selector, and all cases must
+// result in the same type
+//
+//	𝚪 ⊢ e: t    𝚪,𝚫1 ⊢ p1: t    𝚪,𝚫1 ⊢ e1: t'   ...   𝚪,𝚫N ⊢ pN: t    𝚪,𝚫N ⊢ eN: t'
+//	-------------------------------------------------------------------------------
+//	                𝚪 ⊢ select e when p1 -> e1 ... when pN -> eN: t'
+func (cxt *Context[N]) inferSelection(s expr.Selection[N]) exprConclusion[N] {
+	return cxt.checkSelection(s, cxt.TypeContext.NewVar())
+}
+
+// [Case] rule:
+//
+//	𝚪 ⊢ e: t    𝚪,𝚫1 ⊢ p1: t    𝚪,𝚫1 ⊢ e1 ⇐ t'   ...   𝚪,𝚫N ⊢ pN: t    𝚪,𝚫N ⊢ eN ⇐ t'
+//	-------------------------------------------------------------------------------
+//	                𝚪 ⊢ select e when p1 -> e1 ... when pN -> eN ⇐ t'
+//	where
+//	    𝚫I = x1: a1, ..., xK: aK for the variables bound by case I (each aJ is a
+//	    new, monomorphic type variable)
+//
+// all patterns must have the same type as the selector, and each case must
+// result in the expected type. Cases that are never reached and values that no
+// case matches are recorded as warnings
+func (cxt *Context[N]) checkSelection(s expr.Selection[N], expected types.Monotyped[N]) exprConclusion[N] {
+	c0 := cxt.infer(s.GetSelector())
+	if c0.NotOk() {
+		return c0
+	}
+
+	selector, t := c0.judgment.GetExpression(), c0.judgment.GetType()
+	cases := s.GetCases()
+	closedCases := make([]expr.Case[N], len(cases))
+	coreCases := make([]CoreCase[N], len(cases))
+
+	for i, c := range cases {
+		pattern, expression, consts, closeCase := cxt.openCase(c)
+		binders := make(map[string]bool, len(consts))
+		for _, name := range consts {
+			binders[name.Name.GetName()] = true
+		}
+		// pattern and selector must have the same type
+		cp := cxt.inferPattern(pattern, binders)
+		stat := cp.Status
+		if stat.IsOk() {
+			if stat = cxt.Unify(t, cp.judgment.GetType()); stat.NotOk() {
+				cxt.appendReport(makeReport[N]("Case", stat, c0.judgment, cp.judgment))
+			}
+		}
+		// each case must result in the expected type
+		var ce exprConclusion[N]
+		if stat.IsOk() {
+			ce = cxt.check(expression, expected)
+			stat = ce.Status
+		}
+
+		if stat.NotOk() {
+			closeCase(pattern, expression)
+			return cannotInfer[N](stat)
+		}
+		coreCases[i] = cxt.coreCase(consts, cp.judgment.GetExpression(), ce.core)
+		closedCases[i] = closeCase(cp.judgment.GetExpression(), ce.judgment.GetExpression())
+	}
+
+	cxt.checkCaseCoverage(cxt.GetSub(t), closedCases)
+	return concludeInferred[N](expr.Select(selector, closedCases...), cxt.GetSub(expected)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreSelect[N]{c0.core, coreCases, t}
+	})
+}
+
+// case of a core select expression; `binders` must still be in the context
+func (cxt *Context[N]) coreCase(binders []expr.Const[N], pattern expr.Expression[N], body Core[N]) CoreCase[N] {
+	names := make([]N, len(binders))
+	binderTypes := make([]types.Monotyped[N], len(binders))
+	for i, binder := range binders {
+		judged, _ := cxt.Get(binder)
+		ty, _ := judged.TypeAndExpr()
+		names[i], binderTypes[i] = binder.Name, ty.(types.Monotyped[N])
+	}
+	return CoreCase[N]{names, binderTypes, pattern, body}
+}
+
+// returns true iff `name` is the name of the wildcard pattern, `_`
+func isWildcard[N nameable.Nameable](name expr.Const[N]) bool {
+	return name.Name.GetName() == wildcardConstructorName
+}
+
+// infers the type of a pattern. Patterns are built from
+//   - variables bound by the case (named in `binders`)
+//   - the wildcard `_`
+//   - constructors (registered w/ AddConstructorFor) applied to exactly as many
+//     patterns as they have members
+//   - primitives
+//   - lists of patterns
+//   - patterns judged to have some type
+func (cxt *Context[N]) inferPattern(pattern expr.Expression[N], binders map[string]bool) exprConclusion[N] {
+	switch x := pattern.(type) {
+	case expr.Const[N]:
+		if binders[x.Name.GetName()] {
+			return generalConclusion(cxt.Var(x))
+		}
+		if isWildcard(x) {
+			return concludeInferred[N](x, cxt.TypeContext.NewVar())
+		}
+		return cxt.inferConstructorPattern(x, x, nil, binders)
+	case expr.Application[N]:
+		head, args := unwindApplication(x)
+		if c, ok := head.(expr.Const[N]); ok && !binders[c.Name.GetName()] && !isWildcard(c) {
+			return cxt.inferConstructorPattern(x, c, args, binders)
+		}
+	case bridge.Data[N]:
+		args := make([]expr.Expression[N], len(x.Members))
+		for i, member := range x.Members {
+			args[i] = member
+		}
+		return cxt.inferConstructorPattern(x, x.GetTag(), args, binders)
+	case bridge.Prim[N]:
+		return generalConclusion(cxt.Primitive(x))
+	case expr.List[N]:
+		return cxt.inferListPattern(x, binders)
+	case bridge.JudgmentAsExpression[N, expr.Expression[N]]:
+		return cxt.inferJudgedPattern(x, binders)
+	}
+
+	cxt.appendReport(makeReport("Case", IllegalPattern, cxt.Judge(pattern)))
+	return cannotInfer[N](IllegalPattern)
+}
+
+// (((e0 e1) e2) .. eN) => e0, [e1, e2, .., eN]
+func unwindApplication[N nameable.Nameable](a expr.Application[N]) (head expr.Expression[N], args []expr.Expression[N]) {
+	var right expr.Expression[N]
+	head, right = a.Split()
+	args = []expr.Expression[N]{right}
+	for {
+		left, ok := head.(expr.Application[N])
+		if !ok {
+			break
+		}
+		head, right = left.Split()
+		args = append([]expr.Expression[N]{right}, args...)
+	}
+	return
+}
+
+// types constructor pattern `pattern` = `name args..`
+//
+//	C: σ    t0 = Inst(σ)    𝚪 ⊢ p1: t1 ... 𝚪 ⊢ pN: tN    t0 = t1 -> .. -> tN -> t
+//	------------------------------------------------------------------------------
+//	                             𝚪 ⊢ C p1 .. pN: t
+//
+// where C has exactly N members
+func (cxt *Context[N]) inferConstructorPattern(pattern expr.Expression[N], name expr.Const[N], args []expr.Expression[N], binders map[string]bool) exprConclusion[N] {
+	constructor, found := cxt.findConstructor(name.Name)
+	if !found {
+		cxt.appendReport(makeNameReport("Case", UndefinedConstructor, name))
+		return cannotInfer[N](UndefinedConstructor)
+	}
+
+	if arity := len(constructor.GetExpression().GetBinders()); arity != len(args) {
+		cxt.appendReport(makeReport("Case", ConstructorArityMismatch, cxt.Judge(pattern)))
+		return cannotInfer[N](ConstructorArityMismatch)
+	}
+
+	t := cxt.Inst(constructor.GetType())
+	for _, arg := range args {
+		c := cxt.inferPattern(arg, binders)
+		if c.NotOk() {
+			return c
+		}
+		rest := cxt.TypeContext.NewVar()
+		if stat := cxt.Unify(t, cxt.TypeContext.Function(c.judgment.GetType(), rest)); stat.NotOk() {
+			cxt.appendReport(makeReport[N]("Case", stat, c.judgment))
+			return cannotInfer[N](stat)
+		}
+		t = rest
+	}
+
+	return concludeInferred(pattern, cxt.GetSub(t))
+}
+
+// all elements of a list pattern must have the same type
+func (cxt *Context[N]) inferListPattern(ls expr.List[N], binders map[string]bool) exprConclusion[N] {
+	elem := cxt.TypeContext.NewVar()
+	for _, p := range ls {
+		c := cxt.inferPattern(p, binders)
+		if c.NotOk() {
+			return c
+		}
+		if stat := cxt.Unify(elem, c.judgment.GetType()); stat.NotOk() {
+			cxt.appendReport(makeReport[N]("Case", stat, c.judgment))
+			return cannotInfer[N](stat)
+		}
+	}
+
+	listType := types.Apply[N](cxt.TypeContext.EnclosingCon(1, "[]"), cxt.GetSub(elem))
+	return concludeInferred[N](ls, listType)
+}
+
+// pattern must have the type it is judged to have
+func (cxt *Context[N]) inferJudgedPattern(j bridge.JudgmentAsExpression[N, expr.Expression[N]], binders map[string]bool) exprConclusion[N] {
+	ty, p := j.TypeAndExpr()
+	c := cxt.inferPattern(p, binders)
+	if c.NotOk() {
+		return c
+	}
+
+	var sigma types.Polytype[N]
+	switch t := ty.(type) {
+	case types.Polytype[N]:
+		sigma = t
+	case types.DependentTyped[N]:
+		sigma = types.Forall[N]().Bind(t)
+	default:
+		// no evidence is matched, so patterns cannot be judged to have a
+		// qualified type
+		cxt.appendReport(makeReport("Case", IllegalPattern, cxt.Judge(j)))
+		return cannotInfer[N](IllegalPattern)
+	}
+
+	t := cxt.Inst(sigma)
+	if stat := cxt.Unify(t, c.judgment.GetType()); stat.NotOk() {
+		cxt.appendReport(makeReport[N]("Case", stat, c.judgment, j))
+		return cannotInfer[N](stat)
+	}
+	return concludeInferred[N](j, cxt.GetSub(t))
+}
@@ -0,0 +1,147 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// creates a context w/
+//
+//	0: Int
+//	Maybe a = Just a | Nothing
+func makeCaseTestContext() *Context[nameable.Testable] {
+	cxt := makeDriverTestContext()
+	nothing := expr.Const[nameable.Testable]{Name: "Nothing"}
+	cxt.AddConstructorFor("Maybe", bridge.MakeData(nothing))
+	return cxt
+}
+
+func TestCase(t *testing.T) {
+	x := expr.Var(nameable.MakeTestable("x"))
+	y := expr.Var(nameable.MakeTestable("y"))
+	z := expr.Var(nameable.MakeTestable("z"))
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+	nothing := expr.Const[nameable.Testable]{Name: "Nothing"}
+	nope := expr.Const[nameable.Testable]{Name: "Nope"}
+	wildcard := expr.Const[nameable.Testable]{Name: "_"}
+	just0 := expr.Apply[nameable.Testable](just, zero)
+	justY := expr.Apply[nameable.Testable](just, y)
+	a := types.Var(nameable.MakeTestable("a"))
+	// forall a . Eq a => a
+	qualified := types.Qualify(types.Forall(a).Bind(a), types.Pred[nameable.Testable]("Eq", a))
+
+	tests := []struct {
+		description string
+		input       expr.Selection[nameable.Testable]
+		expect      string
+		stat        Status
+	}{
+		{
+			`select Just 0 when Just y -> y when Nothing -> 0`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(justY, y),
+				expr.Bind[nameable.Testable]().InCase(nothing, zero),
+			),
+			"Int",
+			Ok,
+		},
+		{
+			`select Just 0 when _ -> 0`,
+			expr.Select[nameable.Testable](just0, expr.Bind[nameable.Testable]().InCase(wildcard, zero)),
+			"Int",
+			Ok,
+		},
+		{
+			`select Just 0 when y -> y`,
+			expr.Select[nameable.Testable](just0, expr.Bind(y).InCase(y, y)),
+			"(Maybe Int)",
+			Ok,
+		},
+		{
+			`select [0] when [y] -> y`,
+			expr.Select[nameable.Testable](
+				expr.List[nameable.Testable]{zero},
+				expr.Bind(y).InCase(expr.List[nameable.Testable]{y}, y),
+			),
+			"Int",
+			Ok,
+		},
+		{
+			`select Just 0 when (Just y) -> y`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(bridge.MakeData(just, bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](y, types.Var(nameable.MakeTestable("b")))), y),
+			),
+			"Int",
+			Ok,
+		},
+		{
+			`select Just 0 when Nope y -> y`,
+			expr.Select[nameable.Testable](just0, expr.Bind(y).InCase(expr.Apply[nameable.Testable](nope, y), y)),
+			"",
+			UndefinedConstructor,
+		},
+		{
+			`select Just 0 when Just y z -> y`,
+			expr.Select[nameable.Testable](just0, expr.Bind(y, z).InCase(expr.Apply[nameable.Testable](just, y, z), y)),
+			"",
+			ConstructorArityMismatch,
+		},
+		{
+			`select Just 0 when Just -> 0`,
+			expr.Select[nameable.Testable](just0, expr.Bind[nameable.Testable]().InCase(just, zero)),
+			"",
+			ConstructorArityMismatch,
+		},
+		{
+			`select 0 when Just y -> y`,
+			expr.Select[nameable.Testable](zero, expr.Bind(y).InCase(justY, y)),
+			"",
+			ConstantMismatch,
+		},
+		{
+			`select Just 0 when Just y -> y when Nothing -> Nothing`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(justY, y),
+				expr.Bind[nameable.Testable]().InCase(nothing, nothing),
+			),
+			"",
+			ConstantMismatch,
+		},
+		{
+			`select Just 0 when (\x -> x) -> 0`,
+			expr.Select[nameable.Testable](just0, expr.Bind[nameable.Testable]().InCase(expr.Bind(x).In(x), zero)),
+			"",
+			IllegalPattern,
+		},
+		{
+			`select 0 when (y: forall a . Eq a => a) -> y`,
+			expr.Select[nameable.Testable](zero, expr.Bind(y).InCase(bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](y, qualified), y)),
+			"",
+			IllegalPattern,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeCaseTestContext()
+		actual, reports := cxt.Infer(test.input)
+		if test.stat.NotOk() {
+			if len(reports) != 1 || !reports[0].Status.Is(test.stat) {
+				t.Fatal(testutil.Testing("status", test.description).FailMessage(test.stat, reports, i))
+			}
+			continue
+		}
+
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("errors", test.description).FailMessage(nil, reports, i))
+		}
+		if actual.String() != test.expect {
+			t.Fatal(testutil.Testing("equality", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: checking mode--pushes an expected type into an expression instead
+// of only unifying it w/ the expression's inferred type after the fact
+//
+// Notes: -
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// checks that `e` has type `expected`. Any variables bound by `expected`
+// (type variables and mapval-bound kind variables) are treated as rigid, i.e.,
+// `e` must have the type for every choice of those variables
+//
+//	𝚪 ⊢ e ⇐ σ
+//	------------
+//	𝚪 ⊢ e: σ
+func (cxt *Context[N]) Check(e expr.Expression[N], expected types.Type[N]) Conclusion[N, expr.Expression[N], types.Monotyped[N]] {
+	if stat := cxt.checkKind("Check", make(map[string]Kind), expected); stat.NotOk() {
+		return Conclusion[N, expr.Expression[N], types.Monotyped[N]](cannotInfer[N](stat))
+	}
+	return Conclusion[N, expr.Expression[N], types.Monotyped[N]](cxt.checkSigma(e, expected))
+}
+
+// creates a new skolem for `v`, a rigid variable that is only equal to itself
+// (see types.(*Context).Skolem)
+func (cxt *Context[N]) newSkolem(v types.Variable[N]) types.Monotyped[N] {
+	return cxt.TypeContext.Skolem(v)
+}
+
+// replaces all variables bound by `ty` w/ new skolems and constants, returning
+// the result along w/ the skolems that replaced type variables
+//
+//	skolemize(forall a . mapval (n: Uint) . [a; n]) = [a; $1], [a]
+func (cxt *Context[N]) skolemize(ty types.Type[N]) (rho types.Monotyped[N], skolems []types.Monotyped[N]) {
+	var binders []types.Variable[N]
+	var bound types.DependentTyped[N]
+	if q, ok := ty.(types.Qualified[N]); ok {
+		// predicates are assumed separately; see assumeContext
+		ty = q.GetPolytype()
+	}
+	if sigma, ok := ty.(types.Polytype[N]); ok {
+		binders, bound = sigma.GetBinders(), sigma.GetBound()
+	} else {
+		bound = ty.(types.DependentTyped[N])
+	}
+
+	var m types.Monotyped[N]
+	if d, ok := bound.(types.DependentType[N]); ok {
+		dependees := types.GetDependees[N](d)
+		skolems := fun.FMap(dependees, func(types.TypeJudgment[N, expr.Variable[N]]) expr.Referable[N] {
+			_, c := cxt.freshName()
+			return c
+		})
+		m = d.Function.SubVars(dependees, skolems)
+	} else {
+		m = bound.(types.Monotyped[N])
+	}
+
+	if len(binders) == 0 {
+		return m, nil
+	}
+	skolems = fun.FMap(binders, cxt.newSkolem)
+	return m.ReplaceDependent(binders, skolems), skolems
+}
+
+// assumes the predicates qualifying `sigma` w/ the variables `sigma` binds
+// replaced by `skolems`, the skolems returned by skolemize. Each predicate is
+// proven by a new dictionary parameter
+//
+//	assumeContext(forall a . Eq a => a -> Bool, [a]) = [d: Eq a]
+func (cxt *Context[N]) assumeContext(sigma types.Type[N], skolems []types.Monotyped[N]) []constraint[N] {
+	q, ok := sigma.(types.Qualified[N])
+	if !ok {
+		return nil
+	}
+	givens := make([]constraint[N], len(q.GetContext()))
+	for i, p := range q.GetContext() {
+		_, evidence := cxt.freshName()
+		givens[i] = constraint[N]{evidence, p.ReplaceDependent(q.GetBinders(), skolems)}
+	}
+	return givens
+}
+
+// returns the placeholders of the constraints currently wanted
+func (cxt *Context[N]) wantedEvidence() map[string]bool {
+	placeholders := make(map[string]bool, len(cxt.wanted))
+	for _, c := range cxt.wanted {
+		placeholders[c.evidence.Name.GetName()] = true
+	}
+	return placeholders
+}
+
+// proves each constraint wanted since `before` (see wantedEvidence) that
+// follows from `givens` and the instances in the context; constraints that do
+// not are left wanted
+func (cxt *Context[N]) discharge(givens []constraint[N], before map[string]bool) {
+	old := cxt.wanted
+	assumptions := asAssumptions(givens)
+	kept := make([]constraint[N], 0, len(old))
+	for _, c := range old {
+		if !before[c.evidence.Name.GetName()] {
+			if evidence, entailed := cxt.entails(assumptions, c.pred); entailed {
+				cxt.prove(c.evidence, evidence)
+				continue
+			}
+		}
+		kept = append(kept, c)
+	}
+	cxt.wanted = kept
+	cxt.onRollback(func() { cxt.wanted = old })
+}
+
+// splits `m` into `left -> right` if `m` is a function type
+func (cxt *Context[N]) splitFunction(m types.Monotyped[N]) (left, right types.Monotyped[N], isFunction bool) {
+	c, params, indexes := Split(cxt.GetSub(m))
+	arrow := cxt.TypeContext.InfixCon("->").GetName()
+	if isFunction = c == arrow && len(params) == 2 && len(indexes) == 0; isFunction {
+		left, right = params[0], params[1]
+	}
+	return
+}
+
+// dispatches `e` to the rule that can push `expected` into it; when there is
+// no such rule, `e`'s type is inferred and unified w/ `expected`
+func (cxt *Context[N]) check(e expr.Expression[N], expected types.Monotyped[N]) exprConclusion[N] {
+	defer cxt.enterSpan(e)()
+	if nested, ok := cxt.GetSub(expected).(types.Nested[N]); ok {
+		return cxt.checkSigma(e, nested.GetPolytype())
+	}
+
+	switch x := e.(type) {
+	case expr.Function[N]:
+		if left, right, ok := cxt.splitFunction(expected); ok {
+			return cxt.derive("Abs", e, expected, func() exprConclusion[N] { return cxt.checkFunction(x, left, right) })
+		}
+	case expr.NameContext[N]:
+		return cxt.derive("Let", e, expected, func() exprConclusion[N] { return cxt.checkNameContext(x, expected) })
+	case expr.RecIn[N]:
+		return cxt.derive("Rec", e, expected, func() exprConclusion[N] { return cxt.checkRecIn(x, expected) })
+	case expr.Selection[N]:
+		return cxt.derive("Case", e, expected, func() exprConclusion[N] { return cxt.checkSelection(x, expected) })
+	}
+
+	c := cxt.infer(e)
+	if c.NotOk() {
+		return c
+	}
+	return cxt.subsumes(c, expected)
+}
+
+// checks that type of conclusion `c` is at least as polymorphic as `expected`;
+// the core term of `c` is coerced to `expected` (see subsCheck)
+func (cxt *Context[N]) subsumes(c exprConclusion[N], expected types.Monotyped[N]) exprConclusion[N] {
+	k, stat := cxt.subsCheck(c.judgment.GetType(), expected)
+	if stat.NotOk() {
+		e := c.judgment.GetExpression()
+		cxt.appendReport(makeReport[N]("Check", stat, c.judgment, bridge.Judgment(e, types.Type[N](expected))))
+		return cannotInfer[N](stat)
+	}
+	out := concludeInferred(c.judgment.GetExpression(), cxt.GetSub(expected))
+	out.core = k.apply(c.core)
+	return out
+}
+
+// checking version of [Abs] rule:
+//
+//	𝚪, param: t0 ⊢ e ⇐ t1
+//	----------------------------
+//	𝚪 ⊢ (λparam . e) ⇐ t0 -> t1
+//
+// when `t0` is a nested polytype σ, `param` has type σ in the body, so it can
+// be used at more than one type
+func (cxt *Context[N]) checkFunction(f expr.Function[N], t0, t1 types.Monotyped[N]) exprConclusion[N] {
+	v, param := cxt.binderName(f.GetBinders()[0], f)
+	body := f.Instantiate(param)
+
+	var paramType types.Type[N] = t0
+	if nested, ok := t0.(types.Nested[N]); ok {
+		paramType = nested.GetPolytype()
+	}
+	cxt.Shadow(param, paramType)
+	c := cxt.check(body, t1)
+	cxt.Remove(param)
+	if c.NotOk() {
+		return c
+	}
+
+	e := c.judgment.GetExpression().BodyAbstract(v, param)
+	fnType := cxt.TypeContext.Function(t0, c.judgment.GetType())
+	return concludeInferred[N](expr.Bind(v).In(e), cxt.GetSub(fnType)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreLambda[N]{param.Name, paramType, c.core, t}
+	})
+}
+
+// binds `name` to `bound` generalized to the type `name` has in the context;
+// `context` holds the class constraints on the type
+func (cxt *Context[N]) coreBinding(name expr.Const[N], context []constraint[N], bound Core[N]) CoreBinding[N] {
+	judged, found := cxt.Get(name)
+	if !found {
+		// rule failed and already discharged `name`
+		return CoreBinding[N]{name.Name, nil, bound}
+	}
+	sigma, _ := judged.TypeAndExpr()
+	return CoreBinding[N]{name.Name, sigma, coreGeneralization(bindersOf(sigma), context, bound, sigma)}
+}
+
+// checking version of [Let] rule:
+//
+//	𝚪 ⊢ e0: t0    𝚪, name: Gen(t0) ⊢ e1 ⇐ t1
+//	-----------------------------------------
+//	    𝚪 ⊢ let name = e0 in e1 ⇐ t1
+func (cxt *Context[N]) checkNameContext(let expr.NameContext[N], expected types.Monotyped[N]) exprConclusion[N] {
+	cxt.TypeContext.EnterLevel()
+	c0 := cxt.infer(let.GetAssignment())
+	cxt.TypeContext.LeaveLevel()
+	if c0.NotOk() {
+		return c0
+	}
+
+	name := let.GetName()
+	discharge, context := cxt.let(name.Name, c0.judgment)
+	binding := cxt.coreBinding(name, context, c0.core)
+	c1 := cxt.check(let.GetContextualized(), expected)
+	if c1.NotOk() {
+		cxt.Remove(name)
+		return c1
+	}
+	return generalConclusion(discharge(c1.judgment)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreLet[N]{false, []CoreBinding[N]{binding}, c1.core, t}
+	})
+}
+
+// like [Rec], but the rec-expression's body is checked against `expected`.
+// Definitions that are not all mutually recursive are first split into groups
+// (see Regroup); a lone definition is left to [Rec], which generalizes it just
+// as [Let] would
+func (cxt *Context[N]) checkRecIn(rec expr.RecIn[N], expected types.Monotyped[N]) exprConclusion[N] {
+	if len(BindingGroups(rec.GetDefs())) != 1 {
+		return cxt.check(Regroup(rec.GetDefs(), rec.GetContextualized()), expected)
+	}
+
+	consts := rec.GetNames()
+	names := fun.FMap(consts, func(c expr.Const[N]) N { return c.Name })
+
+	removeNames := func() {
+		for _, c := range consts {
+			cxt.Remove(c)
+		}
+	}
+
+	assignments := rec.GetAssignments()
+	js := make([]TypeJudgment[N], len(assignments))
+	bindings := make([]CoreBinding[N], len(assignments))
+	cxt.TypeContext.EnterLevel()
+	discharge := cxt.rec(names)
+	for i, assignment := range assignments {
+		c := cxt.infer(assignment)
+		if c.NotOk() {
+			cxt.TypeContext.LeaveLevel()
+			removeNames()
+			return c
+		}
+		js[i], bindings[i].Bound = c.judgment, c.core
+	}
+	cxt.TypeContext.LeaveLevel()
+
+	discharge2, context := discharge(js)
+	for i, c := range consts {
+		bindings[i] = cxt.coreBinding(c, context, bindings[i].Bound)
+	}
+	c := cxt.check(rec.GetContextualized(), expected)
+	if c.NotOk() {
+		removeNames()
+		return c
+	}
+	return generalConclusion(discharge2(c.judgment)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreLet[N]{true, bindings, c.core, t}
+	})
+}
@@ -0,0 +1,134 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestCheck(t *testing.T) {
+	arrow := types.MakeInfixConst[nameable.Testable](nameable.MakeTestable("->"))
+	x := expr.Var(nameable.MakeTestable("x"))
+	y := expr.Var(nameable.MakeTestable("y"))
+	n := expr.Var(nameable.MakeTestable("n"))
+	id := expr.Const[nameable.Testable]{Name: "id"}
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+	idFunc := expr.Bind[nameable.Testable](x).In(x)       // (\x -> x)
+	constZero := expr.Bind[nameable.Testable](x).In(zero) // (\x -> 0)
+
+	a := types.Var(nameable.MakeTestable("a"))
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	Uint := types.MakeConst(nameable.MakeTestable("Uint"))
+	Array_a := types.Apply[nameable.Testable](types.MakeConst(nameable.MakeTestable("Array")), a)
+	aToA := types.Apply[nameable.Testable](arrow, a, a)
+	IntToInt := types.Apply[nameable.Testable](arrow, Int, Int)
+	// (Array a; n)
+	n_Uint := types.Judgment(expr.Referable[nameable.Testable](n), types.Type[nameable.Testable](Uint))
+	domain := []types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]]{n_Uint}
+	Array_a_n := types.Index(Array_a, domain...)
+	// forall a . mapval (n: Uint) . (Array a; n) -> (Array a; n)
+	arrayId := types.Forall(a).Bind(
+		types.Map(types.Judgment[nameable.Testable, expr.Variable[nameable.Testable]](n, Uint)).
+			To(types.Apply[nameable.Testable](arrow, Array_a_n, Array_a_n)),
+	)
+
+	r := types.Var(nameable.MakeTestable("r"))
+	label := nameable.MakeTestable("name")
+	// {name: Int | r}
+	hasName := types.RecordOf([]types.FieldType[nameable.Testable]{types.Field[nameable.Testable](label, Int)}, types.Monotyped[nameable.Testable](r))
+	getName := expr.Bind[nameable.Testable](x).In(expr.AccessField[nameable.Testable](x, label))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		against     types.Type[nameable.Testable]
+		expect      Status
+	}{
+		{
+			`(\x -> x) <= forall a . a -> a`,
+			idFunc,
+			types.Forall(a).Bind(aToA),
+			Ok,
+		},
+		{
+			`(\x -> x) <= Int -> Int`,
+			idFunc,
+			IntToInt,
+			Ok,
+		},
+		{
+			`(\x -> x) <= forall a . mapval (n: Uint) . (Array a; n) -> (Array a; n)`,
+			idFunc,
+			arrayId,
+			Ok,
+		},
+		{
+			`let id = (\x -> x) in id <= Int -> Int`,
+			expr.Let[nameable.Testable](id, idFunc, id),
+			IntToInt,
+			Ok,
+		},
+		{
+			`select Just 0 when Just y -> y <= Int`,
+			expr.Select[nameable.Testable](
+				expr.Apply[nameable.Testable](just, zero),
+				expr.Bind(y).InCase(expr.Apply[nameable.Testable](just, y), y),
+			),
+			Int,
+			Ok,
+		},
+		{
+			`(\x -> x.name) <= forall r . {name: Int | r} -> Int`,
+			getName,
+			types.Forall(r).Bind(types.Apply[nameable.Testable](arrow, hasName, Int)),
+			Ok,
+		},
+		{
+			`(\x -> x) <= forall r . {name: Int | r} -> {r}`,
+			idFunc,
+			types.Forall(r).Bind(types.Apply[nameable.Testable](arrow, hasName, types.RecordOf[nameable.Testable](nil, r))),
+			MissingLabel,
+		},
+		{
+			`(\x -> 0) <= forall a . a -> a`,
+			constZero,
+			types.Forall(a).Bind(aToA),
+			ConstantMismatch,
+		},
+		{
+			`(\x -> x) <= Int`,
+			idFunc,
+			Int,
+			ConstantMismatch,
+		},
+		{
+			`((\x -> 0): forall a . a -> a) <= Int -> Int`,
+			bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](constZero, types.Forall(a).Bind(aToA)),
+			IntToInt,
+			ConstantMismatch,
+		},
+		{
+			`((\x -> x): forall a . a -> a) <= Int -> Int`,
+			bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](idFunc, types.Forall(a).Bind(aToA)),
+			IntToInt,
+			Ok,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeDriverTestContext()
+		actual := cxt.Check(test.input, test.against)
+		if !actual.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, actual.Status, i))
+		}
+
+		if test.expect.IsOk() && cxt.HasErrors() {
+			t.Fatal(testutil.Testing("errors", test.description).FailMessage(nil, cxt.GetReports(), i))
+		}
+	}
+}
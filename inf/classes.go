@@ -0,0 +1,546 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: type classes--class and instance declarations, and the
+// resolution of class constraints into dictionaries
+//
+// Notes: overloaded names are elaborated into dictionary-passing style: a name
+// of type `forall a . C a => t` is applied to a placeholder for a dictionary
+// proving `C a`; placeholders are replaced w/ dictionaries (instances,
+// superclass selections, or dictionary parameters) when their constraints are
+// resolved
+// =============================================================================
+package inf
+
+import (
+	"strconv"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// class declaration:
+//
+//	class (S1 a1 .., .., SK a1 ..) => C a1 .. aN
+type class[N nameable.Nameable] struct {
+	name    N
+	params  []types.Variable[N]
+	supers  []types.Predicate[N]
+	methods []expr.Const[N]
+}
+
+// returns true iff `c` and `other` declare the same class
+func (c class[N]) equals(other class[N]) bool {
+	if c.name.GetName() != other.name.GetName() || len(c.params) != len(other.params) || len(c.supers) != len(other.supers) {
+		return false
+	}
+	for i, param := range c.params {
+		if !param.Equals(other.params[i]) {
+			return false
+		}
+	}
+	for i, super := range c.supers {
+		if !super.Equals(other.supers[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// instance declaration:
+//
+//	instance (P1, .., PK) => C t1 .. tN
+//
+// `name` names the dictionary for the instance; when the instance has a
+// context, `name` is a function from dictionaries for P1, .., PK
+type instance[N nameable.Nameable] struct {
+	name    expr.Const[N]
+	binders []types.Variable[N]
+	context []types.Predicate[N]
+	head    types.Predicate[N]
+}
+
+// class constraint waiting to be resolved; `evidence` is the placeholder for
+// the dictionary that proves `pred`
+type constraint[N nameable.Nameable] struct {
+	evidence expr.Const[N]
+	pred     types.Predicate[N]
+}
+
+// class constraint assumed to hold; `evidence` is the dictionary that proves
+// `pred`
+type assumption[N nameable.Nameable] struct {
+	evidence expr.Expression[N]
+	pred     types.Predicate[N]
+}
+
+// returns name of the function that selects the dictionary for the
+// `index`-th superclass of `c` from a dictionary for `c`, e.g., "$Ord.Eq"
+func (cxt *Context[N]) superSelector(c class[N], index int) expr.Const[N] {
+	name := "$" + c.name.GetName() + "." + c.supers[index].GetClass().GetName()
+	for _, super := range c.supers[:index] {
+		if super.GetClass().GetName() == c.supers[index].GetClass().GetName() {
+			name = name + "." + strconv.Itoa(index)
+			break
+		}
+	}
+	return expr.MakeConst(cxt.TypeContext.Con(name).GetReferred())
+}
+
+// adds a class named `name` to the context along w/ its methods. Each method
+// `methods[i]` is given the type
+//
+//	forall a1 .. aN b1 .. bM . C a1 .. aN => methodTypes[i]
+//
+// where a1 .. aN are `params` and b1 .. bM are the other free variables of
+// `methodTypes[i]`
+func (cxt *Context[N]) AddClass(name N, params []types.Variable[N], supers []types.Predicate[N], methods []expr.Const[N], methodTypes []types.DependentTyped[N]) Status {
+	if len(methods) != len(methodTypes) {
+		panic("illegal arguments: len(methods) != len(methodTypes)")
+	}
+
+	if _, found := cxt.classes.Get(name); found {
+		cxt.appendReport(makeNameReport("Class", ClassRedef, expr.MakeConst(name)))
+		return ClassRedef
+	}
+
+	for _, super := range supers {
+		if _, found := cxt.classes.Get(super.GetClass()); !found {
+			cxt.appendReport(makeNameReport("Class", UndefinedClass, expr.MakeConst(super.GetClass())))
+			return UndefinedClass
+		}
+	}
+
+	// methods are checked before anything is added, so a class that cannot be
+	// added leaves the context unchanged
+	declared := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if _, found := cxt.lookup(method.Name); found || declared[method.Name.GetName()] {
+			cxt.appendReport(makeNameReport("Declare Name", IllegalShadow, method))
+			return IllegalShadow
+		}
+		declared[method.Name.GetName()] = true
+	}
+
+	cxt.classes.Add(name, class[N]{name, params, supers, methods})
+	cxt.onRollback(func() { cxt.classes.Remove(name) })
+
+	paramTypes := make([]types.Monotyped[N], len(params))
+	for i, param := range params {
+		paramTypes[i] = param
+	}
+	pred := types.Pred(name, paramTypes...)
+	for i, method := range methods {
+		free := uniqueVariables(append(append([]types.Variable[N]{}, params...), methodTypes[i].GetFreeVariables()...))
+		sigma := types.Forall(free...).Bind(DependentGeneralization(methodTypes[i]))
+		cxt.Add(method, types.Qualify(sigma, pred))
+	}
+	return Ok
+}
+
+// adds an instance of a class to the context. The dictionary for the instance
+// is named `name`. `head` is the predicate the instance proves, `context` the
+// predicates it requires to prove `head`, and `binders` the variables free in
+// both.
+//
+// Each superclass of the instance's class must already have an instance that
+// proves it
+func (cxt *Context[N]) AddInstance(name N, binders []types.Variable[N], context []types.Predicate[N], head types.Predicate[N]) Status {
+	c, found := cxt.classes.Get(head.GetClass())
+	if !found {
+		cxt.appendReport(makeNameReport("Instance", UndefinedClass, expr.MakeConst(head.GetClass())))
+		return UndefinedClass
+	}
+
+	if len(head.GetParams()) != len(c.params) {
+		cxt.appendReport(makeNameReport("Instance", ParamLengthMismatch, expr.MakeConst(head.GetClass())))
+		return ParamLengthMismatch
+	}
+
+	inst := instance[N]{expr.MakeConst(name), binders, context, head}
+	instances, _ := cxt.instances.Get(head.GetClass())
+	for _, other := range instances {
+		if cxt.overlaps(inst, other) {
+			cxt.appendReport(makeNameReport("Instance", InstanceRedef, inst.name, other.name))
+			return InstanceRedef
+		}
+	}
+
+	// superclasses must be entailed by the instance's context and the
+	// instances that already exist
+	assumptions := make([]assumption[N], len(context))
+	for i, p := range context {
+		_, ev := cxt.freshName()
+		assumptions[i] = assumption[N]{ev, p}
+	}
+	for _, super := range c.supers {
+		p := super.ReplaceDependent(c.params, head.GetParams())
+		if _, entailed := cxt.entails(assumptions, p); !entailed {
+			cxt.appendReport(makePredicateReport("Instance", NoInstance, p))
+			return NoInstance
+		}
+	}
+
+	cxt.instances.Add(head.GetClass(), append(instances, inst))
+	cxt.onRollback(func() { cxt.instances.Add(head.GetClass(), instances) })
+	return Ok
+}
+
+// instantiates the variables bound by `inst` w/ new variables
+func (cxt *Context[N]) instantiateInstance(inst instance[N]) (context []types.Predicate[N], head types.Predicate[N]) {
+	vs := make([]types.Monotyped[N], len(inst.binders))
+	for i := range vs {
+		vs[i] = cxt.TypeContext.NewVar()
+	}
+	context = make([]types.Predicate[N], len(inst.context))
+	for i, p := range inst.context {
+		context[i] = p.ReplaceDependent(inst.binders, vs)
+	}
+	return context, inst.head.ReplaceDependent(inst.binders, vs)
+}
+
+// returns true iff some predicate is proven by both `a` and `b`
+func (cxt *Context[N]) overlaps(a, b instance[N]) bool {
+	snap := cxt.Snapshot()
+	defer cxt.Rollback(snap)
+
+	_, headA := cxt.instantiateInstance(a)
+	_, headB := cxt.instantiateInstance(b)
+	paramsA, paramsB := headA.GetParams(), headB.GetParams()
+	for i := range paramsA {
+		if cxt.Unify(paramsA[i], paramsB[i]).NotOk() {
+			return false
+		}
+	}
+	return true
+}
+
+// matches `pattern`, whose variables are all in `binders`, against `t`.
+// Variables in `t` are only matched by variables in `pattern`
+func match[N nameable.Nameable](binders map[string]types.Monotyped[N], pattern, t types.Monotyped[N]) bool {
+	if v, ok := pattern.(types.Variable[N]); ok {
+		if m, isBinder := binders[v.GetName()]; isBinder {
+			if m == nil {
+				binders[v.GetName()] = t
+				return true
+			}
+			return m.Equals(t)
+		}
+	}
+
+	if IsVariable(t) {
+		return false
+	}
+
+	cp, paramsOfP, _ := Split(pattern)
+	ct, paramsOfT, _ := Split(t)
+	if cp != ct || len(paramsOfP) != len(paramsOfT) {
+		return false
+	}
+	for i := range paramsOfP {
+		if !match(binders, paramsOfP[i], paramsOfT[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// searches for an instance that proves `p`; returns the predicates the
+// instance requires to prove `p`
+func (cxt *Context[N]) findInstance(p types.Predicate[N]) (inst instance[N], context []types.Predicate[N], found bool) {
+	instances, _ := cxt.instances.Get(p.GetClass())
+	for _, inst = range instances {
+		binders := make(map[string]types.Monotyped[N], len(inst.binders))
+		for _, v := range inst.binders {
+			binders[v.GetName()] = nil
+		}
+
+		params := inst.head.GetParams()
+		found = true
+		for i := 0; found && i < len(params); i++ {
+			found = match(binders, params[i], p.GetParams()[i])
+		}
+		if !found {
+			continue
+		}
+
+		with := make([]types.Monotyped[N], len(inst.binders))
+		for i, v := range inst.binders {
+			if with[i] = binders[v.GetName()]; with[i] == nil {
+				// not determined by head, so any type will do
+				with[i] = cxt.TypeContext.NewVar()
+			}
+		}
+		context = make([]types.Predicate[N], len(inst.context))
+		for i, q := range inst.context {
+			context[i] = q.ReplaceDependent(inst.binders, with)
+		}
+		return inst, context, true
+	}
+	return inst, nil, false
+}
+
+// returns the superclasses of the predicate of `a`, each w/ evidence selected
+// from the evidence of `a`
+func (cxt *Context[N]) superAssumptions(a assumption[N]) []assumption[N] {
+	c, _ := cxt.classes.Get(a.pred.GetClass())
+	supers := make([]assumption[N], len(c.supers))
+	for i, super := range c.supers {
+		evidence := expr.Apply[N](cxt.superSelector(c, i), a.evidence)
+		supers[i] = assumption[N]{evidence, super.ReplaceDependent(c.params, a.pred.GetParams())}
+	}
+	return supers
+}
+
+// returns evidence for `p` built from the evidence of `assumptions` or of
+// their superclasses
+func (cxt *Context[N]) byAssumption(assumptions []assumption[N], p types.Predicate[N]) (evidence expr.Expression[N], found bool) {
+	for _, a := range assumptions {
+		if a.pred.Map(cxt.GetSub).Equals(p) {
+			return a.evidence, true
+		}
+		if evidence, found = cxt.byAssumption(cxt.superAssumptions(a), p); found {
+			return evidence, true
+		}
+	}
+	return nil, false
+}
+
+// returns evidence for `p`, or false if `p` cannot be proven from
+// `assumptions` and the instances in the context
+func (cxt *Context[N]) entails(assumptions []assumption[N], p types.Predicate[N]) (evidence expr.Expression[N], entailed bool) {
+	p = p.Map(cxt.GetSub)
+	if evidence, entailed = cxt.byAssumption(assumptions, p); entailed {
+		return
+	}
+
+	inst, context, found := cxt.findInstance(p)
+	if !found {
+		return nil, false
+	}
+
+	evidence = inst.name
+	for _, q := range context {
+		var ev expr.Expression[N]
+		if ev, entailed = cxt.entails(assumptions, q); !entailed {
+			return nil, false
+		}
+		evidence = expr.Apply(evidence, ev)
+	}
+	return evidence, true
+}
+
+// instantiates `q`; each predicate of the instance becomes a wanted
+// constraint. Returns the instance, the new type variables that replaced the
+// variables `q` binds, and the placeholders for the dictionaries proving its
+// predicates
+func (cxt *Context[N]) instQualified(q types.Qualified[N]) (t types.Monotyped[N], vs []types.Monotyped[N], placeholders []expr.Expression[N]) {
+	binders := q.GetBinders()
+	vs = make([]types.Monotyped[N], len(binders))
+	for i := range vs {
+		vs[i] = cxt.TypeContext.NewVar()
+	}
+
+	var bound types.DependentTyped[N] = q.GetBound()
+	if d, ok := bound.(types.DependentType[N]); ok {
+		bound = d.FreeIndex(cxt.ExprContext)
+	}
+	t = bound.ReplaceDependent(binders, vs)
+
+	placeholders = make([]expr.Expression[N], len(q.GetContext()))
+	for i, p := range q.GetContext() {
+		placeholders[i] = cxt.want(p.ReplaceDependent(binders, vs))
+	}
+	return t, vs, placeholders
+}
+
+// records that `p` must hold; returns the placeholder for its evidence
+func (cxt *Context[N]) want(p types.Predicate[N]) expr.Const[N] {
+	_, ev := cxt.freshName()
+	old := cxt.wanted
+	cxt.wanted = append(cxt.wanted, constraint[N]{ev, p})
+	cxt.onRollback(func() { cxt.wanted = old })
+	return ev
+}
+
+// declares that the placeholder `ev` is replaced by `evidence`
+func (cxt *Context[N]) prove(ev expr.Const[N], evidence expr.Expression[N]) {
+	key := ev.Name.GetName()
+	cxt.evidence[key] = evidence
+	cxt.onRollback(func() { delete(cxt.evidence, key) })
+}
+
+// replaces each occurrence of `c` in `e` w/ `with`
+func (cxt *Context[N]) substituteConst(e expr.Expression[N], c expr.Const[N], with expr.Expression[N]) expr.Expression[N] {
+	v := cxt.ExprContext.NewVar()
+	return expr.Bind(v).In(e.BodyAbstract(v, c)).Instantiate(with)
+}
+
+// replaces each placeholder in `e` w/ the evidence proving it
+func (cxt *Context[N]) applyEvidence(e expr.Expression[N]) expr.Expression[N] {
+	for again := len(cxt.evidence) != 0; again; {
+		again = false
+		replaced := make(map[string]bool)
+		for _, name := range e.Collect() {
+			key := name.GetName()
+			if evidence, found := cxt.evidence[key]; found && !replaced[key] {
+				replaced[key], again = true, true
+				e = cxt.substituteConst(e, expr.MakeConst(name), evidence)
+			}
+		}
+	}
+	return e
+}
+
+// returns true iff the predicate is on a variable, e.g., `C a` or `C (a b)`,
+// i.e., no instance can be selected for it until more is known
+func headedByVariable[N nameable.Nameable](p types.Predicate[N]) bool {
+	for _, param := range p.GetParams() {
+		if IsVariable(param) {
+			return true
+		}
+		if app, ok := param.(types.Application[N]); ok {
+			if IsVariable(app.GetHead()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func asAssumptions[N nameable.Nameable](context []constraint[N]) []assumption[N] {
+	out := make([]assumption[N], len(context))
+	for i, c := range context {
+		out[i] = assumption[N]{c.evidence, c.pred}
+	}
+	return out
+}
+
+// resolves wanted constraints after the types of a binding group have been
+// generalized to `sigmas`:
+//   - constraints proven by instances are replaced by the instances
+//   - constraints on variables bound by `sigmas` are assumed, becoming the
+//     returned context of the group
+//   - the rest are left for an enclosing binding unless `final` is true, in
+//     which case they are ambiguous
+//
+// Constraints in the returned context are simplified: each is unique and none
+// is a superclass of another. When a constraint cannot be resolved, it and
+// the constraints not yet resolved stay wanted
+func (cxt *Context[N]) resolveWanted(sigmas []types.Polytype[N], final bool) (context []constraint[N], stat Status) {
+	bound := make(map[string]bool)
+	for _, sigma := range sigmas {
+		for _, v := range sigma.GetBinders() {
+			bound[v.GetName()] = true
+		}
+	}
+
+	old, level := cxt.wanted, cxt.TypeContext.GetLevel()
+	// replaces the wanted constraints w/ those still `remaining`
+	keepWanted := func(remaining []constraint[N]) {
+		cxt.wanted = remaining
+		cxt.onRollback(func() { cxt.wanted = old })
+	}
+
+	queue, deferred := append([]constraint[N]{}, cxt.wanted...), []constraint[N]{}
+	// constraints that are not resolved stay wanted when `c` cannot be
+	// resolved
+	fail := func(c constraint[N]) {
+		remaining := append(append([]constraint[N]{}, deferred...), context...)
+		keepWanted(append(append(remaining, c), queue...))
+	}
+	for len(queue) != 0 {
+		c := queue[0]
+		queue = queue[1:]
+		p := c.pred.Map(cxt.GetSub)
+
+		if ev, found := cxt.byAssumption(asAssumptions(context), p); found {
+			cxt.prove(c.evidence, ev)
+			continue
+		}
+
+		if inst, sub, found := cxt.findInstance(p); found {
+			var ev expr.Expression[N] = inst.name
+			for _, q := range sub {
+				_, placeholder := cxt.freshName()
+				queue = append(queue, constraint[N]{placeholder, q})
+				ev = expr.Apply(ev, expr.Expression[N](placeholder))
+			}
+			cxt.prove(c.evidence, ev)
+			continue
+		}
+
+		if !headedByVariable(p) {
+			fail(c)
+			cxt.appendReport(makePredicateReport("Gen", NoInstance, p))
+			return nil, NoInstance
+		}
+
+		isBound := false
+		for _, v := range p.GetFreeVariables() {
+			isBound = isBound || bound[v.GetName()]
+		}
+		switch ambiguous := cxt.isAmbiguousIn(p, bound, level); {
+		case isBound && !ambiguous:
+			context = append(context, constraint[N]{c.evidence, p})
+		case !isBound && !ambiguous && !final:
+			deferred = append(deferred, c)
+		default:
+			fail(c)
+			cxt.appendReport(makePredicateReport("Gen", AmbiguousConstraint, p))
+			return nil, AmbiguousConstraint
+		}
+	}
+
+	// remove constraints entailed by the superclasses of other constraints
+	for i := 0; i < len(context); {
+		others := append(append([]constraint[N]{}, context[:i]...), context[i+1:]...)
+		if ev, found := cxt.byAssumption(asAssumptions(others), context[i].pred); found {
+			cxt.prove(context[i].evidence, ev)
+			context = others
+		} else {
+			i++
+		}
+	}
+
+	keepWanted(deferred)
+	return context, Ok
+}
+
+// returns true iff some variable of `p` is neither bound by the binding group
+// being generalized nor reachable from outside of it
+func (cxt *Context[N]) isAmbiguousIn(p types.Predicate[N], bound map[string]bool, level uint32) bool {
+	for _, v := range p.GetFreeVariables() {
+		if !bound[v.GetName()] && cxt.levelOf(v) > level {
+			return true
+		}
+	}
+	return false
+}
+
+// abstracts `e` over the evidence for `context` after replacing all
+// placeholders in `e` w/ their evidence
+//
+//	λd1 . .. λdK . e
+func (cxt *Context[N]) abstractEvidence(context []constraint[N], e expr.Expression[N]) expr.Expression[N] {
+	e = cxt.applyEvidence(e)
+	for i := len(context) - 1; i >= 0; i-- {
+		v := cxt.ExprContext.NewVar()
+		e = expr.Bind(v).In(e.BodyAbstract(v, context[i].evidence))
+	}
+	return e
+}
+
+// qualifies `sigma` w/ the predicates of `context`; returns `sigma` if
+// `context` is empty
+func qualify[N nameable.Nameable](sigma types.Polytype[N], context []constraint[N]) types.Type[N] {
+	if len(context) == 0 {
+		return sigma
+	}
+	preds := make([]types.Predicate[N], len(context))
+	for i, c := range context {
+		preds[i] = c.pred
+	}
+	return types.Qualify(sigma, preds...)
+}
@@ -0,0 +1,348 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// creates a context w/
+//
+//	0: Int
+//	true: Bool
+//	and: Bool -> Bool -> Bool
+//	class Num a where (+): a -> a -> a
+//	class Eq a where (==): a -> a -> Bool
+//	class Eq a => Ord a where (<): a -> a -> Bool
+//	instance Num Int
+//	instance Eq Int
+//	instance Ord Int
+//	instance Eq a => Eq [a]
+func makeClassTestContext() *Context[nameable.Testable] {
+	cxt := NewTestableContext()
+	name := nameable.MakeTestable
+	Int := types.MakeConst(name("Int"))
+	Bool := types.MakeConst(name("Bool"))
+	a := types.Var(name("a"))
+	cxt.Add(expr.MakeConst(name("0")), Int)
+	cxt.Add(expr.MakeConst(name("true")), Bool)
+	cxt.Add(expr.MakeConst(name("and")), cxt.TypeContext.Function(Bool, cxt.TypeContext.Function(Bool, Bool)))
+
+	params := []types.Variable[nameable.Testable]{a}
+	binary := cxt.TypeContext.Function(a, cxt.TypeContext.Function(a, a))
+	compare := cxt.TypeContext.Function(a, cxt.TypeContext.Function(a, Bool))
+	method := func(m string) []expr.Const[nameable.Testable] {
+		return []expr.Const[nameable.Testable]{expr.MakeConst(name(m))}
+	}
+	methodType := func(t types.DependentTyped[nameable.Testable]) []types.DependentTyped[nameable.Testable] {
+		return []types.DependentTyped[nameable.Testable]{t}
+	}
+	cxt.AddClass(name("Num"), params, nil, method("+"), methodType(binary))
+	cxt.AddClass(name("Eq"), params, nil, method("=="), methodType(compare))
+	eqA := types.Pred[nameable.Testable](name("Eq"), a)
+	cxt.AddClass(name("Ord"), params, []types.Predicate[nameable.Testable]{eqA}, method("<"), methodType(compare))
+
+	cxt.AddInstance(name("$NumInt"), nil, nil, types.Pred[nameable.Testable](name("Num"), Int))
+	cxt.AddInstance(name("$EqInt"), nil, nil, types.Pred[nameable.Testable](name("Eq"), Int))
+	cxt.AddInstance(name("$OrdInt"), nil, nil, types.Pred[nameable.Testable](name("Ord"), Int))
+	listA := types.Apply[nameable.Testable](cxt.TypeContext.EnclosingCon(1, "[]"), a)
+	eqListA := types.Pred[nameable.Testable](name("Eq"), listA)
+	cxt.AddInstance(name("$EqList"), params, []types.Predicate[nameable.Testable]{eqA}, eqListA)
+	return cxt
+}
+
+func TestElaborate(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x, y := expr.Var(name("x")), expr.Var(name("y"))
+	plus, eq, lt, and := c("+"), c("=="), c("<"), c("and")
+	zero, true_ := c("0"), c("true")
+	double := expr.Bind(x).In(expr.Apply[nameable.Testable](plus, x, x))
+	equal := expr.Bind(x, y).In(expr.Apply[nameable.Testable](eq, x, y))
+	a := types.Var(name("a"))
+	// forall a . P => a -> a -> Bool
+	comparison := func(ps ...types.Predicate[nameable.Testable]) types.Type[nameable.Testable] {
+		arrow := types.MakeInfixConst[nameable.Testable](name("->"))
+		sigma := types.Forall(a).Bind(types.Apply[nameable.Testable](arrow, a, types.Apply[nameable.Testable](arrow, a, types.MakeConst(name("Bool")))))
+		if len(ps) == 0 {
+			return sigma
+		}
+		return types.Qualify(sigma, ps...)
+	}
+	annotate := func(e expr.Expression[nameable.Testable], ty types.Type[nameable.Testable]) expr.Expression[nameable.Testable] {
+		return bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](e, ty)
+	}
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		stat        Status
+		expect      string
+		elaborated  string
+	}{
+		{
+			`+ 0 0`,
+			expr.Apply[nameable.Testable](plus, zero, zero),
+			Ok,
+			"Int",
+			"(((+ $NumInt) 0) 0)",
+		},
+		{
+			`λx . + x x`,
+			double,
+			Ok,
+			"forall $0 . Num $0 => ($0 -> $0)",
+			"(λ$2 . (λx . (((+ $2) x) x)))",
+		},
+		{
+			`λx y . and (== x y) (< x y)`,
+			expr.Bind(x, y).In(expr.Apply[nameable.Testable](and,
+				expr.Apply[nameable.Testable](eq, x, y),
+				expr.Apply[nameable.Testable](lt, x, y),
+			)),
+			Ok,
+			"forall $0 . Ord $0 => ($0 -> ($0 -> Bool))",
+			"(λ$4 . (λx . (λy . ((and (((== ($Ord.Eq $4)) x) y)) (((< $4) x) y)))))",
+		},
+		{
+			`== [0] [0]`,
+			expr.Apply[nameable.Testable](eq, expr.List[nameable.Testable]{zero}, expr.List[nameable.Testable]{zero}),
+			Ok,
+			"Bool",
+			"(((== ($EqList $EqInt)) [0]) [0])",
+		},
+		{
+			`let double = λx . + x x in double 0`,
+			expr.Let[nameable.Testable](c("double"), double, expr.Apply[nameable.Testable](c("double"), zero)),
+			Ok,
+			"Int",
+			"let double = (λ$2 . (λx . (((+ $2) x) x))) in ((double $NumInt) 0)",
+		},
+		{
+			`rec f = λx . + x (f x) in f`,
+			expr.Rec[nameable.Testable](expr.Declare(name("f")).Instantiate(
+				expr.Bind(x).In(expr.Apply[nameable.Testable](plus, x, expr.Apply[nameable.Testable](c("f"), x))),
+			))(c("f")),
+			Ok,
+			"forall $7 . Num $7 => ($7 -> $7)",
+			"(λ$5 . rec f = (λ$3 . (λx . (((+ $3) x) ((f $3) x)))) in (f $5))",
+		},
+		{
+			`(λx y . == x y): forall a . Eq a => a -> a -> Bool`,
+			annotate(equal, comparison(types.Pred[nameable.Testable](name("Eq"), a))),
+			Ok,
+			"forall $4 . Eq $4 => ($4 -> ($4 -> Bool))",
+			"(λ$6 . (((λ$4 . (λx . (λy . (((== $4) x) y)))): forall a . Eq a => (a -> (a -> Bool))) $6))",
+		},
+		{
+			`(λx y . == x y): forall a . Ord a => a -> a -> Bool`,
+			annotate(equal, comparison(types.Pred[nameable.Testable](name("Ord"), a))),
+			Ok,
+			"forall $4 . Ord $4 => ($4 -> ($4 -> Bool))",
+			"(λ$6 . (((λ$4 . (λx . (λy . (((== ($Ord.Eq $4)) x) y)))): forall a . Ord a => (a -> (a -> Bool))) $6))",
+		},
+		{
+			`(λx y . == x y): forall a . a -> a -> Bool`,
+			annotate(equal, comparison()),
+			NoInstance,
+			"",
+			"",
+		},
+		{
+			`+ true true`,
+			expr.Apply[nameable.Testable](plus, true_, true_),
+			NoInstance,
+			"",
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeClassTestContext()
+		e, ty, reports := cxt.Elaborate(test.input)
+		if test.stat.IsOk() {
+			if len(reports) != 0 {
+				t.Fatal(testutil.Testing("reports", test.description).FailMessage(nil, reports, i))
+			}
+			if ty.String() != test.expect {
+				t.Fatal(testutil.Testing("type", test.description).FailMessage(test.expect, ty.String(), i))
+			}
+			if e.String() != test.elaborated {
+				t.Fatal(testutil.Testing("elaboration", test.description).FailMessage(test.elaborated, e.String(), i))
+			}
+		} else if len(reports) != 1 || !reports[0].Status.Is(test.stat) {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(test.stat, reports, i))
+		}
+	}
+}
+
+func TestCheckQualified(t *testing.T) {
+	name := nameable.MakeTestable
+	x, y := expr.Var(name("x")), expr.Var(name("y"))
+	a := types.Var(name("a"))
+	arrow := types.MakeInfixConst[nameable.Testable](name("->"))
+	// forall a . P => a -> a -> Bool
+	comparison := func(class string) types.Type[nameable.Testable] {
+		sigma := types.Forall(a).Bind(types.Apply[nameable.Testable](arrow, a, types.Apply[nameable.Testable](arrow, a, types.MakeConst(name("Bool")))))
+		return types.Qualify(sigma, types.Pred[nameable.Testable](name(class), a))
+	}
+	equal := expr.Bind(x, y).In(expr.Apply[nameable.Testable](expr.MakeConst(name("==")), x, y))
+
+	tests := []struct {
+		description string
+		against     types.Type[nameable.Testable]
+		expect      string
+	}{
+		{
+			`(λx y . == x y) <= forall a . Eq a => a -> a -> Bool`,
+			comparison("Eq"),
+			"(λ$4 . (λx . (λy . (((== $4) x) y))))",
+		},
+		{
+			`(λx y . == x y) <= forall a . Ord a => a -> a -> Bool`,
+			comparison("Ord"),
+			"(λ$4 . (λx . (λy . (((== ($Ord.Eq $4)) x) y))))",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeClassTestContext()
+		actual := cxt.Check(equal, test.against)
+		if actual.NotOk() || cxt.HasErrors() {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(nil, cxt.GetReports(), i))
+		}
+		// every dictionary is a parameter; no placeholder is left wanted
+		if len(cxt.wanted) != 0 {
+			t.Fatal(testutil.Testing("wanted", test.description).FailMessage(nil, cxt.wanted, i))
+		}
+		if e := actual.Judgment().GetExpression().String(); e != test.expect {
+			t.Fatal(testutil.Testing("elaboration", test.description).FailMessage(test.expect, e, i))
+		}
+	}
+}
+
+func TestAddInstance(t *testing.T) {
+	name := nameable.MakeTestable
+	Int := types.MakeConst(name("Int"))
+	Bool := types.MakeConst(name("Bool"))
+
+	tests := []struct {
+		description string
+		head        types.Predicate[nameable.Testable]
+		expect      Status
+	}{
+		{`instance Num Bool`, types.Pred[nameable.Testable](name("Num"), Bool), Ok},
+		{`instance Num Int`, types.Pred[nameable.Testable](name("Num"), Int), InstanceRedef},
+		{`instance Ord Bool`, types.Pred[nameable.Testable](name("Ord"), Bool), NoInstance},
+		{`instance Show Int`, types.Pred[nameable.Testable](name("Show"), Int), UndefinedClass},
+	}
+
+	for i, test := range tests {
+		cxt := makeClassTestContext()
+		actual := cxt.AddInstance(name("$instance"), nil, nil, test.head)
+		if !actual.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestElaborateData(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	a, x := types.Var(name("a")), expr.Var(name("x"))
+	arrow := types.MakeInfixConst[nameable.Testable](name("->"))
+	compare := types.Apply[nameable.Testable](arrow, a, types.Apply[nameable.Testable](arrow, a, types.MakeConst(name("Bool"))))
+	// forall a . Eq a => a -> a -> Bool
+	sigma := types.Qualify(types.Forall(a).Bind(compare), types.Pred[nameable.Testable](name("Eq"), a))
+
+	cxt := makeClassTestContext()
+	cxt.AddType(name("Maybe"), types.Apply[nameable.Testable](types.MakeConst(name("Maybe")), a))
+	cxt.AddConstructorFor(name("Maybe"), bridge.MakeData(c("Just"), bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, a)))
+
+	// Just ((==): forall a . Eq a => a -> a -> Bool)
+	input := bridge.MakeData(c("Just"), bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](c("=="), sigma))
+	e, ty, reports := cxt.Elaborate(input)
+	if len(reports) != 0 {
+		t.Fatal(testutil.Testing("reports").FailMessage(nil, reports, 0))
+	}
+	if expect := "forall $3 . Eq $3 => (Maybe ($3 -> ($3 -> Bool)))"; ty.String() != expect {
+		t.Fatal(testutil.Testing("type").FailMessage(expect, ty.String(), 0))
+	}
+	if expect := "(λ$8 . (Just ((((λ$6 . (== $6)): forall a . Eq a => (a -> (a -> Bool))) $8): ($3 -> ($3 -> Bool)))))"; e.String() != expect {
+		t.Fatal(testutil.Testing("elaboration").FailMessage(expect, e.String(), 0))
+	}
+}
+
+func TestAddClass(t *testing.T) {
+	name := nameable.MakeTestable
+	a := types.Var(name("a"))
+	show := types.Apply[nameable.Testable](types.MakeInfixConst[nameable.Testable](name("->")), a, types.MakeConst(name("String")))
+	params := []types.Variable[nameable.Testable]{a}
+
+	tests := []struct {
+		description string
+		class       string
+		supers      []types.Predicate[nameable.Testable]
+		methods     []string
+		expect      Status
+	}{
+		{`class Show a where show`, "Show", nil, []string{"show"}, Ok},
+		{`class Eq a where show`, "Eq", nil, []string{"show"}, ClassRedef},
+		{`class Read a => Show a where show`, "Show", []types.Predicate[nameable.Testable]{types.Pred[nameable.Testable](name("Read"), a)}, []string{"show"}, UndefinedClass},
+		{`class Show a where show, (==)`, "Show", nil, []string{"show", "=="}, IllegalShadow},
+		{`class Show a where show, show`, "Show", nil, []string{"show", "show"}, IllegalShadow},
+	}
+
+	for i, test := range tests {
+		cxt := makeClassTestContext()
+		methods := make([]expr.Const[nameable.Testable], len(test.methods))
+		methodTypes := make([]types.DependentTyped[nameable.Testable], len(test.methods))
+		for j, m := range test.methods {
+			methods[j], methodTypes[j] = expr.MakeConst(name(m)), show
+		}
+
+		actual := cxt.AddClass(name(test.class), params, test.supers, methods, methodTypes)
+		if !actual.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, actual, i))
+		}
+
+		// a class that cannot be added adds neither itself nor its methods
+		_, found := cxt.Get(expr.MakeConst(name("show")))
+		if found != test.expect.Is(Ok) {
+			t.Fatal(testutil.Testing("method", test.description).FailMessage(test.expect.Is(Ok), found, i))
+		}
+		if test.class == "Show" {
+			if _, found := cxt.classes.Get(name("Show")); found != test.expect.Is(Ok) {
+				t.Fatal(testutil.Testing("class", test.description).FailMessage(test.expect.Is(Ok), found, i))
+			}
+		}
+	}
+}
+
+func TestResolveWantedFailure(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	equal := func(x expr.Expression[nameable.Testable]) expr.Expression[nameable.Testable] {
+		return expr.Apply[nameable.Testable](c("=="), x, x)
+	}
+
+	// and (== 0 0) (== true true)
+	input := expr.Apply[nameable.Testable](c("and"), equal(c("0")), equal(c("true")))
+	cxt := makeClassTestContext()
+	_, _, reports := cxt.Elaborate(input)
+	if len(reports) != 1 || !reports[0].Status.Is(NoInstance) {
+		t.Fatal(testutil.Testing("reports").FailMessage(NoInstance, reports, 0))
+	}
+
+	// `Eq Int` is proven by an instance; `Eq Bool` is still wanted
+	if len(cxt.wanted) != 1 {
+		t.Fatal(testutil.Testing("wanted").FailMessage(1, cxt.wanted, 0))
+	}
+	if expect, actual := "Eq Bool", cxt.wanted[0].pred.Map(cxt.GetSub).String(); actual != expect {
+		t.Fatal(testutil.Testing("wanted").FailMessage(expect, actual, 0))
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 type Conclusion[N nameable.Nameable, E expr.Expression[N], T types.Type[N]] struct {
 	judgment types.TypedJudgment[N, E, T]
+	// explicitly typed term the expression elaborates to; nil unless the
+	// conclusion was reached by the inference driver (see ElaborateCore)
+	core Core[N]
 	Status
 }
 
@@ -21,7 +24,7 @@ func (c Conclusion[N, E, T]) String() string {
 }
 
 func Conclude[N nameable.Nameable, E expr.Expression[N], T types.Type[N]](e E, t T) Conclusion[N, E, T] {
-	return Conclusion[N, E, T]{types.TypedJudge[N](e, t), Ok}
+	return Conclusion[N, E, T]{judgment: types.TypedJudge[N](e, t), Status: Ok}
 }
 
 func CannotConclude[N nameable.Nameable, E expr.Expression[N], T types.Type[N]](stat Status) Conclusion[N, E, T] {
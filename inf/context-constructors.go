@@ -16,11 +16,21 @@ import (
 
 // removes type from context
 func (cxt *Context[N]) RemoveType(name N) {
-	cxt.consTable.Remove(name)
+	if cj, removed := cxt.consTable.Remove(name); removed {
+		cxt.onRollback(func() { cxt.consTable.Add(name, cj) })
+	}
+}
+
+// adds constructors `cj` for type named `name` to context
+func (cxt *Context[N]) addConsJudge(name N, cj consJudge[N]) {
+	cxt.consTable.Add(name, cj)
+	cxt.onRollback(func() { cxt.consTable.Remove(name) })
 }
 
+// key of constructor that matches any constructor of a type
+const wildcardConstructorName string = "_"
+
 func (cxt *Context[N]) makeConsJudge(sigma types.Polytype[N]) consJudge[N] {
-	const wildcardConstructorName string = "_"
 	out := consJudge[N]{sigma, make(constructorMapType[N])}
 	wildcardConstructor := expr.Bind[N]().In(cxt.ExprContext.NewVar())
 	// (\-> $v): σ
@@ -51,11 +61,12 @@ func (cxt *Context[N]) AddType(name N, ty types.Type[N]) Status {
 		return TypeRedef
 	}
 
-	ty = cxt.Gen(ty)
+	sigma := cxt.Gen(ty)
 
-	constructors := cxt.makeConsJudge(ty.(types.Polytype[N]))
+	constructors := cxt.makeConsJudge(sigma)
 
-	cxt.consTable.Add(name, constructors)
+	cxt.addConsJudge(name, constructors)
+	cxt.addTypeKind(name, sigma)
 	return Ok
 }
 
@@ -123,10 +134,23 @@ func (cxt *Context[N]) AddConstructorFor(typeName N, data bridge.Data[N]) Status
 		return ConstructorRedef
 	}
 
+	// members must have types that have values
+	memberTypes := fun.FMap(
+		data.Members,
+		func(member bridge.JudgmentAsExpression[N, expr.Expression[N]]) types.Type[N] {
+			t, _ := member.TypeAndExpr()
+			return t
+		},
+	)
+	if stat := cxt.checkMemberKinds(typeName, memberTypes); stat.NotOk() {
+		return stat
+	}
+
 	// get Yew type of type with the name that's the value of `typeName`
 	yewPolytype := constructors.GetType()
 	constructor := cxt.buildConstructor(data, yewPolytype)
 	key := data.GetTag().Name.GetName()
 	constructors.constructors[key] = constructor
+	cxt.onRollback(func() { delete(constructors.constructors, key) })
 	return Ok
 }
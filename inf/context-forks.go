@@ -0,0 +1,86 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: forking a Context into child contexts that can infer types
+// concurrently, and merging the children back into it
+//
+// Notes: children share the parent's tables of types, constructors, classes,
+// instances, modules, and definitions, and they find the parent's symbols
+// through the parent. None of these may change while children are held, so
+// only expressions--never declarations--are inferred in a child. Each child
+// has its own substitutions, levels, lacks constraints, wanted and index
+// constraints, evidence, and reports, and its variable counters are
+// interleaved w/ those of its siblings (see types.(*Context).Fork), so types
+// inferred by different children never share variables
+// =============================================================================
+package inf
+
+import "github.com/petersalex27/yew-packages/types"
+
+// returns `n` child contexts of `cxt`. The parent must not be used until the
+// children are given back w/ Merge, and each child must only be used by one
+// goroutine at a time
+func (cxt *Context[N]) Fork(n int) []*Context[N] {
+	typeContexts := cxt.TypeContext.Fork(n)
+	exprContexts := cxt.ExprContext.Fork(n)
+	children := make([]*Context[N], n)
+	for i := range children {
+		child := NewContext[N]()
+		child.parent = cxt
+		child.consTable = cxt.consTable
+		child.typeKinds = cxt.typeKinds
+		child.classes = cxt.classes
+		child.instances = cxt.instances
+		child.modules = cxt.modules
+		child.definitions = cxt.definitions
+		child.fuel = cxt.fuel
+		child.kindVars = cxt.kindVars
+		child.spans = append([]sourceSpan{}, cxt.spans...)
+		if cxt.interner != nil {
+			// interners are not safe for concurrent use
+			child.UseInterner()
+		}
+		child.TypeContext, child.ExprContext = typeContexts[i], exprContexts[i]
+		children[i] = child
+	}
+	return children
+}
+
+// gives back `children`, contexts returned by Fork, to `cxt`: their reports,
+// warnings, wanted and index constraints (w/ their substitutions applied), and
+// evidence are added to `cxt` in the order the children are given, and `cxt`
+// continues counting variables after the variables they created. Symbols added
+// to the children are not merged
+func (cxt *Context[N]) Merge(children ...*Context[N]) {
+	typeContexts := make([]*types.Context[N], len(children))
+	for i, child := range children {
+		typeContexts[i] = child.TypeContext
+		cxt.ExprContext.Merge(child.ExprContext)
+		if child.kindVars > cxt.kindVars {
+			cxt.kindVars = child.kindVars
+		}
+
+		cxt.reports = append(cxt.reports, child.reports...)
+		cxt.warnings = append(cxt.warnings, child.warnings...)
+
+		old := cxt.wanted
+		for _, c := range child.wanted {
+			cxt.wanted = append(cxt.wanted, constraint[N]{c.evidence, c.pred.Map(child.GetSub)})
+		}
+		cxt.onRollback(func() { cxt.wanted = old })
+
+		oldConstraints := cxt.indexConstraints
+		for _, c := range child.indexConstraints {
+			c := indexConstraint[N]{child.substituteKinds(c.a), child.substituteKinds(c.b), child.GetSub(c.t)}
+			cxt.indexConstraints = append(cxt.indexConstraints, c)
+		}
+		cxt.onRollback(func() { cxt.indexConstraints = oldConstraints })
+
+		for key, evidence := range child.evidence {
+			key := key
+			cxt.evidence[key] = evidence
+			cxt.onRollback(func() { delete(cxt.evidence, key) })
+		}
+	}
+	cxt.TypeContext.Merge(typeContexts...)
+}
@@ -0,0 +1,265 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: module registry and methods associated w/ importing modules into a
+// Context
+//
+// Notes: -
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/table"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// separates a qualifier from the name it qualifies, e.g., `M.x`
+const qualificationSeparator string = "."
+
+// stores exported contexts by module name
+type Modules[N nameable.Nameable] struct {
+	exports *table.Table[*ExportableContext[N]]
+}
+
+// creates an empty module registry
+func NewModules[N nameable.Nameable]() *Modules[N] {
+	mods := new(Modules[N])
+	mods.exports = table.NewTable[*ExportableContext[N]]()
+	return mods
+}
+
+// adds `ecxt` to the registry under its module name iff no module w/ that name
+// is already registered
+func (mods *Modules[N]) Register(ecxt *ExportableContext[N]) Status {
+	if _, found := mods.exports.Get(ecxt.name); found {
+		return ModuleRedef
+	}
+
+	mods.exports.Add(ecxt.name, ecxt)
+	return Ok
+}
+
+// tries to find the module named `moduleName`
+func (mods *Modules[N]) Lookup(moduleName N) (ecxt *ExportableContext[N], found bool) {
+	return mods.exports.Get(moduleName)
+}
+
+// returns name of exported module
+func (ecxt *ExportableContext[N]) GetName() N { return ecxt.name }
+
+// sets the module registry used by Import; contexts that share a registry can
+// import each other's exports
+func (cxt *Context[N]) UseModules(mods *Modules[N]) {
+	cxt.modules = mods
+}
+
+// returns the module registry used by Import
+func (cxt *Context[N]) GetModules() *Modules[N] {
+	return cxt.modules
+}
+
+// registers `ecxt` in the context's module registry
+func (cxt *Context[N]) RegisterModule(ecxt *ExportableContext[N]) Status {
+	stat := cxt.modules.Register(ecxt)
+	if stat.NotOk() {
+		cxt.appendReport(makeNameReport("Register Module", stat, expr.MakeConst(ecxt.name)))
+	}
+	return stat
+}
+
+// returns function that qualifies names according to `qualification`
+//
+//	NotQualified:   x => x
+//	NameQualified:  x => as.x
+//	FullyQualified: x => moduleName.x
+func (cxt *Context[N]) qualifier(qualification QualificationType, moduleName N, as N) func(N) N {
+	var prefix string
+	switch qualification {
+	case NotQualified:
+		return func(name N) N { return name }
+	case NameQualified:
+		prefix = as.GetName()
+	default:
+		prefix = moduleName.GetName()
+	}
+
+	return func(name N) N {
+		qualified := prefix + qualificationSeparator + name.GetName()
+		return cxt.TypeContext.Con(qualified).GetReferred()
+	}
+}
+
+// rewrites types exported by a module so they refer to the module's types by
+// the names they are imported under
+type requalifier[N nameable.Nameable] struct {
+	// qualified name of each type the module exports, by unqualified name
+	qualified map[string]N
+}
+
+// creates a requalifier for the types exported by `ecxt`
+func makeRequalifier[N nameable.Nameable](ecxt *ExportableContext[N], qualify func(N) N) requalifier[N] {
+	r := requalifier[N]{make(map[string]N)}
+	ecxt.consTable.ForEach(func(typeName nameable.Nameable, _ consJudge[N]) bool {
+		r.qualified[typeName.GetName()] = qualify(typeName.(N))
+		return true
+	})
+	return r
+}
+
+// returns `m` w/ each type constant exported by the module replaced by its
+// qualified name
+func (r requalifier[N]) monotype(m types.Monotyped[N]) types.Monotyped[N] {
+	keepKind := func(kind expr.Referable[N]) expr.Referable[N] { return kind }
+	switch t := m.(type) {
+	case types.Constant[N]:
+		if name, found := r.qualified[t.GetName()]; found {
+			return types.MakeConst(name)
+		}
+	case types.TypeFunction[N]:
+		return t.Rebuild(r.monotype, keepKind)
+	case types.Nested[N]:
+		return t.Rebuild(r.monotype, keepKind)
+	case types.Record[N]:
+		return t.Rebuild(r.monotype, keepKind)
+	}
+	return m
+}
+
+func (r requalifier[N]) polytype(sigma types.Polytype[N]) types.Polytype[N] {
+	return types.Forall(sigma.GetBinders()...).Bind(r.dependent(sigma.GetBound()))
+}
+
+func (r requalifier[N]) dependent(d types.DependentTyped[N]) types.DependentTyped[N] {
+	switch t := d.(type) {
+	case types.Monotyped[N]:
+		return r.monotype(t)
+	case types.DependentType[N]:
+		return types.MakeDependentType(types.GetDependees[N](t), r.monotype(t.Function).(types.TypeFunction[N]))
+	}
+	return d
+}
+
+func (r requalifier[N]) predicate(p types.Predicate[N]) types.Predicate[N] {
+	return p.Map(r.monotype)
+}
+
+// returns `ty` w/ each type constant exported by the module replaced by its
+// qualified name
+func (r requalifier[N]) typ(ty types.Type[N]) types.Type[N] {
+	switch t := ty.(type) {
+	case types.Qualified[N]:
+		context := fun.FMap(t.GetContext(), r.predicate)
+		return types.Qualify(r.polytype(t.GetPolytype()), context...)
+	case types.Polytype[N]:
+		return r.polytype(t)
+	case types.DependentTyped[N]:
+		return r.dependent(t)
+	}
+	return ty
+}
+
+// adds each exported symbol to context under its qualified name; each name
+// that is already in the context is reported
+func (cxt *Context[N]) importNames(ecxt *ExportableContext[N], qualify func(N) N, r requalifier[N]) (stat Status) {
+	stat = Ok
+	ecxt.syms.ForEach(func(_ nameable.Nameable, sym Symbol[N]) bool {
+		ty, name := sym.Get().TypeAndExpr()
+		qualified := expr.MakeConst(qualify(name.Name))
+		if _, found := cxt.lookup(qualified.Name); found {
+			cxt.appendReport(makeNameReport("Import Name", IllegalShadow, qualified))
+			stat = IllegalShadow
+			return true
+		}
+		cxt.Add(qualified, r.typ(ty))
+		return true
+	})
+	return
+}
+
+// adds each class and instance exported by `ecxt` to the context. Classes and
+// instances are not qualified: a class is the same class wherever it is
+// imported, and its instances hold wherever it does. Only the types in
+// instance heads are requalified. A class or instance that is already in the
+// context, e.g., because its module was imported before, is skipped
+func (cxt *Context[N]) importClasses(ecxt *ExportableContext[N], r requalifier[N]) (stat Status) {
+	stat = Ok
+	ecxt.classes.ForEach(func(_ nameable.Nameable, c class[N]) bool {
+		if old, found := cxt.classes.Get(c.name); found {
+			if !old.equals(c) {
+				cxt.appendReport(makeNameReport("Import Class", ClassRedef, expr.MakeConst(c.name)))
+				stat = ClassRedef
+			}
+			return true
+		}
+		cxt.classes.Add(c.name, c)
+		cxt.onRollback(func() { cxt.classes.Remove(c.name) })
+		return true
+	})
+
+	ecxt.instances.ForEach(func(_ nameable.Nameable, instances []instance[N]) bool {
+		for _, inst := range instances {
+			inst.context = fun.FMap(inst.context, r.predicate)
+			inst.head = r.predicate(inst.head)
+			if instStat := cxt.importInstance(inst); instStat.NotOk() {
+				stat = instStat
+			}
+		}
+		return true
+	})
+	return
+}
+
+// adds `inst` to the context unless it is already there; fails iff it overlaps
+// another instance
+func (cxt *Context[N]) importInstance(inst instance[N]) Status {
+	class := inst.head.GetClass()
+	instances, _ := cxt.instances.Get(class)
+	for _, other := range instances {
+		if other.name.Name.GetName() == inst.name.Name.GetName() && other.head.Equals(inst.head) {
+			return Ok
+		}
+		if cxt.overlaps(inst, other) {
+			cxt.appendReport(makeNameReport("Import Instance", InstanceRedef, inst.name, other.name))
+			return InstanceRedef
+		}
+	}
+	cxt.instances.Add(class, append(instances, inst))
+	cxt.onRollback(func() { cxt.instances.Add(class, instances) })
+	return Ok
+}
+
+// adds each exported type, its kind, and its constructors to context under
+// their qualified names. The types of the constructors refer to the exported types
+// by their qualified names, too
+func (cxt *Context[N]) importTypes(ecxt *ExportableContext[N], qualify func(N) N, r requalifier[N]) (stat Status) {
+	stat = Ok
+	ecxt.consTable.ForEach(func(typeName nameable.Nameable, cj consJudge[N]) bool {
+		qualifiedType := qualify(typeName.(N))
+		if _, alreadyDefined := cxt.consTable.Get(qualifiedType); alreadyDefined {
+			cxt.appendReport(makeNameReport("Import Type", TypeRedef, expr.MakeConst(qualifiedType)))
+			stat = TypeRedef
+			return true
+		}
+
+		constructors := make(constructorMapType[N], len(cj.constructors))
+		for key, constructor := range cj.constructors {
+			requalified := types.TypedJudge[N](constructor.GetExpression(), r.polytype(constructor.GetType()))
+			if key == wildcardConstructorName {
+				constructors[key] = requalified
+				continue
+			}
+			name := constructor.GetExpression().GetBound().(bridge.Data[N]).GetTag().Name
+			constructors[qualify(name).GetName()] = requalified
+		}
+		cxt.addConsJudge(qualifiedType, consJudge[N]{r.polytype(cj.forType), constructors})
+		if kind, found := ecxt.kinds.Get(typeName); found {
+			cxt.addImportedKind(qualifiedType, kind)
+		}
+		return true
+	})
+	return
+}
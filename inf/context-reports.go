@@ -7,7 +7,38 @@
 // =============================================================================
 package inf
 
+import "github.com/petersalex27/yew-packages/expr"
+
+// records that `e` is being inferred until the returned function is called;
+// reports made in the meantime are located at `e`, unless `e` has no location,
+// in which case they are located at the expression enclosing it
+func (cxt *Context[N]) enterSpan(e expr.Expression[N]) (leave func()) {
+	span := spanOf(e.Collect())
+	if span.line == 0 && len(cxt.spans) != 0 {
+		span = cxt.spans[len(cxt.spans)-1]
+	}
+	cxt.spans = append(cxt.spans, span)
+	return func() { cxt.spans = cxt.spans[:len(cxt.spans)-1] }
+}
+
+// locates `report` at the expression being inferred (see enterSpan) unless it
+// is already located
+func (cxt *Context[N]) locate(report errorReport[N]) errorReport[N] {
+	if report.Span.line == 0 && len(cxt.spans) != 0 {
+		report.Span = cxt.spans[len(cxt.spans)-1]
+	}
+	return report
+}
+
+// appends `report`; if `report` is for a failed unification, the types that
+// could not be unified are added to it. Reports are located at the expression
+// being inferred (see enterSpan)
 func (cxt *Context[N]) appendReport(report errorReport[N]) {
+	report = cxt.locate(report)
+	if report.Status.isUnificationFailure() && len(report.TypesInvolved) == 0 {
+		report.TypesInvolved = cxt.mismatch
+	}
+	cxt.mismatch = nil
 	cxt.reports = append(cxt.reports, report)
 }
 
@@ -17,4 +48,18 @@ func (cxt *Context[N]) GetReports() []errorReport[N] {
 
 func (cxt *Context[N]) HasErrors() bool {
 	return len(cxt.reports) != 0
+}
+
+// warnings are reports that do not cause inference to fail; like reports, they
+// are located at the expression being inferred
+func (cxt *Context[N]) appendWarning(report errorReport[N]) {
+	cxt.warnings = append(cxt.warnings, cxt.locate(report))
+}
+
+func (cxt *Context[N]) GetWarnings() []errorReport[N] {
+	return cxt.warnings
+}
+
+func (cxt *Context[N]) HasWarnings() bool {
+	return len(cxt.warnings) != 0
 }
\ No newline at end of file
@@ -0,0 +1,101 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: methods for saving and returning to the state of a Context
+//
+// Notes: changes are recorded only while a snapshot is held, so contexts that
+// never take snapshots do not pay for them
+// =============================================================================
+package inf
+
+// state of a Context that can be returned to w/ Rollback
+type Snapshot struct {
+	// number of snapshots held when snapshot was taken
+	depth int
+	// length of undo trail
+	trail int
+	// marks for substitution tables
+	typeSubs, exprSubs, kindSubs int
+	// variable counters
+	typeVars, exprVars, kindVars uint32
+	// let-nesting depth
+	level uint32
+	// number of reports
+	reports, warnings int
+}
+
+// records `undo`, a function that undoes a change to the context, iff a
+// snapshot is held
+func (cxt *Context[N]) onRollback(undo func()) {
+	if cxt.snapshots != 0 {
+		cxt.trail = append(cxt.trail, undo)
+	}
+}
+
+// saves the current state of the context: its substitutions, symbols, types
+// and constructors, variable counters, and reports. The returned snapshot must
+// be given back by calling either Rollback or Commit; snapshots must be given
+// back in the reverse order they were taken in
+func (cxt *Context[N]) Snapshot() Snapshot {
+	snap := Snapshot{
+		depth:    cxt.snapshots,
+		trail:    len(cxt.trail),
+		typeSubs: cxt.typeSubs.mark(),
+		exprSubs: cxt.exprSubs.mark(),
+		kindSubs: cxt.kindVarSubs.mark(),
+		typeVars: cxt.TypeContext.GetVarCounter(),
+		exprVars: cxt.ExprContext.GetVarCounter(),
+		kindVars: cxt.kindVars,
+		level:    cxt.TypeContext.GetLevel(),
+		reports:  len(cxt.reports),
+		warnings: len(cxt.warnings),
+	}
+	cxt.snapshots++
+	return snap
+}
+
+// panics if `snap` is not the most recently taken snapshot that is held
+func (cxt *Context[N]) giveBack(snap Snapshot) {
+	if snap.depth != cxt.snapshots-1 {
+		panic("bug: snapshots given back out of order")
+	}
+	cxt.snapshots--
+}
+
+// returns the context to the state it was in when `snap` was taken, undoing
+// all changes made since
+func (cxt *Context[N]) Rollback(snap Snapshot) {
+	cxt.giveBack(snap)
+
+	for i := len(cxt.trail) - 1; i >= snap.trail; i-- {
+		cxt.trail[i]()
+	}
+	cxt.trail = cxt.trail[:snap.trail]
+
+	cxt.typeSubs.undo(snap.typeSubs)
+	cxt.exprSubs.undo(snap.exprSubs)
+	cxt.kindVarSubs.undo(snap.kindSubs)
+	cxt.TypeContext.SetVarCounter(snap.typeVars)
+	cxt.ExprContext.SetVarCounter(snap.exprVars)
+	cxt.kindVars = snap.kindVars
+	for cxt.TypeContext.GetLevel() > snap.level {
+		cxt.TypeContext.LeaveLevel()
+	}
+	for cxt.TypeContext.GetLevel() < snap.level {
+		cxt.TypeContext.EnterLevel()
+	}
+	cxt.reports = cxt.reports[:snap.reports]
+	cxt.warnings = cxt.warnings[:snap.warnings]
+}
+
+// keeps all changes made since `snap` was taken
+func (cxt *Context[N]) Commit(snap Snapshot) {
+	cxt.giveBack(snap)
+
+	cxt.typeSubs.release(snap.typeSubs)
+	cxt.exprSubs.release(snap.exprSubs)
+	cxt.kindVarSubs.release(snap.kindSubs)
+	if cxt.snapshots == 0 {
+		cxt.trail = nil
+	}
+}
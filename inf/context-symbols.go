@@ -10,18 +10,31 @@ package inf
 import (
 	"github.com/petersalex27/yew-packages/bridge"
 	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
 	"github.com/petersalex27/yew-packages/types"
 )
 
 // removes name binding from context
 func (cxt *Context[N]) Remove(name expr.Const[N]) {
-	key := name.Name
-	sym, ok := cxt.syms.Get(key)
+	judgment, ok := cxt.Get(name)
 	if !ok {
 		// TODO: do nothing, ig?
 		return
 	}
 
+	cxt.remove(name)
+	ty, _ := judgment.TypeAndExpr()
+	cxt.onRollback(func() { cxt.shadow(name, ty) })
+}
+
+// removes name binding from context w/o recording the change
+func (cxt *Context[N]) remove(name expr.Const[N]) {
+	key := name.Name
+	sym, found := cxt.syms.Get(key)
+	if !found {
+		// name is bound by the context `cxt` was forked from
+		return
+	}
 	// unshadow/remove sym
 	remove := sym.Unshadow()
 	if remove {
@@ -33,7 +46,7 @@ func (cxt *Context[N]) Remove(name expr.Const[N]) {
 func (cxt *Context[N]) Add(name expr.Const[N], ty types.Type[N]) (added bool) {
 	key := name.Name
 	// attempt to look up existing symbol
-	sym, ok := cxt.syms.Get(key)
+	sym, ok := cxt.lookup(key)
 	if ok {
 		cxt.appendReport(makeNameReport("Declare Name", IllegalShadow, name))
 		return false
@@ -44,11 +57,18 @@ func (cxt *Context[N]) Add(name expr.Const[N], ty types.Type[N]) (added bool) {
 	sym.Shadow(name, ty)
 	// add symbol to table
 	cxt.syms.Add(name.Name, sym)
+	cxt.onRollback(func() { cxt.syms.Remove(key) })
 	return true
 }
 
 // adds judgment to context
 func (cxt *Context[N]) Shadow(name expr.Const[N], ty types.Type[N]) {
+	cxt.shadow(name, ty)
+	cxt.onRollback(func() { cxt.remove(name) })
+}
+
+// adds judgment to context w/o recording the change
+func (cxt *Context[N]) shadow(name expr.Const[N], ty types.Type[N]) {
 	key := name.Name
 	// attempt to look up existing symbol
 	sym, ok := cxt.syms.Get(key)
@@ -66,9 +86,40 @@ func (cxt *Context[N]) Shadow(name expr.Const[N], ty types.Type[N]) {
 func (cxt *Context[N]) Get(name expr.Const[N]) (judgedName bridge.JudgmentAsExpression[N, expr.Const[N]], found bool) {
 	key := name.Name
 	var sym Symbol[N]
-	sym, found = cxt.syms.Get(key)
+	sym, found = cxt.lookup(key)
 	if found {
 		judgedName = sym.Get()
 	}
 	return
 }
+
+// tries to find symbol named `key`, first in the context's symbol table and
+// then in the tables of the contexts it was forked from
+func (cxt *Context[N]) lookup(key N) (sym Symbol[N], found bool) {
+	for c := cxt; c != nil; c = c.parent {
+		if sym, found = c.syms.Get(key); found {
+			return
+		}
+	}
+	return
+}
+
+// calls `f` on each symbol visible in the context--including those of the
+// contexts it was forked from that are not shadowed--until `f` returns false
+func (cxt *Context[N]) forEachSymbol(f func(sym Symbol[N]) bool) {
+	seen := make(map[string]bool)
+	for c := cxt; c != nil; c = c.parent {
+		stop := false
+		c.syms.ForEach(func(key nameable.Nameable, sym Symbol[N]) bool {
+			if seen[key.GetName()] {
+				return true
+			}
+			seen[key.GetName()] = true
+			stop = !f(sym)
+			return !stop
+		})
+		if stop {
+			return
+		}
+	}
+}
@@ -38,13 +38,35 @@ func (constructors consJudge[N]) GetType() types.Polytype[N] {
 }
 
 type Context[N nameable.Nameable] struct {
-	reports     []errorReport[N]
-	typeSubs    *table.Table[types.Monotyped[N]]
-	exprSubs    *table.Table[expr.Referable[N]]
-	consTable   *table.Table[consJudge[N]]
-	syms        *table.Table[Symbol[N]]
-	TypeContext *types.Context[N]
-	ExprContext *expr.Context[N]
+	reports          []errorReport[N]
+	warnings         []errorReport[N]
+	mismatch         []types.Type[N] // types of most recent failed unification
+	typeSubs         substitutions[types.Monotyped[N]]
+	exprSubs         substitutions[expr.Referable[N]]
+	varLevels        *table.Table[uint32]
+	trail            []func()
+	snapshots        int
+	consTable        *table.Table[consJudge[N]]
+	typeKinds        *table.Table[typeKind]
+	kindVarSubs      substitutions[Kind]
+	kindVars         uint32
+	lacks            *table.Table[[]string] // labels each row variable lacks
+	classes          *table.Table[class[N]]
+	instances        *table.Table[[]instance[N]]
+	wanted           []constraint[N]
+	indexConstraints []indexConstraint[N] // deferred constraints between indexes
+	evidence         map[string]expr.Expression[N]
+	holes            []hole[N]                        // holes not yet reported
+	definitions      *table.Table[expr.Expression[N]] // definitions unfolded in indexes
+	fuel             uint                             // reduction steps allowed per index
+	syms             *table.Table[Symbol[N]]
+	modules          *Modules[N]
+	parent           *Context[N]            // context this context was forked from, if any
+	derivations      *derivationRecorder[N] // nil unless derivations are recorded
+	spans            []sourceSpan           // spans of expressions being inferred, inner-most last
+	interner         *types.Interner[N]     // nil unless monotypes are interned
+	TypeContext      *types.Context[N]
+	ExprContext      *expr.Context[N]
 }
 
 // convenience method for a type judgment with a new, free type variable; i.e., for an expression e,
@@ -58,7 +80,11 @@ func (cxt *Context[N]) Judge(e expr.Expression[N]) TypeJudgment[N] {
 type ExportableContext[N nameable.Nameable] struct {
 	name      N
 	consTable *table.Table[consJudge[N]]
+	kinds     *table.Table[Kind] // kinds of the exported types, if known
 	syms      *table.Table[Symbol[N]]
+	// every class the module declares, and every instance, by class name
+	classes   *table.Table[class[N]]
+	instances *table.Table[[]instance[N]]
 }
 
 func (ecxt *ExportableContext[N]) export(name N, sym Symbol[N]) Status {
@@ -80,27 +106,55 @@ func newConsAndSymsTables[N nameable.Nameable]() (*table.Table[consJudge[N]], *t
 func NewExportableContext[N nameable.Nameable]() *ExportableContext[N] {
 	cxt := new(ExportableContext[N])
 	cxt.consTable, cxt.syms = newConsAndSymsTables[N]()
+	cxt.kinds = table.NewTable[Kind]()
+	cxt.classes = table.NewTable[class[N]]()
+	cxt.instances = table.NewTable[[]instance[N]]()
 	return cxt
 }
 
 // creates new inf context
 func NewContext[N nameable.Nameable]() *Context[N] {
 	cxt := new(Context[N])
-	cxt.typeSubs = table.NewTable[types.Monotyped[N]]()
-	cxt.exprSubs = table.NewTable[expr.Referable[N]]()
+	cxt.typeSubs = newTypeSubstitutions[N]()
+	cxt.exprSubs = newExprSubstitutions[N]()
+	cxt.varLevels = table.NewTable[uint32]()
 	cxt.consTable, cxt.syms = newConsAndSymsTables[N]()
+	cxt.typeKinds = table.NewTable[typeKind]()
+	cxt.kindVarSubs = newKindVariableSubstitutions()
+	cxt.lacks = table.NewTable[[]string]()
+	cxt.classes = table.NewTable[class[N]]()
+	cxt.instances = table.NewTable[[]instance[N]]()
+	cxt.evidence = make(map[string]expr.Expression[N])
+	cxt.modules = NewModules[N]()
+	cxt.definitions = table.NewTable[expr.Expression[N]]()
+	cxt.fuel = DefaultFuel
 	cxt.ExprContext = expr.NewContext[N]()
 	cxt.TypeContext = types.NewContext[N]()
 	cxt.reports = []errorReport[N]{}
+	cxt.warnings = []errorReport[N]{}
 	return cxt
 }
 
+// interns the monotypes whose free variables are found during inference, so
+// the free variables of each structurally distinct monotype are found once
+// (see types.Interner)
+func (cxt *Context[N]) UseInterner() {
+	cxt.interner = types.NewInterner[N]()
+}
+
 func (cxt *Context[N]) Inst(sigma types.Polytype[N]) types.Monotyped[N] {
+	m, _ := cxt.instantiate(sigma)
+	return m
+}
+
+// like Inst, but also returns the new type variables that replaced the
+// variables `sigma` binds (in the order `sigma` binds them)
+func (cxt *Context[N]) instantiate(sigma types.Polytype[N]) (m types.Monotyped[N], vs []types.Monotyped[N]) {
 	var t types.DependentTyped[N] = sigma.GetBound()
 	typeVars := sigma.GetBinders()
 
 	// create new type variables
-	vs := fun.FMap(
+	vs = fun.FMap(
 		typeVars,
 		func(v types.Variable[N]) types.Monotyped[N] {
 			return cxt.TypeContext.NewVar()
@@ -113,7 +167,9 @@ func (cxt *Context[N]) Inst(sigma types.Polytype[N]) types.Monotyped[N] {
 	}
 
 	// replace all bound variables w/ newly created type variables
-	return t.ReplaceDependent(typeVars, vs)
+	m = t.ReplaceDependent(typeVars, vs)
+	cxt.impliedLacks(m)
+	return m, vs
 }
 
 func NewTestableContext() *Context[nameable.Testable] {
@@ -162,8 +218,14 @@ func (cxt *Context[N]) applyKindSubstitutions(postFindKind expr.Referable[N]) ex
 //
 //	> Succ (Succ 0)
 func (cxt *Context[N]) GetKindSub(rawKind expr.Referable[N]) (kind expr.Referable[N]) {
-	kind, _ = cxt.findKindSub(rawKind) // returns rawKind if no sub exists
-	return cxt.applyKindSubstitutions(kind)
+	var found bool
+	kind, found = cxt.findKindSub(rawKind) // returns rawKind if no sub exists
+	kind = cxt.applyKindSubstitutions(kind)
+	if found {
+		// remember result so later lookups do not need to redo substitutions
+		cxt.exprSubs.rebind(rawKind.GetReferred(), kind)
+	}
+	return kind
 }
 
 func (cxt *Context[N]) GetSub(m types.Monotyped[N]) (out types.Monotyped[N]) {
@@ -175,8 +237,21 @@ func (cxt *Context[N]) GetSub(m types.Monotyped[N]) (out types.Monotyped[N]) {
 		out = m
 	}
 
-	if function, ok := out.(types.TypeFunction[N]); ok {
+	rebuilt := true
+	switch function := out.(type) {
+	case types.TypeFunction[N]:
+		out = function.Rebuild(cxt.GetSub, cxt.GetKindSub)
+	case types.Nested[N]:
+		out = function.Rebuild(cxt.GetSub, cxt.GetKindSub)
+	case types.Record[N]:
 		out = function.Rebuild(cxt.GetSub, cxt.GetKindSub)
+	default:
+		rebuilt = false
+	}
+
+	if rebuilt && found {
+		// remember result so later lookups do not need to redo substitutions
+		cxt.typeSubs.rebind(m.GetReferred(), out)
 	}
 
 	return out
@@ -188,7 +263,7 @@ func (cxt *Context[N]) GetSub(m types.Monotyped[N]) (out types.Monotyped[N]) {
 func (cxt *Context[N]) findSub(m types.Monotyped[N]) (out types.Monotyped[N], found bool) {
 	found = false
 	if nm, ok := m.(types.Variable[N]); ok {
-		out, found = cxt.typeSubs.Get(nm)
+		out, found = cxt.typeSubs.Get(nm.GetReferred())
 	}
 
 	if !found {
@@ -0,0 +1,410 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: explicitly typed core language that inferred expressions are
+// elaborated into (see ElaborateCore)
+//
+// Notes: each binder, application, and constructor of a core term carries its
+// type. Instantiation of a polytype is an explicit type application and
+// generalization is an explicit type abstraction; class dictionaries are
+// passed explicitly, too, but are left untyped. While inference is still
+// running, the types of core terms may contain variables w/ substitutions;
+// `zonk` replaces them once inference is done. Terms checked against a less
+// polymorphic type are wrapped in the coercions subsCheck returns. One thing is
+// left implicit: uses of a name w/in its own rec-expression definitions are
+// monomorphic and so are never instantiated
+// =============================================================================
+package inf
+
+import (
+	"strings"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// term of the explicitly typed core language
+type Core[N nameable.Nameable] interface {
+	String() string
+	// type of the term
+	GetType() types.Type[N]
+	// applies all substitutions to the types w/in the term and replaces
+	// dictionary placeholders w/ the dictionaries proving them
+	zonk(cxt *Context[N]) Core[N]
+}
+
+// applies substitutions to `ty`; variables bound by `ty` are never substituted
+func (cxt *Context[N]) zonkType(ty types.Type[N]) types.Type[N] {
+	switch t := ty.(type) {
+	case types.Monotyped[N]:
+		return cxt.GetSub(t)
+	case types.Polytype[N]:
+		if m, ok := t.GetBound().(types.Monotyped[N]); ok {
+			return types.Forall(t.GetBinders()...).Bind(cxt.GetSub(m))
+		}
+	}
+	return ty
+}
+
+func (cxt *Context[N]) zonkMonotype(m types.Monotyped[N]) types.Monotyped[N] {
+	return cxt.GetSub(m)
+}
+
+// variable or constant; its type is a polytype iff it is instantiated by a
+// CoreTypeApp
+//
+//	x
+type CoreVar[N nameable.Nameable] struct {
+	Name N
+	Type types.Type[N]
+}
+
+func (x CoreVar[N]) String() string         { return x.Name.GetName() }
+func (x CoreVar[N]) GetType() types.Type[N] { return x.Type }
+func (x CoreVar[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreVar[N]{x.Name, cxt.zonkType(x.Type)}
+}
+
+// instance `t` of the name `name` w/ type `sigma`, where the variables `sigma`
+// binds were replaced by `args` and its class constraints are proven by
+// `dictionaries`
+func coreInstance[N nameable.Nameable](name N, sigma types.Type[N], args []types.Monotyped[N], dictionaries []expr.Expression[N], t types.Monotyped[N]) Core[N] {
+	if len(args) == 0 && len(dictionaries) == 0 {
+		return CoreVar[N]{name, t}
+	}
+	return CoreTypeApp[N]{CoreVar[N]{name, sigma}, args, dictionaries, t}
+}
+
+// primitive value
+type CoreLiteral[N nameable.Nameable] struct {
+	Value expr.Expression[N]
+	Type  types.Monotyped[N]
+}
+
+func (lit CoreLiteral[N]) String() string         { return lit.Value.String() }
+func (lit CoreLiteral[N]) GetType() types.Type[N] { return lit.Type }
+func (lit CoreLiteral[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreLiteral[N]{lit.Value, cxt.GetSub(lit.Type)}
+}
+
+// term that has not been written yet
+//
+//	?x
+type CoreHole[N nameable.Nameable] struct {
+	Hole expr.Hole[N]
+	Type types.Monotyped[N]
+}
+
+func (h CoreHole[N]) String() string         { return h.Hole.String() }
+func (h CoreHole[N]) GetType() types.Type[N] { return h.Type }
+func (h CoreHole[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreHole[N]{h.Hole, cxt.GetSub(h.Type)}
+}
+
+// instantiation of a polymorphic term, applying it to types and then to the
+// dictionaries of the class constraints on it
+//
+//	(x @Int @Bool d1 d2)
+type CoreTypeApp[N nameable.Nameable] struct {
+	Term         Core[N]
+	TypeArgs     []types.Monotyped[N]
+	Dictionaries []expr.Expression[N]
+	Type         types.Monotyped[N]
+}
+
+func (app CoreTypeApp[N]) String() string {
+	strs := []string{app.Term.String()}
+	for _, t := range app.TypeArgs {
+		strs = append(strs, "@"+t.String())
+	}
+	for _, d := range app.Dictionaries {
+		strs = append(strs, d.String())
+	}
+	return "(" + strings.Join(strs, " ") + ")"
+}
+
+func (app CoreTypeApp[N]) GetType() types.Type[N] { return app.Type }
+
+func (app CoreTypeApp[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreTypeApp[N]{
+		app.Term.zonk(cxt),
+		fun.FMap(app.TypeArgs, cxt.zonkMonotype),
+		fun.FMap(app.Dictionaries, cxt.applyEvidence),
+		cxt.GetSub(app.Type),
+	}
+}
+
+// generalization of a term over types and then over the dictionaries of the
+// class constraints on it. Binders are type variables or--for terms checked
+// against a polytype--the skolems standing in for them
+//
+//	(Λa b . λd1 d2 . e)
+type CoreTypeAbs[N nameable.Nameable] struct {
+	Binders      []types.Monotyped[N]
+	Dictionaries []N
+	Term         Core[N]
+	Type         types.Type[N]
+}
+
+func (abs CoreTypeAbs[N]) String() string {
+	body := abs.Term.String()
+	if len(abs.Dictionaries) != 0 {
+		dicts := fun.FMap(abs.Dictionaries, func(d N) string { return d.GetName() })
+		body = "λ" + strings.Join(dicts, " ") + " . " + body
+	}
+	if len(abs.Binders) != 0 {
+		binders := fun.FMap(abs.Binders, func(t types.Monotyped[N]) string { return t.String() })
+		body = "Λ" + strings.Join(binders, " ") + " . " + body
+	}
+	return "(" + body + ")"
+}
+
+func (abs CoreTypeAbs[N]) GetType() types.Type[N] { return abs.Type }
+
+func (abs CoreTypeAbs[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreTypeAbs[N]{abs.Binders, abs.Dictionaries, abs.Term.zonk(cxt), cxt.zonkType(abs.Type)}
+}
+
+// wraps `term` in a type abstraction unless there is nothing to abstract over
+func coreGeneralization[N nameable.Nameable](binders []types.Monotyped[N], context []constraint[N], term Core[N], ty types.Type[N]) Core[N] {
+	if len(binders) == 0 && len(context) == 0 {
+		return term
+	}
+	dictionaries := fun.FMap(context, func(c constraint[N]) N { return c.evidence.Name })
+	return CoreTypeAbs[N]{binders, dictionaries, term, ty}
+}
+
+// variables bound by polytype `ty` as monotypes
+func bindersOf[N nameable.Nameable](ty types.Type[N]) []types.Monotyped[N] {
+	if q, ok := ty.(types.Qualified[N]); ok {
+		ty = q.GetPolytype()
+	}
+	sigma, ok := ty.(types.Polytype[N])
+	if !ok {
+		return nil
+	}
+	return fun.FMap(sigma.GetBinders(), func(v types.Variable[N]) types.Monotyped[N] { return v })
+}
+
+// function w/ a typed parameter
+//
+//	(λx: t . e)
+type CoreLambda[N nameable.Nameable] struct {
+	Param     N
+	ParamType types.Type[N]
+	Body      Core[N]
+	Type      types.Monotyped[N]
+}
+
+func (f CoreLambda[N]) String() string {
+	return "(λ" + f.Param.GetName() + ": " + f.ParamType.String() + " . " + f.Body.String() + ")"
+}
+
+func (f CoreLambda[N]) GetType() types.Type[N] { return f.Type }
+
+func (f CoreLambda[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreLambda[N]{f.Param, cxt.zonkType(f.ParamType), f.Body.zonk(cxt), cxt.GetSub(f.Type)}
+}
+
+// application of a function to an argument
+//
+//	(f x)
+type CoreApp[N nameable.Nameable] struct {
+	Function Core[N]
+	Arg      Core[N]
+	Type     types.Monotyped[N]
+}
+
+func (app CoreApp[N]) String() string {
+	return "(" + app.Function.String() + " " + app.Arg.String() + ")"
+}
+
+func (app CoreApp[N]) GetType() types.Type[N] { return app.Type }
+
+func (app CoreApp[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreApp[N]{app.Function.zonk(cxt), app.Arg.zonk(cxt), cxt.GetSub(app.Type)}
+}
+
+// definition of a name w/ its (generalized) type
+//
+//	x: σ = e
+type CoreBinding[N nameable.Nameable] struct {
+	Name  N
+	Type  types.Type[N]
+	Bound Core[N]
+}
+
+func (b CoreBinding[N]) String() string {
+	return b.Name.GetName() + ": " + b.Type.String() + " = " + b.Bound.String()
+}
+
+func (b CoreBinding[N]) zonk(cxt *Context[N]) CoreBinding[N] {
+	return CoreBinding[N]{b.Name, cxt.zonkType(b.Type), b.Bound.zonk(cxt)}
+}
+
+// let- or rec-expression
+//
+//	let x: σ = e0 in e1
+//	rec x: σ = e0 and y: τ = e1 in e2
+type CoreLet[N nameable.Nameable] struct {
+	Recursive bool
+	Bindings  []CoreBinding[N]
+	Body      Core[N]
+	Type      types.Monotyped[N]
+}
+
+func (let CoreLet[N]) String() string {
+	head := "let "
+	if let.Recursive {
+		head = "rec "
+	}
+	bindings := fun.FMap(let.Bindings, CoreBinding[N].String)
+	return head + strings.Join(bindings, " and ") + " in " + let.Body.String()
+}
+
+func (let CoreLet[N]) GetType() types.Type[N] { return let.Type }
+
+func (let CoreLet[N]) zonk(cxt *Context[N]) Core[N] {
+	bindings := fun.FMap(let.Bindings, func(b CoreBinding[N]) CoreBinding[N] { return b.zonk(cxt) })
+	return CoreLet[N]{let.Recursive, bindings, let.Body.zonk(cxt), cxt.GetSub(let.Type)}
+}
+
+// application of a data constructor to its members
+//
+//	(Tag e1 .. eN)
+type CoreData[N nameable.Nameable] struct {
+	Tag     N
+	Members []Core[N]
+	Type    types.Monotyped[N]
+}
+
+func (data CoreData[N]) String() string {
+	strs := append([]string{data.Tag.GetName()}, fun.FMap(data.Members, Core[N].String)...)
+	return "(" + strings.Join(strs, " ") + ")"
+}
+
+func (data CoreData[N]) GetType() types.Type[N] { return data.Type }
+
+func (data CoreData[N]) zonk(cxt *Context[N]) Core[N] {
+	members := fun.FMap(data.Members, func(m Core[N]) Core[N] { return m.zonk(cxt) })
+	return CoreData[N]{data.Tag, members, cxt.GetSub(data.Type)}
+}
+
+// list of terms
+//
+//	[e1, .., eN]
+type CoreList[N nameable.Nameable] struct {
+	Elems []Core[N]
+	Type  types.Monotyped[N]
+}
+
+func (ls CoreList[N]) String() string {
+	return "[" + strings.Join(fun.FMap(ls.Elems, Core[N].String), ", ") + "]"
+}
+
+func (ls CoreList[N]) GetType() types.Type[N] { return ls.Type }
+
+func (ls CoreList[N]) zonk(cxt *Context[N]) Core[N] {
+	elems := fun.FMap(ls.Elems, func(e Core[N]) Core[N] { return e.zonk(cxt) })
+	return CoreList[N]{elems, cxt.GetSub(ls.Type)}
+}
+
+// record of labeled terms
+//
+//	{l1 = e1, .., lN = eN}
+type CoreRecord[N nameable.Nameable] struct {
+	Labels []N
+	Fields []Core[N]
+	Type   types.Monotyped[N]
+}
+
+func (r CoreRecord[N]) String() string {
+	fields := make([]string, len(r.Labels))
+	for i, label := range r.Labels {
+		fields[i] = label.GetName() + " = " + r.Fields[i].String()
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+func (r CoreRecord[N]) GetType() types.Type[N] { return r.Type }
+
+func (r CoreRecord[N]) zonk(cxt *Context[N]) Core[N] {
+	fields := fun.FMap(r.Fields, func(e Core[N]) Core[N] { return e.zonk(cxt) })
+	return CoreRecord[N]{r.Labels, fields, cxt.GetSub(r.Type)}
+}
+
+// selection of a record's field
+//
+//	e.label
+type CoreAccess[N nameable.Nameable] struct {
+	Record Core[N]
+	Label  N
+	Type   types.Monotyped[N]
+}
+
+func (a CoreAccess[N]) String() string         { return a.Record.String() + "." + a.Label.GetName() }
+func (a CoreAccess[N]) GetType() types.Type[N] { return a.Type }
+func (a CoreAccess[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreAccess[N]{a.Record.zonk(cxt), a.Label, cxt.GetSub(a.Type)}
+}
+
+// record extended w/ a field
+//
+//	{label = e0 | e1}
+type CoreExtension[N nameable.Nameable] struct {
+	Label  N
+	Value  Core[N]
+	Record Core[N]
+	Type   types.Monotyped[N]
+}
+
+func (x CoreExtension[N]) String() string {
+	return "{" + x.Label.GetName() + " = " + x.Value.String() + " | " + x.Record.String() + "}"
+}
+
+func (x CoreExtension[N]) GetType() types.Type[N] { return x.Type }
+
+func (x CoreExtension[N]) zonk(cxt *Context[N]) Core[N] {
+	return CoreExtension[N]{x.Label, x.Value.zonk(cxt), x.Record.zonk(cxt), cxt.GetSub(x.Type)}
+}
+
+// case of a select expression; the pattern is left as an expression, and the
+// variables it binds are typed
+//
+//	when p -> e
+type CoreCase[N nameable.Nameable] struct {
+	Binders     []N
+	BinderTypes []types.Monotyped[N]
+	Pattern     expr.Expression[N]
+	Body        Core[N]
+}
+
+func (c CoreCase[N]) String() string {
+	return "when " + c.Pattern.String() + " -> " + c.Body.String()
+}
+
+func (c CoreCase[N]) zonk(cxt *Context[N]) CoreCase[N] {
+	return CoreCase[N]{c.Binders, fun.FMap(c.BinderTypes, cxt.zonkMonotype), c.Pattern, c.Body.zonk(cxt)}
+}
+
+// select expression
+//
+//	select e when p1 -> e1 | .. | when pN -> eN
+type CoreSelect[N nameable.Nameable] struct {
+	Selector Core[N]
+	Cases    []CoreCase[N]
+	Type     types.Monotyped[N]
+}
+
+func (s CoreSelect[N]) String() string {
+	return "select " + s.Selector.String() + " " + strings.Join(fun.FMap(s.Cases, CoreCase[N].String), " | ")
+}
+
+func (s CoreSelect[N]) GetType() types.Type[N] { return s.Type }
+
+func (s CoreSelect[N]) zonk(cxt *Context[N]) Core[N] {
+	cases := fun.FMap(s.Cases, func(c CoreCase[N]) CoreCase[N] { return c.zonk(cxt) })
+	return CoreSelect[N]{s.Selector.zonk(cxt), cases, cxt.GetSub(s.Type)}
+}
@@ -0,0 +1,107 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestElaborateCore(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x := expr.Var(name("x"))
+	id := expr.Bind(x).In(x)
+	apply := func(es ...expr.Expression[nameable.Testable]) expr.Expression[nameable.Testable] {
+		return expr.Apply[nameable.Testable](es[0], es[1], es[2:]...)
+	}
+	annotate := func(e expr.Expression[nameable.Testable], t types.Monotyped[nameable.Testable]) expr.Expression[nameable.Testable] {
+		return bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](e, t)
+	}
+	a := types.Var(name("a"))
+	Int := types.MakeConst(name("Int"))
+	fn := NewTestableContext().TypeContext.Function
+	polyId := types.Nest(types.Forall(a).Bind(fn(a, a)))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expect      string
+		expectType  string
+	}{
+		{
+			`λx . x`,
+			id,
+			"(Λ$0 . (λx: $0 . x))",
+			"forall $0 . ($0 -> $0)",
+		},
+		{
+			`λ0 . 0`, // binder is renamed so it does not capture the constant 0
+			expr.Bind(expr.Var(name("0"))).In(c("0")),
+			"(Λ$0 . (λ$0: $0 . 0))",
+			"forall $0 . ($0 -> Int)",
+		},
+		{
+			`pair 0`,
+			apply(c("pair"), c("0")),
+			"(Λ$1 . ((pair @Int @$1) 0))",
+			"forall $1 . ($1 -> (Pair Int $1))",
+		},
+		{
+			`let id = λx . x in pair (id 0) (id true)`,
+			expr.Let[nameable.Testable](c("id"), id, apply(c("pair"), apply(c("id"), c("0")), apply(c("id"), c("true")))),
+			"let id: forall $1 . ($1 -> $1) = (Λ$1 . (λx: $1 . x)) in (((pair @Int @Bool) ((id @Int) 0)) ((id @Bool) true))",
+			"(Pair Int Bool)",
+		},
+		{
+			`[0, 0]`,
+			expr.List[nameable.Testable]{c("0"), c("0")},
+			"[0, 0]",
+			"[Int]",
+		},
+		{
+			`runST (ret 0)`,
+			apply(c("runST"), apply(c("ret"), c("0"))),
+			"((runST @Int) (Λs . ((ret @s @Int) 0)))",
+			"Int",
+		},
+		{
+			`(nested : Int -> Int -> Int)`, // instantiated under a function
+			annotate(c("nested"), fn(Int, fn(Int, Int))),
+			"(λ$0: Int . ((nested $0) @Int))",
+			"(Int -> (Int -> Int))",
+		},
+		{
+			`(nested : Int -> (forall a . a -> a))`, // instantiated and then generalized
+			annotate(c("nested"), fn(Int, polyId)),
+			"(λ$0: Int . (Λa . ((nested $0) @a)))",
+			"(Int -> (forall a . (a -> a)))",
+		},
+		{
+			`(applyInt : (forall a . a -> a) -> Int)`, // coerced argument
+			annotate(c("applyInt"), fn(polyId, Int)),
+			"(λ$0: (forall a . (a -> a)) . (applyInt ($0 @Int)))",
+			"((forall a . (a -> a)) -> Int)",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeRankTestContext()
+		core, ty, reports := cxt.ElaborateCore(test.input)
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(nil, reports, i))
+		}
+		if actual := ty.String(); actual != test.expectType {
+			t.Fatal(testutil.Testing("type", test.description).FailMessage(test.expectType, actual, i))
+		}
+		if actual := core.String(); actual != test.expect {
+			t.Fatal(testutil.Testing("core", test.description).FailMessage(test.expect, actual, i))
+		}
+		if actual := core.GetType().String(); actual != test.expectType {
+			t.Fatal(testutil.Testing("core type", test.description).FailMessage(test.expectType, actual, i))
+		}
+	}
+}
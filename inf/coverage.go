@@ -0,0 +1,380 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: exhaustiveness and redundancy checking of select expressions
+//
+// Notes: uses the pattern matrix / usefulness algorithm described in Maranget,
+// "Warnings for pattern matching" (2007)
+// =============================================================================
+package inf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// kinds of deconstructed patterns
+type spaceKind byte
+
+const (
+	// matches anything--wildcards and variables
+	anySpace spaceKind = iota
+	// constructor applied to exactly as many patterns as it has members
+	constructorSpace
+	// primitive literal; there are (practically) infinitely many of these
+	literalSpace
+	// list of a fixed length; there are infinitely many lengths
+	listSpace
+	// tuple of a fixed length; a tuple has exactly one "constructor"
+	tupleSpace
+)
+
+// head of a deconstructed pattern, i.e., everything but its sub-patterns
+type spaceHead struct {
+	kind  spaceKind
+	name  string
+	arity int
+}
+
+// pattern deconstructed into a head and its sub-patterns
+type space struct {
+	spaceHead
+	args []space
+}
+
+// row of patterns
+type spaceRow []space
+
+var anything = space{spaceHead: spaceHead{kind: anySpace}}
+
+// returns `n` wildcard patterns
+func anythings(n int) spaceRow {
+	row := make(spaceRow, n)
+	for i := range row {
+		row[i] = anything
+	}
+	return row
+}
+
+// [p1, .., pN] ++ [q1, .., qM]
+func (row spaceRow) prepend(ps ...space) spaceRow {
+	out := make(spaceRow, 0, len(ps)+len(row))
+	return append(append(out, ps...), row...)
+}
+
+// deconstructs `pat`; returns false when `pat` is not understood
+func deconstruct[N nameable.Nameable](pat expr.AlmostPattern[N]) (sp space, ok bool) {
+	if elem, isElem := pat.AsElement(); isElem {
+		name := elem.GetElement().GetName()
+		switch elem.GetElementType() {
+		case expr.PatternElementWildcard, expr.PatternElementVar:
+			return anything, true
+		case expr.PatternElementLiteral:
+			return space{spaceHead: spaceHead{literalSpace, name, 0}}, true
+		case expr.PatternElementConst:
+			if name == wildcardConstructorName {
+				return anything, true
+			}
+			return space{spaceHead: spaceHead{constructorSpace, name, 0}}, true
+		}
+		return sp, false
+	}
+
+	seq, isSeq := pat.AsSequence()
+	if !isSeq {
+		return sp, false
+	}
+
+	switch seq.GetSequenceType() {
+	case expr.PatternSequenceWildcard:
+		return anything, true
+	case expr.PatternSequenceList, expr.PatternSequenceTuple:
+		kind := listSpace
+		if seq.GetSequenceType() == expr.PatternSequenceTuple {
+			kind = tupleSpace
+		}
+		elems := seq.GetSequence()
+		sp = space{spaceHead: spaceHead{kind, strconv.Itoa(len(elems)), len(elems)}}
+		sp.args = make([]space, len(elems))
+		for i, elem := range elems {
+			if sp.args[i], ok = deconstruct(elem); !ok {
+				return
+			}
+		}
+		return sp, true
+	case expr.PatternSequenceApplication:
+		// ((C p1) p2)
+		elems := seq.GetSequence()
+		if len(elems) != 2 {
+			return sp, false
+		}
+		var left, right space
+		if left, ok = deconstruct(elems[0]); !ok || left.kind != constructorSpace {
+			return sp, false
+		}
+		if right, ok = deconstruct(elems[1]); !ok {
+			return
+		}
+		sp = space{spaceHead: left.spaceHead}
+		sp.args = append(append(make([]space, 0, len(left.args)+1), left.args...), right)
+		sp.arity = len(sp.args)
+		return sp, true
+	}
+	return sp, false
+}
+
+// S(h, P): rows whose first pattern matches head `h`, w/ the first pattern
+// replaced by its sub-patterns (or by wildcards if it was a wildcard)
+func specialize(rows []spaceRow, h spaceHead) []spaceRow {
+	out := make([]spaceRow, 0, len(rows))
+	for _, row := range rows {
+		first, rest := row[0], row[1:]
+		if first.kind == anySpace {
+			out = append(out, rest.prepend(anythings(h.arity)...))
+		} else if first.spaceHead == h {
+			out = append(out, rest.prepend(first.args...))
+		}
+	}
+	return out
+}
+
+// D(P): rows whose first pattern is a wildcard, w/ the first pattern removed
+func defaultRows(rows []spaceRow) []spaceRow {
+	out := make([]spaceRow, 0, len(rows))
+	for _, row := range rows {
+		if row[0].kind == anySpace {
+			out = append(out, row[1:])
+		}
+	}
+	return out
+}
+
+// distinct heads of the first column of `rows`, in order of appearance
+func firstColumnHeads(rows []spaceRow) []spaceHead {
+	heads := []spaceHead{}
+	seen := make(map[spaceHead]bool)
+	for _, row := range rows {
+		if h := row[0].spaceHead; h.kind != anySpace && !seen[h] {
+			seen[h] = true
+			heads = append(heads, h)
+		}
+	}
+	return heads
+}
+
+// returns the constructors of the type that has a constructor named
+// `constructorName`; each constructor is paired w/ its number of members
+func (cxt *Context[N]) siblingConstructors(constructorName string) (siblings []spaceHead, found bool) {
+	cxt.consTable.ForEach(func(_ nameable.Nameable, cj consJudge[N]) bool {
+		if _, found = cj.constructors[constructorName]; !found || constructorName == wildcardConstructorName {
+			found = false
+			return true
+		}
+
+		for name, constructor := range cj.constructors {
+			if name == wildcardConstructorName {
+				continue
+			}
+			arity := len(constructor.GetExpression().GetBinders())
+			siblings = append(siblings, spaceHead{constructorSpace, name, arity})
+		}
+		return false
+	})
+
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i].name < siblings[j].name })
+	return
+}
+
+// returns the heads that are possible but do not appear in `heads`. Second
+// return value is true iff `heads` is non-empty and `missing` is complete,
+// i.e., every head that is not in `heads` is in `missing`
+func (cxt *Context[N]) missingHeads(heads []spaceHead) (missing []spaceHead, known bool) {
+	if len(heads) == 0 {
+		return nil, false
+	}
+
+	switch heads[0].kind {
+	case tupleSpace:
+		return nil, true
+	case constructorSpace:
+		siblings, found := cxt.siblingConstructors(heads[0].name)
+		if !found {
+			return nil, false
+		}
+		present := make(map[spaceHead]bool, len(heads))
+		for _, h := range heads {
+			present[h] = true
+		}
+		for _, h := range siblings {
+			if !present[h] {
+				missing = append(missing, h)
+			}
+		}
+		return missing, true
+	}
+	// literals and lists can never be fully enumerated
+	return nil, false
+}
+
+// U(P, q): true iff some value matched by `q` is not matched by any row of
+// `rows`
+func (cxt *Context[N]) useful(rows []spaceRow, q spaceRow) bool {
+	if len(q) == 0 {
+		return len(rows) == 0
+	}
+
+	first, rest := q[0], q[1:]
+	if first.kind != anySpace {
+		return cxt.useful(specialize(rows, first.spaceHead), rest.prepend(first.args...))
+	}
+
+	heads := firstColumnHeads(rows)
+	if missing, known := cxt.missingHeads(heads); known && len(missing) == 0 {
+		for _, h := range heads {
+			if cxt.useful(specialize(rows, h), rest.prepend(anythings(h.arity)...)) {
+				return true
+			}
+		}
+		return false
+	}
+	return cxt.useful(defaultRows(rows), rest)
+}
+
+// returns rows of `n` patterns that match values no row of `rows` matches;
+// returns nil iff `rows` is exhaustive
+func (cxt *Context[N]) witnesses(rows []spaceRow, n int) []spaceRow {
+	if n == 0 {
+		if len(rows) == 0 {
+			return []spaceRow{{}}
+		}
+		return nil
+	}
+
+	heads := firstColumnHeads(rows)
+	missing, known := cxt.missingHeads(heads)
+	if known && len(missing) == 0 {
+		out := []spaceRow{}
+		for _, h := range heads {
+			for _, w := range cxt.witnesses(specialize(rows, h), h.arity+n-1) {
+				sp := space{spaceHead: h, args: w[:h.arity]}
+				out = append(out, w[h.arity:].prepend(sp))
+			}
+		}
+		return out
+	}
+
+	ws := cxt.witnesses(defaultRows(rows), n-1)
+	if len(ws) == 0 {
+		return nil
+	}
+
+	firsts := []space{anything}
+	if len(heads) != 0 && len(missing) != 0 {
+		firsts = make([]space, len(missing))
+		for i, h := range missing {
+			firsts[i] = space{spaceHead: h, args: anythings(h.arity)}
+		}
+	}
+
+	out := make([]spaceRow, 0, len(firsts)*len(ws))
+	for _, first := range firsts {
+		for _, w := range ws {
+			out = append(out, w.prepend(first))
+		}
+	}
+	return out
+}
+
+// converts a deconstructed pattern back into an expression
+func (cxt *Context[N]) reconstruct(sp space) expr.Expression[N] {
+	args := make([]expr.Expression[N], len(sp.args))
+	for i, arg := range sp.args {
+		args[i] = cxt.reconstruct(arg)
+	}
+
+	var name string
+	switch sp.kind {
+	case anySpace:
+		name = wildcardConstructorName
+	case listSpace:
+		return expr.List[N](args)
+	case tupleSpace:
+		name = "(" + strings.Repeat(",", len(args)-1) + ")"
+	default:
+		name = sp.name
+	}
+
+	head := expr.MakeConst(cxt.TypeContext.Con(name).GetReferred())
+	if len(args) == 0 {
+		return head
+	}
+	return expr.Apply[N](head, args[0], args[1:]...)
+}
+
+// checks that `patterns` match every value of their type and that each
+// pattern matches some value the patterns before it do not. Returns example
+// patterns for the values that are not matched and the indexes of the
+// patterns that can never match. Both are also recorded as warnings.
+//
+// Coverage is not checked (and nil, nil is returned) if some pattern cannot be
+// understood
+func (cxt *Context[N]) CheckCoverage(patterns ...bridge.Pattern[N]) (missing []expr.Expression[N], redundant []int) {
+	rows := make([]spaceRow, len(patterns))
+	for i, pattern := range patterns {
+		sp, ok := deconstruct(pattern.GetPattern())
+		if !ok {
+			return nil, nil
+		}
+		rows[i] = spaceRow{sp}
+	}
+
+	for i, row := range rows {
+		if !cxt.useful(rows[:i], row) {
+			redundant = append(redundant, i)
+			judgment := bridge.Judgment(cxt.reconstruct(row[0]), types.Type[N](patterns[i].GetType()))
+			cxt.appendWarning(makeReport[N]("Case", RedundantCase, judgment))
+		}
+	}
+
+	ws := cxt.witnesses(rows, 1)
+	if len(ws) == 0 {
+		return
+	}
+
+	var ty types.Type[N] = cxt.TypeContext.NewVar()
+	if len(patterns) != 0 {
+		ty = patterns[0].GetType()
+	}
+	judgments := make([]TypeJudgment[N], len(ws))
+	missing = make([]expr.Expression[N], len(ws))
+	for i, w := range ws {
+		missing[i] = cxt.reconstruct(w[0])
+		judgments[i] = bridge.Judgment(missing[i], ty)
+	}
+	cxt.appendWarning(makeReport("Case", NonExhaustiveMatch, judgments...))
+	return
+}
+
+// converts the patterns of `cases` into patterns for values of type `t` and
+// checks their coverage
+func (cxt *Context[N]) checkCaseCoverage(t types.Monotyped[N], cases []expr.Case[N]) {
+	patterns := make([]bridge.Pattern[N], len(cases))
+	for i, c := range cases {
+		p, ok := c.GetPattern().(expr.Patternable[N])
+		if !ok {
+			return
+		}
+		almost, ok := p.ToAlmostPattern()
+		if !ok {
+			return
+		}
+		patterns[i], _ = bridge.ToPattern(almost, t)
+	}
+	cxt.CheckCoverage(patterns...)
+}
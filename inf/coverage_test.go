@@ -0,0 +1,122 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestCoverage(t *testing.T) {
+	y := expr.Var(nameable.MakeTestable("y"))
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+	nothing := expr.Const[nameable.Testable]{Name: "Nothing"}
+	wildcard := expr.Const[nameable.Testable]{Name: "_"}
+	just0 := expr.Apply[nameable.Testable](just, zero)
+	justY := expr.Apply[nameable.Testable](just, y)
+	justJust0 := expr.Apply[nameable.Testable](just, just0)
+
+	tests := []struct {
+		description string
+		input       expr.Selection[nameable.Testable]
+		stat        Status
+		expect      []string
+	}{
+		{
+			`select Just 0 when Just y -> y when Nothing -> 0`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(justY, y),
+				expr.Bind[nameable.Testable]().InCase(nothing, zero),
+			),
+			Ok,
+			nil,
+		},
+		{
+			`select Just 0 when _ -> 0`,
+			expr.Select[nameable.Testable](just0, expr.Bind[nameable.Testable]().InCase(wildcard, zero)),
+			Ok,
+			nil,
+		},
+		{
+			`select Just 0 when Just y -> y`,
+			expr.Select[nameable.Testable](just0, expr.Bind(y).InCase(justY, y)),
+			NonExhaustiveMatch,
+			[]string{"Nothing"},
+		},
+		{
+			`select Just 0 when Nothing -> 0`,
+			expr.Select[nameable.Testable](just0, expr.Bind[nameable.Testable]().InCase(nothing, zero)),
+			NonExhaustiveMatch,
+			[]string{"(Just _)"},
+		},
+		{
+			`select Just (Just 0) when Just (Just y) -> y when Nothing -> 0`,
+			expr.Select[nameable.Testable](justJust0,
+				expr.Bind(y).InCase(expr.Apply[nameable.Testable](just, justY), y),
+				expr.Bind[nameable.Testable]().InCase(nothing, zero),
+			),
+			NonExhaustiveMatch,
+			[]string{"(Just Nothing)"},
+		},
+		{
+			`select [0] when [y] -> y`,
+			expr.Select[nameable.Testable](
+				expr.List[nameable.Testable]{zero},
+				expr.Bind(y).InCase(expr.List[nameable.Testable]{y}, y),
+			),
+			NonExhaustiveMatch,
+			[]string{"_"},
+		},
+		{
+			`select Just 0 when y -> 0 when Nothing -> 0`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(y, zero),
+				expr.Bind[nameable.Testable]().InCase(nothing, zero),
+			),
+			RedundantCase,
+			[]string{"Nothing"},
+		},
+		{
+			`select Just 0 when Just y -> y when Nothing -> 0 when _ -> 0`,
+			expr.Select[nameable.Testable](just0,
+				expr.Bind(y).InCase(justY, y),
+				expr.Bind[nameable.Testable]().InCase(nothing, zero),
+				expr.Bind[nameable.Testable]().InCase(wildcard, zero),
+			),
+			RedundantCase,
+			[]string{"_"},
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeCaseTestContext()
+		if _, reports := cxt.Infer(test.input); len(reports) != 0 {
+			t.Fatal(testutil.Testing("errors", test.description).FailMessage(nil, reports, i))
+		}
+
+		warnings := cxt.GetWarnings()
+		if test.stat.IsOk() {
+			if len(warnings) != 0 {
+				t.Fatal(testutil.Testing("warnings", test.description).FailMessage(nil, warnings, i))
+			}
+			continue
+		}
+
+		if len(warnings) != 1 || !warnings[0].Status.Is(test.stat) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.stat, warnings, i))
+		}
+
+		terms := warnings[0].TermsInvolved
+		if len(terms) != len(test.expect) {
+			t.Fatal(testutil.Testing("patterns", test.description).FailMessage(test.expect, terms, i))
+		}
+		for j, term := range terms {
+			e, _ := term.GetExpressionAndType()
+			if actual := e.String(); actual != test.expect[j] {
+				t.Fatal(testutil.Testing("pattern", test.description).FailMessage(test.expect[j], actual, i))
+			}
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: dependency analysis of named definitions
+//
+// Notes: definitions are split into strongly connected components of the
+// graph where a definition points to each definition whose name it uses. Each
+// component only depends on itself and the components before it, so
+// components can be inferred in order, one [Let] or [Rec] at a time, and
+// independent definitions are generalized before the definitions using them
+// are inferred. Uses are found w/ (expr.Expression).Collect, so a bound
+// variable sharing a definition's name counts as a use; this can only merge
+// components, never split them
+// =============================================================================
+package inf
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// state of Tarjan's algorithm over the definitions in `defs`
+type dependencyGraph[N nameable.Nameable] struct {
+	defs    []expr.Def[N]
+	uses    [][]int // uses[i] are the indexes of the definitions defs[i] uses
+	index   []int   // order in which definitions were visited, 0 iff unvisited
+	low     []int
+	onStack []bool
+	stack   []int
+	visited int
+	groups  [][]expr.Def[N]
+}
+
+func newDependencyGraph[N nameable.Nameable](defs []expr.Def[N]) *dependencyGraph[N] {
+	indexOf := make(map[string]int, len(defs))
+	for i, def := range defs {
+		indexOf[def.GetName().Name.GetName()] = i
+	}
+
+	g := &dependencyGraph[N]{
+		defs:    defs,
+		uses:    make([][]int, len(defs)),
+		index:   make([]int, len(defs)),
+		low:     make([]int, len(defs)),
+		onStack: make([]bool, len(defs)),
+	}
+	for i, def := range defs {
+		used := make(map[int]bool)
+		for _, name := range def.GetAssignment().Collect() {
+			if j, found := indexOf[name.GetName()]; found && !used[j] {
+				used[j] = true
+				g.uses[i] = append(g.uses[i], j)
+			}
+		}
+		sort.Ints(g.uses[i])
+	}
+	return g
+}
+
+// visits definition `i`, adding each component found to `g.groups` after the
+// components it depends on
+func (g *dependencyGraph[N]) visit(i int) {
+	g.visited++
+	g.index[i], g.low[i] = g.visited, g.visited
+	g.stack = append(g.stack, i)
+	g.onStack[i] = true
+
+	for _, j := range g.uses[i] {
+		if g.index[j] == 0 {
+			g.visit(j)
+			if g.low[j] < g.low[i] {
+				g.low[i] = g.low[j]
+			}
+		} else if g.onStack[j] && g.index[j] < g.low[i] {
+			g.low[i] = g.index[j]
+		}
+	}
+
+	if g.low[i] != g.index[i] {
+		return // not the root of a component
+	}
+
+	var members []int
+	for {
+		top := g.stack[len(g.stack)-1]
+		g.stack = g.stack[:len(g.stack)-1]
+		g.onStack[top] = false
+		members = append(members, top)
+		if top == i {
+			break
+		}
+	}
+	// definitions of a component keep the order they were given in
+	sort.Ints(members)
+	group := make([]expr.Def[N], len(members))
+	for k, member := range members {
+		group[k] = g.defs[member]
+	}
+	g.groups = append(g.groups, group)
+}
+
+// returns true iff the definitions of `group` must be inferred together w/
+// [Rec]; i.e., `group` has more than one definition or its only definition
+// uses itself
+func isRecursive[N nameable.Nameable](group []expr.Def[N]) bool {
+	if len(group) != 1 {
+		return true
+	}
+	name := group[0].GetName().Name.GetName()
+	for _, used := range group[0].GetAssignment().Collect() {
+		if used.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splits `defs` into groups of mutually recursive definitions. Each group only
+// uses definitions in itself and in the groups before it
+func BindingGroups[N nameable.Nameable](defs []expr.Def[N]) [][]expr.Def[N] {
+	g := newDependencyGraph(defs)
+	for i := range defs {
+		if g.index[i] == 0 {
+			g.visit(i)
+		}
+	}
+	return g.groups
+}
+
+// nests `body` in one let- or rec-expression per group of BindingGroups(defs)
+//
+//	rec f = .. g .. and g = .. and h = .. h .. in e
+//
+// becomes
+//
+//	let g = .. in let f = .. g .. in rec h = .. h .. in e
+func Regroup[N nameable.Nameable](defs []expr.Def[N], body expr.Expression[N]) expr.Expression[N] {
+	groups := BindingGroups(defs)
+	for i := len(groups) - 1; i >= 0; i-- {
+		if group := groups[i]; isRecursive(group) {
+			body = expr.Rec(group...)(body)
+		} else {
+			body = expr.Let(group[0].GetName(), group[0].GetAssignment(), body)
+		}
+	}
+	return body
+}
+
+// infers the types of top-level definitions `defs` one group of BindingGroups(defs)
+// at a time and adds each definition's name to the context w/ its generalized
+// type. Returns the elaborated definitions (see Elaborate) in the order of their
+// groups. When inference fails, the returned definitions are nil
+func (cxt *Context[N]) InferDefinitions(defs []expr.Def[N]) ([]expr.Def[N], []errorReport[N]) {
+	out := make([]expr.Def[N], 0, len(defs))
+	for _, group := range BindingGroups(defs) {
+		elaborated, sigmas, stat := cxt.inferGroup(group)
+		if stat.NotOk() {
+			return nil, cxt.GetReports()
+		}
+		for i, def := range elaborated {
+			if !cxt.Add(def.GetName(), sigmas[i]) {
+				return nil, cxt.GetReports()
+			}
+		}
+		out = append(out, elaborated...)
+	}
+	return out, cxt.GetReports()
+}
+
+// returns the indexes of `groups`, groups returned by BindingGroups, split into
+// layers: each group only uses definitions in itself and in the groups of
+// earlier layers, so the groups of a layer can be inferred in any order
+func dependencyLayers[N nameable.Nameable](groups [][]expr.Def[N]) [][]int {
+	groupOf := make(map[string]int)
+	for i, group := range groups {
+		for _, def := range group {
+			groupOf[def.GetName().Name.GetName()] = i
+		}
+	}
+
+	var layers [][]int
+	layerOf := make([]int, len(groups))
+	for i, group := range groups {
+		for _, def := range group {
+			for _, name := range def.GetAssignment().Collect() {
+				// groups only use groups that come before them
+				if j, found := groupOf[name.GetName()]; found && j != i && layerOf[j]+1 > layerOf[i] {
+					layerOf[i] = layerOf[j] + 1
+				}
+			}
+		}
+		if layerOf[i] == len(layers) {
+			layers = append(layers, nil)
+		}
+		layers[layerOf[i]] = append(layers[layerOf[i]], i)
+	}
+	return layers
+}
+
+// like InferDefinitions, but the groups of definitions that do not depend on
+// each other are inferred concurrently by up to `workers` forked contexts (see
+// Fork). Definitions are returned and added to the context in the same order
+// as InferDefinitions would, but the names of the type variables they are
+// generalized over may differ. When more than one group fails, each failure is
+// reported
+func (cxt *Context[N]) InferDefinitionsConcurrently(defs []expr.Def[N], workers int) ([]expr.Def[N], []errorReport[N]) {
+	groups := BindingGroups(defs)
+	elaborated := make([][]expr.Def[N], len(groups))
+	sigmas := make([][]types.Type[N], len(groups))
+	stats := make([]Status, len(groups))
+
+	for _, layer := range dependencyLayers(groups) {
+		n := workers
+		if len(layer) < n {
+			n = len(layer)
+		}
+		if n < 1 {
+			n = 1
+		}
+
+		children := cxt.Fork(n)
+		var wg sync.WaitGroup
+		for w, child := range children {
+			wg.Add(1)
+			go func(w int, child *Context[N]) {
+				defer wg.Done()
+				for k := w; k < len(layer); k += n {
+					i := layer[k]
+					elaborated[i], sigmas[i], stats[i] = child.inferGroup(groups[i])
+				}
+			}(w, child)
+		}
+		wg.Wait()
+		cxt.Merge(children...)
+
+		for _, i := range layer {
+			if stats[i].NotOk() {
+				return nil, cxt.GetReports()
+			}
+		}
+		for _, i := range layer {
+			for k, def := range elaborated[i] {
+				if !cxt.Add(def.GetName(), sigmas[i][k]) {
+					return nil, cxt.GetReports()
+				}
+			}
+		}
+	}
+
+	out := make([]expr.Def[N], 0, len(defs))
+	for _, group := range elaborated {
+		out = append(out, group...)
+	}
+	return out, cxt.GetReports()
+}
+
+// infers the types of a group of definitions returned by BindingGroups w/
+// [Let] or [Rec], but returns the elaborated definitions and their types
+// instead of adding them to some expression's context
+func (cxt *Context[N]) inferGroup(group []expr.Def[N]) (elaborated []expr.Def[N], sigmas []types.Type[N], stat Status) {
+	names := make([]N, len(group))
+	for i, def := range group {
+		names[i] = def.GetName().Name
+	}
+	// holes are reported even when inference fails
+	defer cxt.reportHoles()
+
+	// discharges the assumptions of [Let] or [Rec]
+	var discharge func(TypeJudgment[N]) exprConclusion[N]
+
+	cxt.TypeContext.EnterLevel()
+	if !isRecursive(group) {
+		c := cxt.infer(group[0].GetAssignment())
+		cxt.TypeContext.LeaveLevel()
+		if c.NotOk() {
+			return nil, nil, c.Status
+		}
+		dischargeLet := cxt.Let(names[0], c.judgment)
+		discharge = func(j TypeJudgment[N]) exprConclusion[N] { return generalConclusion(dischargeLet(j)) }
+	} else {
+		dischargeRec := cxt.Rec(names)
+		js := make([]TypeJudgment[N], len(group))
+		for i, def := range group {
+			c := cxt.infer(def.GetAssignment())
+			if c.NotOk() {
+				cxt.TypeContext.LeaveLevel()
+				for _, def := range group {
+					cxt.Remove(def.GetName())
+				}
+				return nil, nil, c.Status
+			}
+			js[i] = c.judgment
+		}
+		cxt.TypeContext.LeaveLevel()
+		dischargeRec2 := dischargeRec(js)
+		discharge = func(j TypeJudgment[N]) exprConclusion[N] { return generalConclusion(dischargeRec2(j)) }
+	}
+
+	// read back the generalized types the rule added to the context; they are
+	// only used when the rule succeeds
+	sigmas = make([]types.Type[N], len(group))
+	for i, def := range group {
+		if judgedName, found := cxt.Get(def.GetName()); found {
+			sigmas[i], _ = judgedName.TypeAndExpr()
+		}
+	}
+
+	c := discharge(cxt.Judge(group[0].GetName()))
+	if c.NotOk() {
+		return nil, nil, c.Status
+	}
+	if stat = cxt.reportIndexConstraints(); stat.NotOk() {
+		return nil, nil, stat
+	}
+	switch e := c.judgment.GetExpression().(type) {
+	case expr.NameContext[N]:
+		elaborated = []expr.Def[N]{expr.Define(e.GetName(), e.GetAssignment())}
+	case expr.RecIn[N]:
+		elaborated = e.GetDefs()
+	}
+	return elaborated, sigmas, Ok
+}
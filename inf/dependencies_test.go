@@ -0,0 +1,182 @@
+package inf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestBindingGroups(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	def := func(s string, e expr.Expression[nameable.Testable]) expr.Def[nameable.Testable] {
+		return expr.Define(c(s), e)
+	}
+	groupString := func(group []expr.Def[nameable.Testable]) string {
+		names := fun.FMap(group, func(d expr.Def[nameable.Testable]) string { return d.GetName().String() })
+		return "[" + strings.Join(names, " ") + "]"
+	}
+
+	tests := []struct {
+		description string
+		defs        []expr.Def[nameable.Testable]
+		expect      string
+	}{
+		{
+			`f = g 0 and g = 0`,
+			[]expr.Def[nameable.Testable]{def("f", expr.Apply[nameable.Testable](c("g"), c("0"))), def("g", c("0"))},
+			"[g] [f]",
+		},
+		{
+			`f = g and g = f and h = h`,
+			[]expr.Def[nameable.Testable]{def("f", c("g")), def("g", c("f")), def("h", c("h"))},
+			"[f g] [h]",
+		},
+		{
+			`a = b and b = c and c = b and d = a`,
+			[]expr.Def[nameable.Testable]{def("a", c("b")), def("b", c("c")), def("c", c("b")), def("d", c("a"))},
+			"[b c] [a] [d]",
+		},
+	}
+
+	for i, test := range tests {
+		groups := BindingGroups(test.defs)
+		actual := strings.Join(fun.FMap(groups, groupString), " ")
+		if actual != test.expect {
+			t.Fatal(testutil.Testing("groups", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestRegroup(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x := expr.Var(name("x"))
+	// id = λx . x
+	id := expr.Define[nameable.Testable](c("id"), expr.Bind(x).In(x))
+	// p = pair (id 0) (id true)
+	p := expr.Define[nameable.Testable](c("p"), expr.Apply[nameable.Testable](c("pair"),
+		expr.Apply[nameable.Testable](c("id"), c("0")),
+		expr.Apply[nameable.Testable](c("id"), c("true")),
+	))
+
+	// rec id = λx . x and p = pair (id 0) (id true) in p
+	rec := expr.Rec(id, p)(c("p"))
+	cxt := makeRankTestContext()
+	sigma, reports := cxt.Infer(rec)
+	if len(reports) != 0 {
+		t.Fatal(testutil.Testing("reports").FailMessage(nil, reports, 0))
+	}
+	if actual := types.GetDependent(sigma.GetBound()).String(); actual != "(Pair Int Bool)" {
+		t.Fatal(testutil.Testing("type").FailMessage("(Pair Int Bool)", actual, 0))
+	}
+}
+
+func TestInferDefinitions(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x := expr.Var(name("x"))
+	defs := []expr.Def[nameable.Testable]{
+		// p = pair (id 0) (id true)
+		expr.Define[nameable.Testable](c("p"), expr.Apply[nameable.Testable](c("pair"),
+			expr.Apply[nameable.Testable](c("id"), c("0")),
+			expr.Apply[nameable.Testable](c("id"), c("true")),
+		)),
+		// id = λx . x
+		expr.Define[nameable.Testable](c("id"), expr.Bind(x).In(x)),
+	}
+
+	cxt := makeRankTestContext()
+	elaborated, reports := cxt.InferDefinitions(defs)
+	if len(reports) != 0 {
+		t.Fatal(testutil.Testing("reports").FailMessage(nil, reports, 0))
+	}
+	if actual := fun.FMap(elaborated, func(d expr.Def[nameable.Testable]) string { return d.GetName().String() }); len(actual) != 2 || actual[0] != "id" {
+		t.Fatal(testutil.Testing("order").FailMessage("[id p]", actual, 0))
+	}
+
+	expects := map[string]string{
+		"id": "forall $0 . ($0 -> $0)",
+		"p":  "(Pair Int Bool)",
+	}
+	for s, expect := range expects {
+		judged, found := cxt.Get(c(s))
+		if !found {
+			t.Fatal(testutil.Testing("found", s).FailMessage(true, found, 0))
+		}
+		if ty, _ := judged.TypeAndExpr(); ty.String() != expect {
+			t.Fatal(testutil.Testing("type", s).FailMessage(expect, ty.String(), 0))
+		}
+	}
+}
+
+func TestInferDefinitionsConcurrently(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x, y := expr.Var(name("x")), expr.Var(name("y"))
+	defs := []expr.Def[nameable.Testable]{
+		// p = pair (id 0) (k true 0)
+		expr.Define[nameable.Testable](c("p"), expr.Apply[nameable.Testable](c("pair"),
+			expr.Apply[nameable.Testable](c("id"), c("0")),
+			expr.Apply[nameable.Testable](c("k"), c("true"), c("0")),
+		)),
+		// id = λx . x
+		expr.Define[nameable.Testable](c("id"), expr.Bind(x).In(x)),
+		// k = λx y . x
+		expr.Define[nameable.Testable](c("k"), expr.Bind(x, y).In(x)),
+		// q = pair (k 0 true) (id true)
+		expr.Define[nameable.Testable](c("q"), expr.Apply[nameable.Testable](c("pair"),
+			expr.Apply[nameable.Testable](c("k"), c("0"), c("true")),
+			expr.Apply[nameable.Testable](c("id"), c("true")),
+		)),
+	}
+
+	tests := []struct {
+		workers int
+		expect  map[string]string
+	}{
+		{1, map[string]string{"p": "(Pair Int Bool)", "q": "(Pair Int Bool)"}},
+		{2, map[string]string{"p": "(Pair Int Bool)", "q": "(Pair Int Bool)"}},
+		{4, map[string]string{"p": "(Pair Int Bool)", "q": "(Pair Int Bool)"}},
+	}
+
+	for i, test := range tests {
+		cxt := makeRankTestContext()
+		elaborated, reports := cxt.InferDefinitionsConcurrently(defs, test.workers)
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("reports").FailMessage(nil, reports, i))
+		}
+		order := fun.FMap(elaborated, func(d expr.Def[nameable.Testable]) string { return d.GetName().String() })
+		if expect := "id k p q"; strings.Join(order, " ") != expect {
+			t.Fatal(testutil.Testing("order").FailMessage(expect, order, i))
+		}
+
+		for s, expect := range test.expect {
+			judged, found := cxt.Get(c(s))
+			if !found {
+				t.Fatal(testutil.Testing("found", s).FailMessage(true, found, i))
+			}
+			if ty, _ := judged.TypeAndExpr(); ty.String() != expect {
+				t.Fatal(testutil.Testing("type", s).FailMessage(expect, ty.String(), i))
+			}
+		}
+
+		// variables of definitions inferred by different children are distinct
+		binders := make(map[string]bool)
+		for _, s := range []string{"id", "k"} {
+			judged, _ := cxt.Get(c(s))
+			ty, _ := judged.TypeAndExpr()
+			for _, v := range ty.(types.Polytype[nameable.Testable]).GetBinders() {
+				if binders[v.GetName()] {
+					t.Fatal(testutil.Testing("binders", s).FailMessage("distinct binders", v.GetName(), i))
+				}
+				binders[v.GetName()] = true
+			}
+		}
+	}
+}
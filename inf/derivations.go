@@ -0,0 +1,325 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: recording derivations--the tree of rule applications that
+// concluded the type of an expression--and rendering them as indented text,
+// as LaTeX (w/ the bussproofs package), and as JSON
+//
+// Notes: recording is off unless RecordDerivations is called. Every
+// application of an inference rule and of a checking rule is recorded along
+// w/ the rule applications its premises needed, the substitutions made while
+// it was applied (but not while its premises were), and the type variables it
+// created (but its premises did not). A conclusion's type is recorded w/ the
+// substitutions made by the time the rule concluded it, so later
+// substitutions are not reflected. Rules applied by contexts returned by
+// Fork are not recorded
+// =============================================================================
+package inf
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// variable (of a type or of an index) and what it was substituted by
+type Substitution struct {
+	Variable string
+	Value    string
+}
+
+// application of a rule
+//
+//	Premises[0] .. Premises[N]
+//	-------------------------- [Rule]
+//	Expression: Type
+type Derivation[N nameable.Nameable] struct {
+	Rule       string
+	Expression expr.Expression[N]
+	// type concluded, or nil when the rule failed
+	Type types.Monotyped[N]
+	// true iff the rule was a checking rule, i.e., `Expression ⇐ Type`
+	Checked bool
+	Status  Status
+	// derivations of the premises, in the order they were derived
+	Premises      []*Derivation[N]
+	Substitutions []Substitution
+	// type variables created by the rule, excluding those of its premises
+	FreshVariables []types.Variable[N]
+}
+
+// derivation that has not concluded yet
+type derivationFrame[N nameable.Nameable] struct {
+	derivation *Derivation[N]
+	// variable counter when the rule was first applied
+	counter uint32
+	// names of the variables created by the premises
+	inner map[string]bool
+}
+
+type derivationRecorder[N nameable.Nameable] struct {
+	roots  []*Derivation[N]
+	frames []derivationFrame[N]
+}
+
+// starts recording derivations, discarding any recorded before
+func (cxt *Context[N]) RecordDerivations() {
+	cxt.derivations = &derivationRecorder[N]{}
+}
+
+// stops recording derivations, returning the derivations recorded since
+// RecordDerivations was called--one for each expression whose type was
+// inferred or checked, in order
+func (cxt *Context[N]) StopRecordingDerivations() []*Derivation[N] {
+	if cxt.derivations == nil {
+		return nil
+	}
+	roots := cxt.derivations.roots
+	cxt.derivations = nil
+	return roots
+}
+
+// returns name of the inference rule that concludes the type of `e`
+func ruleName[N nameable.Nameable](e expr.Expression[N]) string {
+	switch e.(type) {
+	case expr.Const[N], expr.Variable[N]:
+		return "Var"
+	case expr.Application[N]:
+		return "App"
+	case expr.Function[N]:
+		return "Abs"
+	case expr.NameContext[N]:
+		return "Let"
+	case expr.RecIn[N]:
+		return "Rec"
+	case expr.Selection[N]:
+		return "Case"
+	case expr.List[N]:
+		return "List"
+	case expr.Record[N]:
+		return "Record"
+	case expr.Access[N]:
+		return "Access"
+	case expr.Extension[N]:
+		return "Extend"
+	case expr.Hole[N]:
+		return "Hole"
+	case bridge.Prim[N]:
+		return "Prim"
+	case bridge.Data[N]:
+		return "Data"
+	case bridge.JudgmentAsExpression[N, expr.Expression[N]]:
+		return "Annot"
+	default:
+		return "Infer"
+	}
+}
+
+// applies rule `rule` to `e` by calling `apply`, recording the application when
+// derivations are being recorded. `expected` is the type pushed into `e` by a
+// checking rule and nil for inference rules
+func (cxt *Context[N]) derive(rule string, e expr.Expression[N], expected types.Monotyped[N], apply func() exprConclusion[N]) exprConclusion[N] {
+	recorder := cxt.derivations
+	if recorder == nil {
+		return apply()
+	}
+
+	d := &Derivation[N]{Rule: rule, Expression: e, Checked: expected != nil}
+	recorder.frames = append(recorder.frames, derivationFrame[N]{d, cxt.TypeContext.GetVarCounter(), map[string]bool{}})
+	c := apply()
+	frame := recorder.frames[len(recorder.frames)-1]
+	recorder.frames = recorder.frames[:len(recorder.frames)-1]
+
+	d.Status = c.Status
+	if c.IsOk() {
+		d.Type = cxt.GetSub(c.judgment.GetType())
+	}
+	created := cxt.TypeContext.VarsSince(frame.counter)
+	d.FreshVariables = fun.Filter(func(v types.Variable[N]) bool { return !frame.inner[v.String()] }, created)
+
+	if len(recorder.frames) == 0 {
+		recorder.roots = append(recorder.roots, d)
+		return c
+	}
+	parent := recorder.frames[len(recorder.frames)-1]
+	parent.derivation.Premises = append(parent.derivation.Premises, d)
+	for _, v := range created {
+		parent.inner[v.String()] = true
+	}
+	return c
+}
+
+// records that `v` was substituted by `value` during the rule being applied
+func (cxt *Context[N]) recordSubstitution(v, value interface{ String() string }) {
+	if cxt.derivations == nil || len(cxt.derivations.frames) == 0 {
+		return
+	}
+	d := cxt.derivations.frames[len(cxt.derivations.frames)-1].derivation
+	d.Substitutions = append(d.Substitutions, Substitution{v.String(), value.String()})
+}
+
+// returns `Expression: Type` or `Expression ⇐ Type`; when the rule failed, the
+// status replaces the type
+func (d *Derivation[N]) judgmentString() string {
+	if d.Type == nil {
+		return d.Expression.String() + " -- " + d.Status.String()
+	}
+	if d.Checked {
+		return d.Expression.String() + " ⇐ " + d.Type.String()
+	}
+	return d.Expression.String() + ": " + d.Type.String()
+}
+
+// returns `v1 := t1, .., vN := tN`
+func (d *Derivation[N]) substitutionsString() string {
+	subs := fun.FMap(d.Substitutions, func(s Substitution) string { return s.Variable + " := " + s.Value })
+	return strings.Join(subs, ", ")
+}
+
+// returns `v1, .., vN`
+func (d *Derivation[N]) freshString() string {
+	vars := fun.FMap(d.FreshVariables, func(v types.Variable[N]) string { return v.String() })
+	return strings.Join(vars, ", ")
+}
+
+// renders derivation as an indented tree: the conclusion of each rule comes
+// first, followed by the variables it created and the substitutions it made,
+// followed by its premises indented by two more spaces
+//
+//	[App] (pair 0): ($1 -> (Pair Int $1))
+//	  fresh: $2
+//	  substitutions: $0 := Int, $2 := ($1 -> (Pair $0 $1))
+//	  [Var] pair: ($0 -> ($1 -> (Pair $0 $1)))
+//	    fresh: $0, $1
+//	  [Var] 0: Int
+func (d *Derivation[N]) String() string {
+	var b strings.Builder
+	d.writeTree(&b, "")
+	return b.String()
+}
+
+func (d *Derivation[N]) writeTree(b *strings.Builder, indent string) {
+	b.WriteString(indent + "[" + d.Rule + "] " + d.judgmentString() + "\n")
+	if len(d.FreshVariables) != 0 {
+		b.WriteString(indent + "  fresh: " + d.freshString() + "\n")
+	}
+	if len(d.Substitutions) != 0 {
+		b.WriteString(indent + "  substitutions: " + d.substitutionsString() + "\n")
+	}
+	for _, premise := range d.Premises {
+		premise.writeTree(b, indent+"  ")
+	}
+}
+
+// most premises a bussproofs inference can have
+const maxLaTeXPremises int = 5
+
+var latexEscapes = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`^`, `\^{}`,
+	`~`, `\~{}`,
+	`λ`, `$\lambda$`,
+	`⇐`, `$\Leftarrow$`,
+)
+
+// renders derivation as a bussproofs proof tree. The rule's name labels each
+// inference on its right, and the variables it created and the substitutions
+// it made label it on its left. bussproofs allows at most five premises, so
+// the premises of a rule w/ more than five are elided after the fourth
+func (d *Derivation[N]) LaTeX() string {
+	var b strings.Builder
+	b.WriteString("\\begin{prooftree}\n")
+	d.writeLaTeX(&b)
+	b.WriteString("\\end{prooftree}\n")
+	return b.String()
+}
+
+func (d *Derivation[N]) writeLaTeX(b *strings.Builder) {
+	premises := d.Premises
+	if len(premises) > maxLaTeXPremises {
+		premises = premises[:maxLaTeXPremises-1]
+	}
+	for _, premise := range premises {
+		premise.writeLaTeX(b)
+	}
+
+	n := len(premises)
+	switch {
+	case n == 0:
+		b.WriteString("\\AxiomC{}\n")
+		n = 1
+	case len(d.Premises) > maxLaTeXPremises:
+		b.WriteString("\\AxiomC{$\\cdots$}\n")
+		n++
+	}
+
+	var labels []string
+	if len(d.FreshVariables) != 0 {
+		labels = append(labels, "fresh "+d.freshString())
+	}
+	if len(d.Substitutions) != 0 {
+		labels = append(labels, d.substitutionsString())
+	}
+	if len(labels) != 0 {
+		b.WriteString("\\LeftLabel{\\scriptsize " + latexEscapes.Replace(strings.Join(labels, "; ")) + "}\n")
+	}
+	b.WriteString("\\RightLabel{\\scriptsize " + latexEscapes.Replace(d.Rule) + "}\n")
+
+	inference := [...]string{"Unary", "Binary", "Trinary", "Quaternary", "Quinary"}[n-1]
+	b.WriteString("\\" + inference + "InfC{\\texttt{" + latexEscapes.Replace(d.judgmentString()) + "}}\n")
+}
+
+type encodedDerivation struct {
+	Rule       string `json:"rule"`
+	Expression string `json:"expression"`
+	// omitted when the rule failed
+	Type           string                `json:"type,omitempty"`
+	Checked        bool                  `json:"checked"`
+	Status         string                `json:"status"`
+	FreshVariables []string              `json:"freshVariables"`
+	Substitutions  []encodedSubstitution `json:"substitutions"`
+	Premises       []encodedDerivation   `json:"premises"`
+}
+
+type encodedSubstitution struct {
+	Variable string `json:"variable"`
+	Value    string `json:"value"`
+}
+
+func (d *Derivation[N]) encode() encodedDerivation {
+	out := encodedDerivation{
+		Rule:           d.Rule,
+		Expression:     d.Expression.String(),
+		Checked:        d.Checked,
+		Status:         d.Status.String(),
+		FreshVariables: fun.FMap(d.FreshVariables, func(v types.Variable[N]) string { return v.String() }),
+		Substitutions: fun.FMap(d.Substitutions, func(s Substitution) encodedSubstitution {
+			return encodedSubstitution{s.Variable, s.Value}
+		}),
+		Premises: fun.FMap(d.Premises, (*Derivation[N]).encode),
+	}
+	if d.Type != nil {
+		out.Type = d.Type.String()
+	}
+	return out
+}
+
+// encodes derivation as a JSON object w/ the fields `rule`, `expression`,
+// `type` (absent when the rule failed), `checked`, `status`, `freshVariables`,
+// `substitutions` (objects w/ the fields `variable` and `value`), and
+// `premises` (encoded derivations)
+func (d *Derivation[N]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.encode())
+}
@@ -0,0 +1,142 @@
+package inf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestDerivations(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	x := expr.Var(name("x"))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expect      string
+	}{
+		{
+			`0`,
+			c("0"),
+			"[Var] 0: Int\n",
+		},
+		{
+			`pair 0`,
+			expr.Apply[nameable.Testable](c("pair"), c("0")),
+			"[App] (pair 0): ($1 -> (Pair Int $1))\n" +
+				"  fresh: $2\n" +
+				"  substitutions: $0 := Int, $2 := ($1 -> (Pair $0 $1))\n" +
+				"  [Var] pair: ($0 -> ($1 -> (Pair $0 $1)))\n" +
+				"    fresh: $0, $1\n" +
+				"  [Var] 0: Int\n",
+		},
+		{
+			`λx . [0, x]`,
+			expr.Bind(x).In(expr.List[nameable.Testable]{c("0"), x}),
+			"[Abs] (λx . [0, x]): (Int -> [Int])\n" +
+				"  fresh: $0\n" +
+				"  [List] [0, x]: [Int]\n" +
+				"    fresh: $1\n" +
+				"    substitutions: $1 := Int, $0 := Int\n" +
+				"    [Var] 0: Int\n" +
+				"    [Var] x: $0\n",
+		},
+		{
+			`0 0`,
+			expr.Apply[nameable.Testable](c("0"), c("0")),
+			"[App] (0 0) -- ConstantMismatch\n" +
+				"  fresh: $0\n" +
+				"  [Var] 0: Int\n" +
+				"  [Var] 0: Int\n",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeRankTestContext()
+		cxt.RecordDerivations()
+		cxt.Infer(test.input)
+		derivations := cxt.StopRecordingDerivations()
+		if len(derivations) != 1 {
+			t.Fatal(testutil.Testing("roots", test.description).FailMessage(1, len(derivations), i))
+		}
+		if actual := derivations[0].String(); actual != test.expect {
+			t.Fatal(testutil.Testing("tree", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestCheckedDerivations(t *testing.T) {
+	name := nameable.MakeTestable
+	x := expr.Var(name("x"))
+	Int := types.MakeConst(name("Int"))
+	cxt := makeRankTestContext()
+	id := expr.Bind(x).In(expr.Expression[nameable.Testable](x))
+
+	cxt.RecordDerivations()
+	cxt.Check(id, cxt.TypeContext.Function(Int, Int))
+	derivations := cxt.StopRecordingDerivations()
+
+	expect := "[Abs] (λx . x) ⇐ (Int -> Int)\n" +
+		"  [Var] x: Int\n"
+	if len(derivations) != 1 || derivations[0].String() != expect {
+		t.Fatal(testutil.Testing("tree").FailMessage(expect, derivations, 0))
+	}
+}
+
+func TestRenderDerivations(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	cxt := makeRankTestContext()
+
+	cxt.RecordDerivations()
+	cxt.Infer(expr.Apply[nameable.Testable](c("pair"), c("0")))
+	d := cxt.StopRecordingDerivations()[0]
+
+	expectLaTeX := "\\begin{prooftree}\n" +
+		"\\AxiomC{}\n" +
+		"\\LeftLabel{\\scriptsize fresh \\$0, \\$1}\n" +
+		"\\RightLabel{\\scriptsize Var}\n" +
+		"\\UnaryInfC{\\texttt{pair: (\\$0 -> (\\$1 -> (Pair \\$0 \\$1)))}}\n" +
+		"\\AxiomC{}\n" +
+		"\\RightLabel{\\scriptsize Var}\n" +
+		"\\UnaryInfC{\\texttt{0: Int}}\n" +
+		"\\LeftLabel{\\scriptsize fresh \\$2; \\$0 := Int, \\$2 := (\\$1 -> (Pair \\$0 \\$1))}\n" +
+		"\\RightLabel{\\scriptsize App}\n" +
+		"\\BinaryInfC{\\texttt{(pair 0): (\\$1 -> (Pair Int \\$1))}}\n" +
+		"\\end{prooftree}\n"
+	if actual := d.LaTeX(); actual != expectLaTeX {
+		t.Fatal(testutil.Testing("latex").FailMessage(expectLaTeX, actual, 0))
+	}
+
+	bytes, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(testutil.Testing("json").FailMessage(nil, err, 0))
+	}
+	var decoded struct {
+		Rule          string `json:"rule"`
+		Type          string `json:"type"`
+		Substitutions []struct {
+			Variable string `json:"variable"`
+		} `json:"substitutions"`
+		Premises []struct {
+			Expression     string   `json:"expression"`
+			FreshVariables []string `json:"freshVariables"`
+		} `json:"premises"`
+	}
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatal(testutil.Testing("json").FailMessage(nil, err, 0))
+	}
+	ok := decoded.Rule == "App" &&
+		decoded.Type == "($1 -> (Pair Int $1))" &&
+		len(decoded.Substitutions) == 2 && decoded.Substitutions[0].Variable == "$0" &&
+		len(decoded.Premises) == 2 && decoded.Premises[0].Expression == "pair" &&
+		len(decoded.Premises[0].FreshVariables) == 2
+	if !ok {
+		t.Fatal(testutil.Testing("json").FailMessage("App derivation", string(bytes), 0))
+	}
+}
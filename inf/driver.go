@@ -0,0 +1,381 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: Algorithm W driver--walks an expression, applying the inference
+// rules defined in rules.go to each of its sub-expressions
+//
+// Notes: -
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// conclusion for an arbitrary expression
+type exprConclusion[N nameable.Nameable] Conclusion[N, expr.Expression[N], types.Monotyped[N]]
+
+// forgets the concrete expression type of a conclusion
+func generalConclusion[N nameable.Nameable, E expr.Expression[N]](c Conclusion[N, E, types.Monotyped[N]]) exprConclusion[N] {
+	if c.NotOk() {
+		return cannotInfer[N](c.Status)
+	}
+	out := concludeInferred[N](c.judgment.GetExpression(), c.judgment.GetType())
+	out.core = c.core
+	return out
+}
+
+// attaches the core term `build` creates from the type of `c`; failed
+// conclusions are returned as they are
+func (c exprConclusion[N]) withCore(build func(t types.Monotyped[N]) Core[N]) exprConclusion[N] {
+	if c.NotOk() {
+		return c
+	}
+	c.core = build(c.judgment.GetType())
+	return c
+}
+
+func concludeInferred[N nameable.Nameable](e expr.Expression[N], t types.Monotyped[N]) exprConclusion[N] {
+	return exprConclusion[N](Conclude[N](e, t))
+}
+
+func cannotInfer[N nameable.Nameable](stat Status) exprConclusion[N] {
+	return exprConclusion[N](CannotConclude[N, expr.Expression[N], types.Monotyped[N]](stat))
+}
+
+// infers the most general type of `e`, returning it along w/ all error
+// reports generated so far. When inference fails, the reports explain why and
+// the returned polytype is `forall a . a`, the type of an expression that
+// never produces a value; it can be printed, but must not be used as the type
+// of `e` when the reports are non-empty. Holes (see expr.Hole) do not make
+// inference fail; each is reported as a warning instead
+//
+//	𝚪 ⊢ e: t
+//	-------------
+//	e: Gen(t)
+//
+// The class constraints on the returned polytype are dropped; see Elaborate
+func (cxt *Context[N]) Infer(e expr.Expression[N]) (types.Polytype[N], []errorReport[N]) {
+	_, ty, reports := cxt.Elaborate(e)
+	switch sigma := ty.(type) {
+	case types.Qualified[N]:
+		return sigma.GetPolytype(), reports
+	case types.Polytype[N]:
+		return sigma, reports
+	}
+	a := cxt.TypeContext.NewVar()
+	return types.Forall(a).Bind(a), reports
+}
+
+// like Infer, but also returns `e` elaborated into dictionary-passing style,
+// and the returned type is qualified by the class constraints on it (see
+// types.Qualified). When inference fails, the returned expression and type
+// are nil
+//
+//	𝚪 ⊢ e: t
+//	------------------------
+//	λd1 .. dK . e: P => Gen(t)
+func (cxt *Context[N]) Elaborate(e expr.Expression[N]) (expr.Expression[N], types.Type[N], []errorReport[N]) {
+	conclusion := cxt.infer(e)
+	cxt.reportHoles()
+	if conclusion.NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+	if cxt.reportIndexConstraints().NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+
+	t := cxt.GetSub(conclusion.judgment.GetType())
+	sigma := cxt.Gen(t)
+	context, stat := cxt.resolveWanted([]types.Polytype[N]{sigma}, true)
+	if stat.NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+
+	elaborated := cxt.abstractEvidence(context, conclusion.judgment.GetExpression())
+	return elaborated, qualify(sigma, context), cxt.GetReports()
+}
+
+// like Elaborate, but `e` is elaborated into the explicitly typed core language
+// (see Core). Every type in the returned term is fully substituted. When
+// inference fails, the returned term and type are nil
+//
+//	𝚪 ⊢ e: t
+//	-------------------------------------
+//	(Λa1 .. aN . λd1 .. dK . e): P => Gen(t)
+func (cxt *Context[N]) ElaborateCore(e expr.Expression[N]) (Core[N], types.Type[N], []errorReport[N]) {
+	conclusion := cxt.infer(e)
+	cxt.reportHoles()
+	if conclusion.NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+	if cxt.reportIndexConstraints().NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+
+	t := cxt.GetSub(conclusion.judgment.GetType())
+	sigma := cxt.Gen(t)
+	context, stat := cxt.resolveWanted([]types.Polytype[N]{sigma}, true)
+	if stat.NotOk() {
+		return nil, nil, cxt.GetReports()
+	}
+
+	ty := qualify(sigma, context)
+	core := coreGeneralization(bindersOf[N](sigma), context, conclusion.core, ty)
+	return core.zonk(cxt), ty, cxt.GetReports()
+}
+
+// dispatches `e` to the rule(s) that can conclude its type; the application
+// is recorded when derivations are (see RecordDerivations)
+func (cxt *Context[N]) infer(e expr.Expression[N]) exprConclusion[N] {
+	defer cxt.enterSpan(e)()
+	if cxt.derivations == nil {
+		return cxt.applyRule(e)
+	}
+	return cxt.derive(ruleName(e), e, nil, func() exprConclusion[N] { return cxt.applyRule(e) })
+}
+
+func (cxt *Context[N]) applyRule(e expr.Expression[N]) exprConclusion[N] {
+	switch x := e.(type) {
+	case expr.Const[N]:
+		return cxt.inferConst(x)
+	case expr.Variable[N]:
+		// free variable, look it up by name
+		return cxt.inferConst(expr.Const[N]{Name: x.GetReferred()})
+	case bridge.Prim[N]:
+		return generalConclusion(cxt.Primitive(x))
+	case expr.Application[N]:
+		return cxt.inferApplication(x)
+	case expr.Function[N]:
+		return cxt.inferFunction(x)
+	case expr.NameContext[N]:
+		return cxt.inferNameContext(x)
+	case expr.RecIn[N]:
+		return cxt.inferRecIn(x)
+	case expr.Selection[N]:
+		return cxt.inferSelection(x)
+	case expr.List[N]:
+		return cxt.inferList(x)
+	case expr.Record[N]:
+		return cxt.inferRecord(x)
+	case expr.Access[N]:
+		return cxt.inferAccess(x)
+	case expr.Extension[N]:
+		return cxt.inferExtension(x)
+	case expr.Hole[N]:
+		return generalConclusion(cxt.Hole(x))
+	case bridge.Data[N]:
+		return cxt.inferData(x)
+	case bridge.JudgmentAsExpression[N, expr.Expression[N]]:
+		return cxt.inferAnnotation(x)
+	default:
+		cxt.appendReport(makeReport("Infer", UnsupportedExpression, cxt.Judge(e)))
+		return cannotInfer[N](UnsupportedExpression)
+	}
+}
+
+// searches all types in the constructor table for a constructor named `name`
+func (cxt *Context[N]) findConstructor(name N) (constructor types.TypedJudgment[N, expr.Function[N], types.Polytype[N]], found bool) {
+	cxt.consTable.ForEach(func(_ nameable.Nameable, cj consJudge[N]) bool {
+		constructor, found = cj.Find(name)
+		return !found
+	})
+	return
+}
+
+// names bound by the context take precedence over data constructors
+func (cxt *Context[N]) inferConst(x expr.Const[N]) exprConclusion[N] {
+	judged, found := cxt.Get(x)
+	if !found {
+		if constructor, isConstructor := cxt.findConstructor(x.Name); isConstructor {
+			sigma := constructor.GetType()
+			t, args := cxt.instantiate(sigma)
+			return concludeInferred[N](x, t).withCore(func(t types.Monotyped[N]) Core[N] {
+				return coreInstance[N](x.Name, sigma, args, nil, t)
+			})
+		}
+		return generalConclusion(cxt.Var(x))
+	}
+
+	// names w/ qualified types are elaborated
+	ty, _ := judged.TypeAndExpr()
+	if q, isQualified := ty.(types.Qualified[N]); isQualified {
+		return generalConclusion(cxt.Overloaded(x, q))
+	}
+	return generalConclusion(cxt.Var(x))
+}
+
+// arguments are checked against nested polytypes (see types.Nested) instead of
+// being inferred
+func (cxt *Context[N]) inferApplication(a expr.Application[N]) exprConclusion[N] {
+	left, right := a.Split()
+	c0 := cxt.infer(left)
+	if c0.NotOk() {
+		return c0
+	}
+
+	if t1, t2, ok := cxt.splitFunction(c0.judgment.GetType()); ok {
+		if nested, isNested := t1.(types.Nested[N]); isNested {
+			c1 := cxt.checkSigma(right, nested.GetPolytype())
+			if c1.NotOk() {
+				return c1
+			}
+			e := expr.Apply(c0.judgment.GetExpression(), c1.judgment.GetExpression())
+			return concludeInferred[N](e, cxt.GetSub(t2)).withCore(func(t types.Monotyped[N]) Core[N] {
+				return CoreApp[N]{c0.core, c1.core, t}
+			})
+		}
+	}
+
+	c1 := cxt.infer(right)
+	if c1.NotOk() {
+		return c1
+	}
+	return generalConclusion(cxt.App(c0.judgment, c1.judgment)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreApp[N]{c0.core, c1.core, t}
+	})
+}
+
+// creates a fresh name that cannot clash w/ a name bound by the context,
+// returning the name as both a variable and a constant
+func (cxt *Context[N]) freshName() (expr.Variable[N], expr.Const[N]) {
+	v := cxt.ExprContext.NewVar()
+	return v, expr.Const[N]{Name: v.GetReferred()}
+}
+
+// returns the name that replaces `binder` when its scope is opened, as both a
+// variable and a constant. The name is the binder's own unless the scope,
+// i.e., `bodies`, already mentions a constant w/ that name, which the binder
+// would capture; then the name is fresh
+func (cxt *Context[N]) binderName(binder expr.Variable[N], bodies ...expr.Expression[N]) (expr.Variable[N], expr.Const[N]) {
+	// made from the name's string, so it is not located at the binder
+	v := cxt.ExprContext.Var(binder.GetReferred().GetName())
+	c := expr.Const[N]{Name: v.GetReferred()}
+	for _, body := range bodies {
+		if !body.BodyAbstract(v, c).StrictEquals(body) {
+			return cxt.freshName()
+		}
+	}
+	return v, c
+}
+
+func (cxt *Context[N]) inferFunction(f expr.Function[N]) exprConclusion[N] {
+	binders := f.GetBinders()
+	discharges := make([]func(TypeJudgment[N]) Conclusion[N, expr.Function[N], types.Monotyped[N]], len(binders))
+	params := make([]expr.Const[N], len(binders))
+
+	// open each binder, replacing the bound variable w/ a constant
+	var body expr.Expression[N] = f
+	for i := range binders {
+		v, param := cxt.binderName(binders[i], body)
+		params[i] = param
+		body = body.(expr.Function[N]).Instantiate(param)
+		discharges[i] = cxt.abs(param.Name, func() expr.Variable[N] { return v })
+	}
+
+	c := cxt.infer(body)
+	if c.NotOk() {
+		for _, param := range params {
+			cxt.Remove(param)
+		}
+		return c
+	}
+
+	// discharge assumptions, inner-most first
+	var j TypeJudgment[N] = c.judgment
+	core := c.core
+	for i := len(discharges) - 1; i >= 0; i-- {
+		judged, _ := cxt.Get(params[i])
+		paramType, _ := judged.TypeAndExpr()
+		j = discharges[i](j).judgment
+		_, t := j.GetExpressionAndType()
+		core = CoreLambda[N]{params[i].Name, paramType, core, t.(types.Monotyped[N])}
+	}
+	e, t := GetExpressionAndType[N, expr.Expression[N], types.Monotyped[N]](j)
+	out := concludeInferred(e, t)
+	out.core = core
+	return out
+}
+
+func (cxt *Context[N]) inferNameContext(let expr.NameContext[N]) exprConclusion[N] {
+	return cxt.checkNameContext(let, cxt.TypeContext.NewVar())
+}
+
+func (cxt *Context[N]) inferRecIn(rec expr.RecIn[N]) exprConclusion[N] {
+	return cxt.checkRecIn(rec, cxt.TypeContext.NewVar())
+}
+
+// all elements of a list must have the same type
+//
+//	𝚪 ⊢ e1: t   ...   𝚪 ⊢ eN: t
+//	---------------------------
+//	   𝚪 ⊢ [e1, .., eN]: [t]
+func (cxt *Context[N]) inferList(ls expr.List[N]) exprConclusion[N] {
+	elem := cxt.TypeContext.NewVar()
+	out := make(expr.List[N], len(ls))
+	elems := make([]Core[N], len(ls))
+	for i, e := range ls {
+		c := cxt.infer(e)
+		if c.NotOk() {
+			return c
+		}
+		if stat := cxt.Unify(elem, c.judgment.GetType()); stat.NotOk() {
+			cxt.appendReport(makeReport[N]("List", stat, c.judgment))
+			return cannotInfer[N](stat)
+		}
+		out[i], elems[i] = c.judgment.GetExpression(), c.core
+	}
+
+	listType := types.Apply[N](cxt.TypeContext.EnclosingCon(1, "[]"), cxt.GetSub(elem))
+	return concludeInferred[N](out, listType).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreList[N]{elems, t}
+	})
+}
+
+// data is typed as the application of its constructor to its members
+func (cxt *Context[N]) inferData(data bridge.Data[N]) exprConclusion[N] {
+	tag := data.GetTag()
+	constructor, found := cxt.findConstructor(tag.Name)
+	if !found {
+		cxt.appendReport(makeNameReport("Data", NameNotInContext, tag))
+		return cannotInfer[N](NameNotInContext)
+	}
+
+	t := cxt.Inst(constructor.GetType())
+	members := make([]bridge.JudgmentAsExpression[N, expr.Expression[N]], len(data.Members))
+	cores := make([]Core[N], len(data.Members))
+	for i, member := range data.Members {
+		c := cxt.infer(member)
+		if c.NotOk() {
+			return c
+		}
+		// `t` must be a function from the member's type to the rest
+		e, tm := c.judgment.GetExpressionAndType()
+		rest := cxt.TypeContext.NewVar()
+		if stat := cxt.Unify(t, cxt.TypeContext.Function(tm.(types.Monotyped[N]), rest)); stat.NotOk() {
+			cxt.appendReport(makeReport[N]("Data", stat, c.judgment))
+			return cannotInfer[N](stat)
+		}
+		// a member w/ a qualified annotation is elaborated to an application
+		// of the judgment to dictionaries; it is judged again to stay a member
+		member, isJudgment := e.(bridge.JudgmentAsExpression[N, expr.Expression[N]])
+		if !isJudgment {
+			member = bridge.Judgment(e, types.Type[N](tm))
+		}
+		members[i], cores[i] = member, c.core
+		t = rest
+	}
+
+	return concludeInferred[N](bridge.MakeData(tag, members...), cxt.GetSub(t)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreData[N]{tag.Name, cores, t}
+	})
+}
+
+// checks judged expression against the type it's judged to have; see Annot
+func (cxt *Context[N]) inferAnnotation(j bridge.JudgmentAsExpression[N, expr.Expression[N]]) exprConclusion[N] {
+	ty, e := j.TypeAndExpr()
+	return generalConclusion(cxt.Annot(e, ty))
+}
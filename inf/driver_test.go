@@ -0,0 +1,252 @@
+package inf
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// creates a context w/
+//
+//	0: Int
+//	Maybe a = Just a
+func makeDriverTestContext() *Context[nameable.Testable] {
+	cxt := NewTestableContext()
+	zero := expr.Const[nameable.Testable]{Name: nameable.MakeTestable("0")}
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	cxt.Add(zero, Int)
+
+	maybeName := nameable.MakeTestable("Maybe")
+	a := types.Var(nameable.MakeTestable("a"))
+	Maybe_a := types.Apply[nameable.Testable](types.MakeConst(maybeName), a)
+	cxt.AddType(maybeName, Maybe_a)
+	just := expr.Const[nameable.Testable]{Name: nameable.MakeTestable("Just")}
+	x := expr.Var(nameable.MakeTestable("x"))
+	data := bridge.MakeData(just, bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, a))
+	cxt.AddConstructorFor(maybeName, data)
+	return cxt
+}
+
+func TestInfer(t *testing.T) {
+	xName := nameable.MakeTestable("x")
+	yName := nameable.MakeTestable("y")
+	fName := nameable.MakeTestable("f")
+	idName := nameable.MakeTestable("id")
+
+	x := expr.Var(xName)                                                          // x (variable)
+	y := expr.Var(yName)                                                          // y (variable)
+	f := expr.Const[nameable.Testable]{Name: fName}                               // f (constant)
+	id := expr.Const[nameable.Testable]{Name: idName}                             // id (constant)
+	zero := expr.Const[nameable.Testable]{Name: "0"}                              // 0 (constant)
+	just := expr.Const[nameable.Testable]{Name: "Just"}                           // Just (constant)
+	idFunc := expr.Bind[nameable.Testable](x).In(x)                               // (\x -> x)
+	constFunc := expr.Bind[nameable.Testable](x, y).In(x)                         // (\x y -> x)
+	fx := expr.Bind[nameable.Testable](x).In(expr.Apply[nameable.Testable](f, x)) // (\x -> f x)
+	zeroJudgment := bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](zero, types.Var(nameable.MakeTestable("b")))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expect      string
+	}{
+		{
+			`\x -> x`,
+			idFunc,
+			"forall $0 . ($0 -> $0)",
+		},
+		{
+			`\x y -> x`,
+			constFunc,
+			"forall $0 $1 . ($0 -> ($1 -> $0))",
+		},
+		{
+			`(\x -> x) 0`,
+			expr.Apply[nameable.Testable](idFunc, zero),
+			"Int",
+		},
+		{
+			`let id = (\x -> x) in id 0`,
+			expr.Let[nameable.Testable](id, idFunc, expr.Apply[nameable.Testable](id, zero)),
+			"Int",
+		},
+		{
+			`let id = (\x -> x) in id id`,
+			expr.Let[nameable.Testable](id, idFunc, expr.Apply[nameable.Testable](id, id)),
+			"forall $3 . ($3 -> $3)",
+		},
+		{
+			`rec f = (\x -> f x) in f 0`,
+			expr.Rec[nameable.Testable](expr.Declare(fName).Instantiate(fx))(expr.Apply[nameable.Testable](f, zero)),
+			"forall $6 . $6",
+		},
+		{
+			`[0, 0]`,
+			expr.List[nameable.Testable]{zero, zero},
+			"[Int]",
+		},
+		{
+			`Just 0`,
+			expr.Apply[nameable.Testable](just, zero),
+			"(Maybe Int)",
+		},
+		{
+			`(Just (0: b))`,
+			bridge.MakeData(just, zeroJudgment),
+			"(Maybe Int)",
+		},
+		{
+			`select Just 0 when Just y -> y`,
+			expr.Select[nameable.Testable](
+				expr.Apply[nameable.Testable](just, zero),
+				expr.Bind(y).InCase(expr.Apply[nameable.Testable](just, y), y),
+			),
+			"Int",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeDriverTestContext()
+		actual, reports := cxt.Infer(test.input)
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("errors", test.description).FailMessage(nil, reports, i))
+		}
+
+		if actual.String() != test.expect {
+			t.Fatal(testutil.Testing("equality", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestInferFail(t *testing.T) {
+	x := expr.Var(nameable.MakeTestable("x"))
+	y := expr.Const[nameable.Testable]{Name: "y"}
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expect      Status
+	}{
+		{
+			`0 0`,
+			expr.Apply[nameable.Testable](zero, zero),
+			ConstantMismatch,
+		},
+		{
+			`\x -> y`,
+			expr.Bind[nameable.Testable](x).In(y),
+			NameNotInContext,
+		},
+		{
+			`[0, Just]`,
+			expr.List[nameable.Testable]{zero, just},
+			ConstantMismatch,
+		},
+		{
+			`\x -> x x`,
+			expr.Bind[nameable.Testable](x).In(expr.Apply[nameable.Testable](x, x)),
+			OccursCheckFailed,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeDriverTestContext()
+		sigma, reports := cxt.Infer(test.input)
+		if len(reports) != 1 {
+			t.Fatal(testutil.Testing("report count", test.description).FailMessage(1, len(reports), i))
+		}
+
+		if !reports[0].Status.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, reports[0].Status, i))
+		}
+
+		// type of a failed inference is `forall a . a`
+		if bound, ok := sigma.GetBound().(types.Variable[nameable.Testable]); !ok || len(sigma.GetBinders()) != 1 || !sigma.GetBinders()[0].Equals(bound) {
+			t.Fatal(testutil.Testing("type", test.description).FailMessage("forall a . a", sigma, i))
+		}
+
+		// all assumptions are discharged, even on failure
+		if _, found := cxt.Get(expr.Const[nameable.Testable]{Name: "x"}); found {
+			t.Fatal(testutil.Testing("discharge", test.description).FailMessage(false, found, i))
+		}
+	}
+}
+
+// creates the program
+//
+//	let p0 = (\x -> x) in
+//	let p1 = (\x -> Just (p0 x)) in
+//	...
+//	let p`n` = (\x -> Just (p`n-1` x)) in
+//	p`n` 0
+//
+// whose type nests `Maybe` `n` times
+func makeLargeProgram(n int) expr.Expression[nameable.Testable] {
+	x := expr.Var(nameable.MakeTestable("x"))
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	p := func(i int) expr.Const[nameable.Testable] {
+		return expr.Const[nameable.Testable]{Name: nameable.MakeTestable("p" + strconv.Itoa(i))}
+	}
+
+	var program expr.Expression[nameable.Testable] = expr.Apply[nameable.Testable](p(n), zero)
+	for i := n; i > 0; i-- {
+		body := expr.Apply[nameable.Testable](just, expr.Apply[nameable.Testable](p(i-1), x))
+		program = expr.Let[nameable.Testable](p(i), expr.Bind[nameable.Testable](x).In(body), program)
+	}
+	return expr.Let[nameable.Testable](p(0), expr.Bind[nameable.Testable](x).In(x), program)
+}
+
+func TestInferInterned(t *testing.T) {
+	x := expr.Var(nameable.MakeTestable("x"))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+	}{
+		{`let p0 = (\x -> x) in .. p20 0`, makeLargeProgram(20)},
+		{`\x -> x x`, expr.Bind[nameable.Testable](x).In(expr.Apply[nameable.Testable](x, x))},
+	}
+
+	for i, test := range tests {
+		expect, expectReports := makeDriverTestContext().Infer(test.input)
+		cxt := makeDriverTestContext()
+		cxt.UseInterner()
+		actual, reports := cxt.Infer(test.input)
+		if len(reports) != len(expectReports) {
+			t.Fatal(testutil.Testing("report count", test.description).FailMessage(len(expectReports), len(reports), i))
+		}
+
+		if actual.String() != expect.String() {
+			t.Fatal(testutil.Testing("equality", test.description).FailMessage(expect, actual, i))
+		}
+	}
+}
+
+func benchmarkInferLargeProgram(b *testing.B, intern bool) {
+	program := makeLargeProgram(100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cxt := makeDriverTestContext()
+		if intern {
+			cxt.UseInterner()
+		}
+		if _, reports := cxt.Infer(program); len(reports) != 0 {
+			b.Fatal(reports)
+		}
+	}
+}
+
+func BenchmarkInferLargeProgram(b *testing.B) {
+	benchmarkInferLargeProgram(b, false)
+}
+
+func BenchmarkInferLargeProgramInterned(b *testing.B) {
+	benchmarkInferLargeProgram(b, true)
+}
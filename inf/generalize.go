@@ -0,0 +1,140 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: generalization of types w/ respect to a context
+//
+// Notes: uses levels (see types.(*Context).EnterLevel) so that, most of the
+// time, the context does not need to be searched for free variables
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// returns let-nesting depth of `v`--this is the depth `v` was created at unless
+// `v` has since been unified w/ a type containing a variable from a
+// shallower depth
+func (cxt *Context[N]) levelOf(v types.Variable[N]) uint32 {
+	if level, found := cxt.varLevels.Get(v.GetReferred()); found {
+		return level
+	}
+	return v.GetLevel()
+}
+
+// sets level of `v` to `level` iff `level` is shallower than level of `v`
+func (cxt *Context[N]) lowerLevel(v types.Variable[N], level uint32) {
+	if cxt.levelOf(v) <= level {
+		return
+	}
+
+	name := v.GetReferred()
+	if old, found := cxt.varLevels.Get(name); found {
+		cxt.onRollback(func() { cxt.varLevels.Add(name, old) })
+	} else {
+		cxt.onRollback(func() { cxt.varLevels.Remove(name) })
+	}
+	cxt.varLevels.Add(name, level)
+}
+
+// called when `v` = `t` is declared: the variables in `t` can now be reached
+// from wherever `v` can, so none of them may be deeper than `v`
+func (cxt *Context[N]) adjustLevels(v types.Variable[N], t types.Monotyped[N]) {
+	level := cxt.levelOf(v)
+	if u, ok := t.(types.Variable[N]); ok {
+		// either variable may end up representing the other
+		if uLevel := cxt.levelOf(u); uLevel < level {
+			level = uLevel
+		}
+		cxt.lowerLevel(v, level)
+		cxt.lowerLevel(u, level)
+		return
+	}
+
+	for _, u := range cxt.monoFreeVariables(cxt.GetSub(t)) {
+		cxt.lowerLevel(u, level)
+	}
+}
+
+// returns free variables of `m`, found through the interner when monotypes are
+// interned. It is NOT safe to modify the slice returned
+func (cxt *Context[N]) monoFreeVariables(m types.Monotyped[N]) []types.Variable[N] {
+	if cxt.interner == nil {
+		return m.GetFreeVariables()
+	}
+	return cxt.interner.FreeVariables(cxt.interner.Intern(m))
+}
+
+// returns free variables of `ty` after applying substitutions
+func (cxt *Context[N]) freeVariables(ty types.Type[N]) []types.Variable[N] {
+	var binders []types.Variable[N]
+	if q, ok := ty.(types.Qualified[N]); ok {
+		ty = q.GetPolytype()
+	}
+	if sigma, ok := ty.(types.Polytype[N]); ok {
+		binders, ty = sigma.GetBinders(), sigma.GetBound()
+	}
+
+	var frees []types.Variable[N]
+	if m, ok := ty.(types.Monotyped[N]); ok {
+		frees = cxt.monoFreeVariables(cxt.GetSub(m))
+	} else if d, ok := ty.(types.DependentTyped[N]); ok {
+		frees = d.GetFreeVariables()
+	}
+
+	return fun.Filter(func(v types.Variable[N]) bool {
+		for _, binder := range binders {
+			if binder.Equals(v) {
+				return false
+			}
+		}
+		return true
+	}, frees)
+}
+
+// returns names of type variables free in the types of the symbols in context
+func (cxt *Context[N]) freeInContext() map[string]bool {
+	frees := make(map[string]bool)
+	cxt.forEachSymbol(func(sym Symbol[N]) bool {
+		ty, _ := sym.Get().TypeAndExpr()
+		for _, v := range cxt.freeVariables(ty) {
+			frees[v.GetName()] = true
+		}
+		return true
+	})
+	return frees
+}
+
+// generalizes a type w/ respect to the context: binds the free variables of
+// monotype that are not free in the context
+//
+//	Gen𝚪(t) = forall a1 .. aN . t    where {a1, .., aN} = free(t) - free(𝚪)
+//
+// Variables deeper than the current level were created (and can only be
+// reached) while inferring a type that is now being generalized, so they are
+// bound w/o searching the context
+func (cxt *Context[N]) Generalize(ty types.Type[N]) types.Polytype[N] {
+	t, ok := ty.(types.DependentTyped[N])
+	if !ok {
+		return ty.(types.Polytype[N])
+	}
+
+	if m, ok := t.(types.Monotyped[N]); ok {
+		t = cxt.GetSub(m)
+	}
+
+	level := cxt.TypeContext.GetLevel()
+	var inContext map[string]bool // only computed if needed
+	vs := fun.Filter(func(v types.Variable[N]) bool {
+		if cxt.levelOf(v) > level {
+			return true
+		}
+		if inContext == nil {
+			inContext = cxt.freeInContext()
+		}
+		return !inContext[v.GetName()]
+	}, uniqueVariables(t.GetFreeVariables()))
+
+	return types.Forall(vs...).Bind(DependentGeneralization(t))
+}
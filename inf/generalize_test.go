@@ -0,0 +1,94 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestGeneralizeLet(t *testing.T) {
+	x := expr.Var(nameable.MakeTestable("x"))
+	y := expr.Const[nameable.Testable]{Name: "y"}
+	z := expr.Const[nameable.Testable]{Name: "z"}
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	y0 := expr.Apply[nameable.Testable](y, zero)
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expect      string
+	}{
+		{
+			`\x -> let y = x in y`,
+			expr.Bind(x).In(expr.Let[nameable.Testable](y, x, y)),
+			"forall $0 . ($0 -> $0)",
+		},
+		{
+			`\x -> let y = x in y 0`,
+			expr.Bind(x).In(expr.Let[nameable.Testable](y, x, y0)),
+			"forall $2 . ((Int -> $2) -> $2)",
+		},
+		{
+			`\x -> let y = x in let z = y 0 in x`,
+			expr.Bind(x).In(expr.Let[nameable.Testable](y, x, expr.Let[nameable.Testable](z, y0, x))),
+			"forall $2 . ((Int -> $2) -> (Int -> $2))",
+		},
+		{
+			`\x -> rec y = x in y 0`,
+			expr.Bind(x).In(expr.Rec[nameable.Testable](expr.Declare(y.Name).Instantiate(x))(y0)),
+			"forall $3 . ((Int -> $3) -> $3)",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeDriverTestContext()
+		actual, reports := cxt.Infer(test.input)
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("errors", test.description).FailMessage(nil, reports, i))
+		}
+		if actual.String() != test.expect {
+			t.Fatal(testutil.Testing("equality", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestGeneralize(t *testing.T) {
+	x := expr.Const[nameable.Testable]{Name: "x"}
+	y := nameable.MakeTestable("y")
+
+	// variables free in context are not generalized, even w/o levels
+	cxt := NewTestableContext()
+	a, b := cxt.TypeContext.NewVar(), cxt.TypeContext.NewVar()
+	cxt.Shadow(x, a)
+	aToB := cxt.TypeContext.Function(a, b)
+	cxt.Let(y, bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, types.Type[nameable.Testable](aToB)))
+	judgment, _ := cxt.Get(expr.Const[nameable.Testable]{Name: y})
+	if ty, _ := judgment.TypeAndExpr(); ty.String() != "forall $1 . ($0 -> $1)" {
+		t.Fatal(testutil.Testing("context").FailMessage("forall $1 . ($0 -> $1)", ty, 0))
+	}
+
+	// deeper variables reachable from context are not generalized
+	cxt = NewTestableContext()
+	b = cxt.TypeContext.NewVar()
+	cxt.Shadow(x, b)
+	cxt.TypeContext.EnterLevel()
+	c := cxt.TypeContext.NewVar()
+	Maybe_c := types.Apply[nameable.Testable](types.MakeConst(nameable.MakeTestable("Maybe")), c)
+	cxt.Unify(b, Maybe_c)
+	cxt.TypeContext.LeaveLevel()
+	if sigma := cxt.Generalize(c); sigma.String() != "$1" {
+		t.Fatal(testutil.Testing("lowered level").FailMessage("$1", sigma, 1))
+	}
+
+	// deeper variables unreachable from context are generalized
+	cxt.TypeContext.EnterLevel()
+	d := cxt.TypeContext.NewVar()
+	cxt.TypeContext.LeaveLevel()
+	if sigma := cxt.Generalize(d); sigma.String() != "forall $2 . $2" {
+		t.Fatal(testutil.Testing("level").FailMessage("forall $2 . $2", sigma, 2))
+	}
+}
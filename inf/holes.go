@@ -0,0 +1,93 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: typed holes--inferring the type of expressions that have not been
+// written yet and finding the names in scope that could be written instead
+//
+// Notes: a hole is only reported once inference of the expression (or
+// definition) it is in is done, so the type it is reported to have is as
+// substituted as it will ever be. The names in scope are recorded when the
+// hole is reached, since names bound by functions and patterns are removed
+// from the context once their bodies are inferred
+// =============================================================================
+package inf
+
+import (
+	"sort"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// hole that has not been reported yet
+type hole[N nameable.Nameable] struct {
+	// `?x: t`
+	judgment TypeJudgment[N]
+	// names in scope at the hole, sorted by name
+	scope []bridge.JudgmentAsExpression[N, expr.Const[N]]
+}
+
+// [Hole] rule:
+//
+//	t = newvar
+//	----------- [Hole]
+//	𝚪 ⊢ ?x: t
+//
+// the hole and the names in 𝚪 are recorded so they can be reported later; see
+// reportHoles
+func (cxt *Context[N]) Hole(h expr.Hole[N]) Conclusion[N, expr.Hole[N], types.Monotyped[N]] {
+	var t types.Monotyped[N] = cxt.TypeContext.NewVar()
+
+	var scope []bridge.JudgmentAsExpression[N, expr.Const[N]]
+	cxt.forEachSymbol(func(sym Symbol[N]) bool {
+		scope = append(scope, sym.Get())
+		return true
+	})
+	sort.Slice(scope, func(i, j int) bool {
+		_, a := scope[i].TypeAndExpr()
+		_, b := scope[j].TypeAndExpr()
+		return a.Name.GetName() < b.Name.GetName()
+	})
+
+	old := cxt.holes
+	cxt.holes = append(cxt.holes, hole[N]{bridge.Judgment(expr.Expression[N](h), types.Type[N](t)), scope})
+	cxt.onRollback(func() { cxt.holes = old })
+
+	c := Conclude[N](h, t)
+	c.core = CoreHole[N]{h, t}
+	return c
+}
+
+// returns true iff `x` can be used where a value of type `t` is expected, i.e.,
+// iff some instance of the type of `x` unifies w/ `t`. The context is left as
+// it was
+func (cxt *Context[N]) fits(x bridge.JudgmentAsExpression[N, expr.Const[N]], t types.Monotyped[N]) bool {
+	snap := cxt.Snapshot()
+	defer cxt.Rollback(snap)
+
+	c := cxt.varBody(x)
+	return cxt.Unify(c.judgment.GetType(), t).IsOk()
+}
+
+// reports each hole recorded by the [Hole] rule as a warning. The first term
+// involved in the report is the hole judged to have its (substituted) type; it
+// is followed by the names that were in scope at the hole and fit that type
+func (cxt *Context[N]) reportHoles() {
+	old := cxt.holes
+	for _, h := range old {
+		e, ty := h.judgment.GetExpressionAndType()
+		t := cxt.GetSub(ty.(types.Monotyped[N]))
+		terms := []TypeJudgment[N]{bridge.Judgment(e, types.Type[N](t))}
+		for _, x := range h.scope {
+			if cxt.fits(x, t) {
+				sigma, name := x.TypeAndExpr()
+				terms = append(terms, bridge.Judgment(expr.Expression[N](name), sigma))
+			}
+		}
+		cxt.appendWarning(makeReport("Hole", TypedHole, terms...))
+	}
+	cxt.holes = nil
+	cxt.onRollback(func() { cxt.holes = old })
+}
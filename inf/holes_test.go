@@ -0,0 +1,83 @@
+package inf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestHoles(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	h := expr.MakeHole(name("h"))
+	x := expr.Var(name("x"))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		expectType  string
+		expectFits  string
+	}{
+		{
+			`[0, ?h]`,
+			expr.List[nameable.Testable]{c("0"), h},
+			"Int",
+			"0",
+		},
+		{
+			`λx . [0, x, ?h]`,
+			expr.Bind(x).In(expr.List[nameable.Testable]{c("0"), x, h}),
+			"Int",
+			"0 x",
+		},
+		{
+			`[pair 0 true, ?h]`,
+			expr.List[nameable.Testable]{expr.Apply[nameable.Testable](c("pair"), c("0"), c("true")), h},
+			"(Pair Int Bool)",
+			"",
+		},
+		{
+			`[?h, true, 0]`, // holes are reported even when inference fails
+			expr.List[nameable.Testable]{h, c("true"), c("0")},
+			"Bool",
+			"true",
+		},
+		{
+			`[?h, true]`,
+			expr.List[nameable.Testable]{h, c("true")},
+			"Bool",
+			"true",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeRankTestContext()
+		cxt.Infer(test.input)
+
+		warnings := cxt.GetWarnings()
+		if len(warnings) != 1 || !warnings[0].Status.Is(TypedHole) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(TypedHole, warnings, i))
+		}
+
+		terms := warnings[0].TermsInvolved
+		e, ty := terms[0].GetExpressionAndType()
+		if actual := e.String(); actual != "?h" {
+			t.Fatal(testutil.Testing("hole", test.description).FailMessage("?h", actual, i))
+		}
+		if actual := ty.String(); actual != test.expectType {
+			t.Fatal(testutil.Testing("type", test.description).FailMessage(test.expectType, actual, i))
+		}
+
+		fits := make([]string, len(terms)-1)
+		for j, term := range terms[1:] {
+			e, _ := term.GetExpressionAndType()
+			fits[j] = e.String()
+		}
+		if actual := strings.Join(fits, " "); actual != test.expectFits {
+			t.Fatal(testutil.Testing("fits", test.description).FailMessage(test.expectFits, actual, i))
+		}
+	}
+}
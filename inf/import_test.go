@@ -0,0 +1,280 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// exports module
+//
+//	module M (id, Maybe(Just)) where
+//	  Maybe a = Just a
+func makeTestModule(t *testing.T) *ExportableContext[nameable.Testable] {
+	moduleName := nameable.MakeTestable("M")
+	idName := nameable.MakeTestable("id")
+	maybeName := nameable.MakeTestable("Maybe")
+	justName := nameable.MakeTestable("Just")
+
+	cxt, export := Export(
+		moduleName,
+		nameable.MakeTestable,
+		[]nameable.Testable{idName},
+		[]nameable.Testable{maybeName},
+		[][]nameable.Testable{{justName}},
+	)
+
+	a := types.Var(nameable.MakeTestable("a"))
+	Maybe_a := types.Apply[nameable.Testable](types.MakeConst(maybeName), a)
+	cxt.AddType(maybeName, Maybe_a)
+	x := expr.Var(nameable.MakeTestable("x"))
+	just := expr.Const[nameable.Testable]{Name: justName}
+	cxt.AddConstructorFor(maybeName, bridge.MakeData(just, bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, a)))
+
+	ecxt := export()
+	if ecxt == nil {
+		t.Fatal(testutil.Testing("export").FailMessage(nil, cxt.GetReports(), 0))
+	}
+	return ecxt
+}
+
+func TestImport(t *testing.T) {
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	moduleName := nameable.MakeTestable("M")
+	asName := nameable.MakeTestable("A")
+
+	tests := []struct {
+		description   string
+		qualification QualificationType
+		just, id      nameable.Testable
+		// type of `just 0`
+		expect string
+	}{
+		{"import M", NotQualified, "Just", "id", "(Maybe Int)"},
+		{"import M as A", NameQualified, "A.Just", "A.id", "(A.Maybe Int)"},
+		{"import qualified M", FullyQualified, "M.Just", "M.id", "(M.Maybe Int)"},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		cxt.Add(zero, Int)
+		if stat := cxt.RegisterModule(makeTestModule(t)); stat.NotOk() {
+			t.Fatal(testutil.Testing("register", test.description).FailMessage(Ok, stat, i))
+		}
+
+		if stat := cxt.Import(test.qualification, moduleName, asName); stat.NotOk() {
+			t.Fatal(testutil.Testing("import", test.description).FailMessage(Ok, stat, i))
+		}
+
+		just := expr.Const[nameable.Testable]{Name: test.just}
+		actual, reports := cxt.Infer(expr.Apply[nameable.Testable](just, zero))
+		if len(reports) != 0 {
+			t.Fatal(testutil.Testing("constructor errors", test.description).FailMessage(nil, reports, i))
+		}
+		if actual.String() != test.expect {
+			t.Fatal(testutil.Testing("constructor", test.description).FailMessage(test.expect, actual, i))
+		}
+
+		if _, found := cxt.Get(expr.Const[nameable.Testable]{Name: test.id}); !found {
+			t.Fatal(testutil.Testing("name", test.description).FailMessage(true, found, i))
+		}
+	}
+}
+
+func TestImportFail(t *testing.T) {
+	moduleName := nameable.MakeTestable("M")
+	asName := nameable.MakeTestable("A")
+
+	// undefined module
+	cxt := NewTestableContext()
+	if stat := cxt.Import(NotQualified, moduleName, asName); !stat.Is(UndefinedModule) {
+		t.Fatal(testutil.Testing("undefined module").FailMessage(UndefinedModule, stat, 0))
+	}
+
+	// module redefinition
+	cxt = NewTestableContext()
+	cxt.RegisterModule(makeTestModule(t))
+	if stat := cxt.RegisterModule(makeTestModule(t)); !stat.Is(ModuleRedef) {
+		t.Fatal(testutil.Testing("module redefinition").FailMessage(ModuleRedef, stat, 1))
+	}
+
+	// importing the same names twice
+	cxt = NewTestableContext()
+	cxt.RegisterModule(makeTestModule(t))
+	cxt.Import(NotQualified, moduleName, asName)
+	if stat := cxt.Import(NotQualified, moduleName, asName); !stat.Is(TypeRedef) {
+		t.Fatal(testutil.Testing("type redefinition").FailMessage(TypeRedef, stat, 2))
+	}
+
+	// importing the same module under different qualifications is fine
+	cxt = NewTestableContext()
+	cxt.RegisterModule(makeTestModule(t))
+	cxt.Import(NotQualified, moduleName, asName)
+	if stat := cxt.Import(FullyQualified, moduleName, asName); stat.NotOk() {
+		t.Fatal(testutil.Testing("qualified import").FailMessage(Ok, stat, 3))
+	}
+
+	// a clashing name is reported by name, and nothing is imported
+	cxt = NewTestableContext()
+	cxt.RegisterModule(makeTestModule(t))
+	cxt.Add(expr.MakeConst(nameable.MakeTestable("id")), types.MakeConst(nameable.MakeTestable("Int")))
+	if stat := cxt.Import(NotQualified, moduleName, asName); !stat.Is(IllegalShadow) {
+		t.Fatal(testutil.Testing("name clash").FailMessage(IllegalShadow, stat, 4))
+	}
+	reports := cxt.GetReports()
+	if expect := "id is already defined"; len(reports) != 1 || reports[0].Message() != expect {
+		t.Fatal(testutil.Testing("name clash report").FailMessage(expect, reports, 4))
+	}
+	if _, found := cxt.consTable.Get(nameable.MakeTestable("Maybe")); found {
+		t.Fatal(testutil.Testing("name clash rollback").FailMessage(false, found, 4))
+	}
+	if _, found := cxt.GetKind(nameable.MakeTestable("Maybe")); found {
+		t.Fatal(testutil.Testing("name clash kind rollback").FailMessage(false, found, 4))
+	}
+}
+
+func TestImportKinds(t *testing.T) {
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	moduleName := nameable.MakeTestable("M")
+	asName := nameable.MakeTestable("A")
+
+	tests := []struct {
+		description   string
+		qualification QualificationType
+		just, maybe   nameable.Testable
+		// message for `Just 0: Maybe Int Int`
+		expect string
+	}{
+		{"import M", NotQualified, "Just", "Maybe", "Maybe does not have kind * -> * -> * (it has kind * -> *)"},
+		{"import M as A", NameQualified, "A.Just", "A.Maybe", "A.Maybe does not have kind * -> * -> * (it has kind * -> *)"},
+		{"import qualified M", FullyQualified, "M.Just", "M.Maybe", "M.Maybe does not have kind * -> * -> * (it has kind * -> *)"},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		cxt.Add(zero, Int)
+		cxt.RegisterModule(makeTestModule(t))
+		if stat := cxt.Import(test.qualification, moduleName, asName); stat.NotOk() {
+			t.Fatal(testutil.Testing("import", test.description).FailMessage(Ok, stat, i))
+		}
+
+		if kind, found := cxt.GetKind(test.maybe); !found || kind.String() != "* -> *" {
+			t.Fatal(testutil.Testing("kind", test.description).FailMessage("* -> *", kind, i))
+		}
+
+		just := expr.Const[nameable.Testable]{Name: test.just}
+		ty := types.Apply[nameable.Testable](types.MakeConst(test.maybe), Int, Int)
+		if stat := cxt.Check(expr.Apply[nameable.Testable](just, zero), ty).Status; !stat.Is(KindMismatch) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(KindMismatch, stat, i))
+		}
+		reports := cxt.GetReports()
+		if len(reports) != 1 || reports[0].Message() != test.expect {
+			t.Fatal(testutil.Testing("message", test.description).FailMessage(test.expect, reports, i))
+		}
+	}
+}
+
+// exports module
+//
+//	module C where
+//	  class Eq a where (==): a -> a -> Bool
+//	  instance Eq Int
+func makeClassModule(t *testing.T) *ExportableContext[nameable.Testable] {
+	name := nameable.MakeTestable
+	cxt, export := Export(name("C"), name, nil, nil, nil)
+
+	a := types.Var(name("a"))
+	compare := cxt.TypeContext.Function(a, cxt.TypeContext.Function(a, types.MakeConst(name("Bool"))))
+	cxt.AddClass(
+		name("Eq"),
+		[]types.Variable[nameable.Testable]{a},
+		nil,
+		[]expr.Const[nameable.Testable]{expr.MakeConst(name("=="))},
+		[]types.DependentTyped[nameable.Testable]{compare},
+	)
+	cxt.AddInstance(name("$EqInt"), nil, nil, types.Pred[nameable.Testable](name("Eq"), types.MakeConst(name("Int"))))
+
+	ecxt := export()
+	if ecxt == nil {
+		t.Fatal(testutil.Testing("export").FailMessage(nil, cxt.GetReports(), 0))
+	}
+	return ecxt
+}
+
+// imports `module` and elaborates `== 0 0` w/ the imported class and instance
+func testClassImport(t *testing.T, module *ExportableContext[nameable.Testable], description string) {
+	name := nameable.MakeTestable
+	zero := expr.MakeConst(name("0"))
+
+	cxt := NewTestableContext()
+	cxt.Add(zero, types.MakeConst(name("Int")))
+	cxt.RegisterModule(module)
+	if stat := cxt.Import(NotQualified, name("C"), name("C")); stat.NotOk() {
+		t.Fatal(testutil.Testing("import", description).FailMessage(Ok, stat, 0))
+	}
+
+	e, ty, reports := cxt.Elaborate(expr.Apply[nameable.Testable](expr.MakeConst(name("==")), zero, zero))
+	if len(reports) != 0 {
+		t.Fatal(testutil.Testing("reports", description).FailMessage(nil, reports, 0))
+	}
+	if expect := "Bool"; ty.String() != expect {
+		t.Fatal(testutil.Testing("type", description).FailMessage(expect, ty, 0))
+	}
+	if expect := "(((== $EqInt) 0) 0)"; e.String() != expect {
+		t.Fatal(testutil.Testing("elaboration", description).FailMessage(expect, e, 0))
+	}
+}
+
+func TestImportClasses(t *testing.T) {
+	testClassImport(t, makeClassModule(t), "import C")
+
+	name := nameable.MakeTestable
+	Int := types.MakeConst(name("Int"))
+	a := types.Var(name("a"))
+	params := []types.Variable[nameable.Testable]{a}
+
+	// importing a module twice imports its classes and instances once
+	cxt := NewTestableContext()
+	cxt.RegisterModule(makeClassModule(t))
+	cxt.Import(NotQualified, name("C"), name("C"))
+	if stat := cxt.Import(FullyQualified, name("C"), name("C")); stat.NotOk() {
+		t.Fatal(testutil.Testing("import twice").FailMessage(Ok, stat, 0))
+	}
+
+	tests := []struct {
+		description string
+		prepare     func(cxt *Context[nameable.Testable])
+		stat        Status
+	}{
+		{
+			"different class w/ the same name",
+			func(cxt *Context[nameable.Testable]) {
+				cxt.AddClass(name("Eq"), append(params, types.Var(name("b"))), nil, nil, nil)
+			},
+			ClassRedef,
+		},
+		{
+			"overlapping instance",
+			func(cxt *Context[nameable.Testable]) {
+				cxt.AddClass(name("Eq"), params, nil, nil, nil)
+				cxt.AddInstance(name("$EqInt'"), nil, nil, types.Pred[nameable.Testable](name("Eq"), Int))
+			},
+			InstanceRedef,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		test.prepare(cxt)
+		cxt.RegisterModule(makeClassModule(t))
+		if stat := cxt.Import(NotQualified, name("C"), name("C")); !stat.Is(test.stat) {
+			t.Fatal(testutil.Testing("import", test.description).FailMessage(test.stat, stat, i))
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: solving linear constraints between natural-number indexes of
+// dependent types, e.g., `n + 1 = m` and `m + k = 5`
+//
+// Notes: an index is understood as a linear combination of kind variables
+// when it is built from decimal numerals, `Succ`, `+`, and `*` by a numeral.
+// A constraint is solved when it determines a variable--either its value or an
+// expression of the other variables w/ no negative parts. Constraints that do
+// not determine any variable yet are deferred and rechecked each time indexes
+// are unified. Deferred constraints that are still unsolved when an expression
+// or top-level definition is generalized are reported
+// =============================================================================
+package inf
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// names of the types of natural-number indexes
+var naturalTypes = map[string]bool{"Nat": true, "Uint": true}
+
+// deferred constraint `a = b` between two indexes of type `t`
+type indexConstraint[N nameable.Nameable] struct {
+	a, b expr.Referable[N]
+	t    types.Monotyped[N]
+}
+
+// c0 + c1*v1 + .. + cN*vN
+type linear[N nameable.Nameable] struct {
+	constant int64
+	coeffs   map[string]int64
+	vars     map[string]expr.Variable[N]
+}
+
+func constantLinear[N nameable.Nameable](constant int64) linear[N] {
+	return linear[N]{constant, map[string]int64{}, map[string]expr.Variable[N]{}}
+}
+
+func variableLinear[N nameable.Nameable](v expr.Variable[N]) linear[N] {
+	l := constantLinear[N](0)
+	l.coeffs[v.GetReferred().GetName()] = 1
+	l.vars[v.GetReferred().GetName()] = v
+	return l
+}
+
+// returns `l` + `k`*`m`
+func (l linear[N]) plus(k int64, m linear[N]) linear[N] {
+	out := constantLinear[N](l.constant + k*m.constant)
+	for name, c := range l.coeffs {
+		out.coeffs[name], out.vars[name] = c, l.vars[name]
+	}
+	for name, c := range m.coeffs {
+		out.coeffs[name] += k * c
+		out.vars[name] = m.vars[name]
+		if out.coeffs[name] == 0 {
+			delete(out.coeffs, name)
+			delete(out.vars, name)
+		}
+	}
+	return out
+}
+
+// names of the variables of `l` in order
+func (l linear[N]) names() []string {
+	names := make([]string, 0, len(l.coeffs))
+	for name := range l.coeffs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// returns true iff `t` is the type of natural-number indexes
+func isNatural[N nameable.Nameable](t types.Type[N]) bool {
+	c, ok := t.(types.Constant[N])
+	return ok && naturalTypes[c.GetReferred().GetName()]
+}
+
+// returns operator and operands of sum or product `e`
+func arithmeticHead[N nameable.Nameable](e expr.Referable[N]) (op string, args []expr.Expression[N], ok bool) {
+	computed, ok := e.(expr.Computed[N])
+	if !ok {
+		return "", nil, false
+	}
+	head, args := spine(computed.GetExpression())
+	c, ok := head.(expr.Const[N])
+	if !ok || len(args) != 2 || (c.Name.GetName() != "+" && c.Name.GetName() != "*") {
+		return "", nil, false
+	}
+	return c.Name.GetName(), args, true
+}
+
+// returns true iff `a` and `b` are linear and either is a sum or product once
+// normalized
+func (cxt *Context[N]) isArithmetic(a, b expr.Referable[N]) bool {
+	_, _, aIsArithmetic := arithmeticHead(cxt.normalizeIndex(a))
+	_, _, bIsArithmetic := arithmeticHead(cxt.normalizeIndex(b))
+	if !aIsArithmetic && !bIsArithmetic {
+		return false
+	}
+	_, okA := cxt.linearize(a)
+	_, okB := cxt.linearize(b)
+	return okA && okB
+}
+
+// applies substitutions to index `e`, including to the expression of a
+// computed index
+func (cxt *Context[N]) substituteKinds(e expr.Referable[N]) expr.Referable[N] {
+	if computed, ok := e.(expr.Computed[N]); ok {
+		return expr.Compute(cxt.substituteIndex(computed.GetExpression()))
+	}
+	return cxt.GetKindSub(e)
+}
+
+// returns index `e` as a linear combination of kind variables; second return
+// value is false iff `e` is not one
+func (cxt *Context[N]) linearize(e expr.Referable[N]) (linear[N], bool) {
+	e = cxt.normalizeIndex(e)
+	switch x := e.(type) {
+	case expr.Variable[N]:
+		return variableLinear(x), true
+	case expr.Const[N]:
+		n, err := strconv.ParseInt(x.Name.GetName(), 10, 64)
+		return constantLinear[N](n), err == nil && n >= 0
+	case bridge.Data[N]:
+		if x.GetTag().Name.GetName() != "Succ" || len(x.Members) != 1 {
+			return linear[N]{}, false
+		}
+		m, _ := x.Members[0].GetExpressionAndType()
+		l, ok := cxt.linearize(asIndex(m))
+		return l.plus(1, constantLinear[N](1)), ok
+	}
+
+	op, args, ok := arithmeticHead(e)
+	if !ok {
+		return linear[N]{}, false
+	}
+	left, okLeft := cxt.linearize(asIndex(args[0]))
+	right, okRight := cxt.linearize(asIndex(args[1]))
+	if !okLeft || !okRight {
+		return linear[N]{}, false
+	}
+	if op == "+" {
+		return left.plus(1, right), true
+	}
+	// linear only when one factor is a numeral
+	if len(left.coeffs) == 0 {
+		return constantLinear[N](0).plus(left.constant, right), true
+	} else if len(right.coeffs) == 0 {
+		return constantLinear[N](0).plus(right.constant, left), true
+	}
+	return linear[N]{}, false
+}
+
+// returns index w/ the value of `l`, which has no negative parts
+func (cxt *Context[N]) delinearize(l linear[N]) expr.Referable[N] {
+	numeral := func(n int64) expr.Expression[N] {
+		return cxt.ExprContext.Const(strconv.FormatInt(n, 10))
+	}
+	plus, times := cxt.ExprContext.Const("+"), cxt.ExprContext.Const("*")
+
+	var terms []expr.Expression[N]
+	for _, name := range l.names() {
+		var term expr.Expression[N] = l.vars[name]
+		if c := l.coeffs[name]; c != 1 {
+			term = expr.Apply[N](times, numeral(c), term)
+		}
+		terms = append(terms, term)
+	}
+	if l.constant != 0 || len(terms) == 0 {
+		terms = append(terms, numeral(l.constant))
+	}
+	sum := fun.FoldLeft(terms[0], terms[1:], func(acc, term expr.Expression[N]) expr.Expression[N] {
+		return expr.Apply[N](plus, acc, term)
+	})
+	return asIndex(sum)
+}
+
+// tries to solve `a = b`. Returns Ok and true when the constraint was solved,
+// Ok and false when it must be deferred, and KindConstantMismatch when it has
+// no solution in the natural numbers or either index is not linear
+func (cxt *Context[N]) solveIndexes(a, b expr.Referable[N]) (stat Status, solved bool) {
+	la, okA := cxt.linearize(a)
+	lb, okB := cxt.linearize(b)
+	if !okA || !okB {
+		return KindConstantMismatch, false
+	}
+	// c0 + c1*v1 + .. + cN*vN = 0
+	eq := la.plus(-1, lb)
+	names := eq.names()
+
+	if len(names) == 0 {
+		if eq.constant != 0 {
+			return KindConstantMismatch, false
+		}
+		return Ok, true
+	}
+
+	// no integer solution
+	divisor := int64(0)
+	signs := 0
+	for _, name := range names {
+		divisor = gcd(divisor, eq.coeffs[name])
+		if eq.coeffs[name] > 0 {
+			signs++
+		}
+	}
+	if eq.constant%divisor != 0 {
+		return KindConstantMismatch, false
+	}
+
+	// all coefficients have the same sign
+	if signs == 0 || signs == len(names) {
+		positive := signs != 0
+		if (positive && eq.constant > 0) || (!positive && eq.constant < 0) {
+			return KindConstantMismatch, false // no solution w/o negative parts
+		}
+		if eq.constant == 0 {
+			// every variable must be 0
+			for _, name := range names {
+				if stat = fixSkip(cxt.kindUnion(eq.vars[name], cxt.delinearize(constantLinear[N](0)))); stat.NotOk() {
+					return stat, false
+				}
+			}
+			return Ok, true
+		}
+	}
+
+	if len(names) == 1 {
+		c := eq.coeffs[names[0]]
+		return fixSkip(cxt.kindUnion(eq.vars[names[0]], cxt.delinearize(constantLinear[N](-eq.constant/c)))), true
+	}
+
+	// v = rest for some `v` w/ coefficient 1 or -1 where `rest` has no negative
+	// parts
+	for _, name := range names {
+		c := eq.coeffs[name]
+		if c != 1 && c != -1 {
+			continue
+		}
+		rest := constantLinear[N](0).plus(-c, eq.plus(-c, variableLinear(eq.vars[name])))
+		ok := rest.constant >= 0
+		for _, r := range rest.coeffs {
+			ok = ok && r > 0
+		}
+		if ok {
+			return fixSkip(cxt.kindUnion(eq.vars[name], cxt.delinearize(rest))), true
+		}
+	}
+	return Ok, false
+}
+
+// solves constraint `a = b` between indexes of type `t` or defers it
+func (cxt *Context[N]) unifyArithmetic(a, b expr.Referable[N], t types.Monotyped[N]) Status {
+	stat, solved := cxt.solveIndexes(a, b)
+	if stat.IsOk() && !solved {
+		old := cxt.indexConstraints
+		cxt.indexConstraints = append(cxt.indexConstraints, indexConstraint[N]{a, b, t})
+		cxt.onRollback(func() { cxt.indexConstraints = old })
+	}
+	return stat
+}
+
+// rechecks deferred index constraints until none of them can be solved
+func (cxt *Context[N]) recheckIndexConstraints() Status {
+	for progress := true; progress; {
+		progress = false
+		old := cxt.indexConstraints
+		var remaining []indexConstraint[N]
+		for _, c := range old {
+			stat, solved := cxt.solveIndexes(c.a, c.b)
+			if stat.NotOk() {
+				return stat
+			}
+			if solved {
+				progress = true
+			} else {
+				remaining = append(remaining, c)
+			}
+		}
+		cxt.indexConstraints = remaining
+		cxt.onRollback(func() { cxt.indexConstraints = old })
+	}
+	return Ok
+}
+
+// reports deferred index constraints that cannot be solved, removing them
+func (cxt *Context[N]) reportIndexConstraints() Status {
+	stat := cxt.recheckIndexConstraints()
+	if stat.NotOk() {
+		return stat
+	}
+	old := cxt.indexConstraints
+	for _, c := range old {
+		t := types.Type[N](cxt.GetSub(c.t))
+		a := bridge.Judgment(expr.Expression[N](cxt.substituteKinds(c.a)), t)
+		b := bridge.Judgment(expr.Expression[N](cxt.substituteKinds(c.b)), t)
+		cxt.appendReport(makeReport[N]("Gen", UnsolvedIndexConstraint, a, b))
+		stat = UnsolvedIndexConstraint
+	}
+	cxt.indexConstraints = nil
+	cxt.onRollback(func() { cxt.indexConstraints = old })
+	return stat
+}
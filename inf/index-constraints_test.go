@@ -0,0 +1,136 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestIndexConstraints(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	n, m, k := expr.Var(name("n")), expr.Var(name("m")), expr.Var(name("k"))
+	a := types.Var(name("a"))
+	Nat, Vec := types.MakeConst(name("Nat")), types.MakeConst(name("Vec"))
+
+	op := func(s string) func(e0, e1 expr.Expression[nameable.Testable]) expr.Referable[nameable.Testable] {
+		return func(e0, e1 expr.Expression[nameable.Testable]) expr.Referable[nameable.Testable] {
+			return expr.Compute[nameable.Testable](expr.Apply[nameable.Testable](c(s), e0, e1))
+		}
+	}
+	plus, times := op("+"), op("*")
+	// (Vec a; e)
+	vec := func(e expr.Referable[nameable.Testable]) types.Monotyped[nameable.Testable] {
+		index := types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]](types.Judgment(e, types.Type[nameable.Testable](Nat)))
+		return types.Index(types.Apply[nameable.Testable](Vec, a), index)
+	}
+	type equation [2]types.Monotyped[nameable.Testable]
+
+	tests := []struct {
+		description string
+		equations   []equation
+		expect      Status
+		expectSubs  map[string]string
+	}{
+		{
+			`n + 1 = 5`,
+			[]equation{{vec(plus(n, c("1"))), vec(c("5"))}},
+			Ok,
+			map[string]string{"n": "4"},
+		},
+		{
+			`n + 1 = m`,
+			[]equation{{vec(plus(n, c("1"))), vec(m)}},
+			Ok,
+			map[string]string{"m": "((+ n) 1)"},
+		},
+		{
+			`2 * n = n + 3`,
+			[]equation{{vec(times(c("2"), n)), vec(plus(n, c("3")))}},
+			Ok,
+			map[string]string{"n": "3"},
+		},
+		{
+			`m + k = 0`,
+			[]equation{{vec(plus(m, k)), vec(c("0"))}},
+			Ok,
+			map[string]string{"m": "0", "k": "0"},
+		},
+		{
+			`m + k = 5 and k = 2`,
+			[]equation{{vec(plus(m, k)), vec(c("5"))}, {vec(k), vec(c("2"))}},
+			Ok,
+			map[string]string{"m": "3", "k": "2"},
+		},
+		{
+			`n + 1 = 0`,
+			[]equation{{vec(plus(n, c("1"))), vec(c("0"))}},
+			KindConstantMismatch,
+			nil,
+		},
+		{
+			`2 * n = 5`,
+			[]equation{{vec(times(c("2"), n)), vec(c("5"))}},
+			KindConstantMismatch,
+			nil,
+		},
+		{
+			`m + k = 5 and k = 6`,
+			[]equation{{vec(plus(m, k)), vec(c("5"))}, {vec(k), vec(c("6"))}},
+			KindConstantMismatch,
+			nil,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		stat := Ok
+		for _, eq := range test.equations {
+			if stat = cxt.Unify(eq[0], eq[1]); stat.NotOk() {
+				break
+			}
+		}
+		if !stat.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, stat, i))
+		}
+		for v, expect := range test.expectSubs {
+			if actual := cxt.GetKindSub(expr.Var(name(v))).String(); actual != expect {
+				t.Fatal(testutil.Testing("substitution", test.description).FailMessage(expect, actual, i))
+			}
+		}
+		if stat.IsOk() {
+			if stat = cxt.reportIndexConstraints(); stat.NotOk() {
+				t.Fatal(testutil.Testing("leftovers", test.description).FailMessage(Ok, cxt.GetReports(), i))
+			}
+		}
+	}
+}
+
+func TestUnsolvedIndexConstraints(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	m, k := expr.Var(name("m")), expr.Var(name("k"))
+	Nat, Vec := types.MakeConst(name("Nat")), types.MakeConst(name("Vec"))
+	vec := func(e expr.Referable[nameable.Testable]) types.Monotyped[nameable.Testable] {
+		index := types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]](types.Judgment(e, types.Type[nameable.Testable](Nat)))
+		return types.Index(types.Apply[nameable.Testable](Vec, Nat), index)
+	}
+	mPlusK := expr.Compute[nameable.Testable](expr.Apply[nameable.Testable](c("+"), m, k))
+
+	// m + k = 5 cannot be solved until m or k is known
+	cxt := NewTestableContext()
+	if stat := cxt.Unify(vec(mPlusK), vec(c("5"))); stat.NotOk() {
+		t.Fatal(testutil.Testing("deferred").FailMessage(Ok, stat, 0))
+	}
+	if stat := cxt.reportIndexConstraints(); !stat.Is(UnsolvedIndexConstraint) {
+		t.Fatal(testutil.Testing("status").FailMessage(UnsolvedIndexConstraint, stat, 0))
+	}
+	reports := cxt.GetReports()
+	expect := "cannot solve index constraint ((+ m) k) = 5"
+	if len(reports) != 1 || reports[0].Message() != expect {
+		t.Fatal(testutil.Testing("report").FailMessage(expect, reports, 0))
+	}
+}
@@ -35,7 +35,7 @@ const (
 	TypeNotDefined
 	// tried to export undefined type
 	UndefinedType
-	// tried to export undefined constructor
+	// tried to use or export undefined constructor
 	UndefinedConstructor
 	// tried to export undefined function
 	UndefinedFunction
@@ -43,6 +43,51 @@ const (
 	AmbiguousFunction
 	// illegal name shadowing 
 	IllegalShadow
+	// expression has no inference rule
+	UnsupportedExpression
+	// module already exists in module registry
+	ModuleRedef
+	// tried to import a module that is not in the module registry
+	UndefinedModule
+	// constructor pattern did not have same number of arguments as constructor
+	// has members
+	ConstructorArityMismatch
+	// expression cannot be used as a pattern
+	IllegalPattern
+	// class is not defined
+	UndefinedClass
+	// class already exists in class table
+	ClassRedef
+	// instance proves some predicate another instance already proves
+	InstanceRedef
+	// no instance proves a class constraint
+	NoInstance
+	// class constraint on a type variable that can never be determined
+	AmbiguousConstraint
+	// type was applied to the wrong number or kinds of params or was used where
+	// a type of a different kind was expected
+	KindMismatch
+	// kind of type would have to contain itself
+	InfiniteKind
+	// expression is not polymorphic enough to have a type it was checked
+	// against: a variable bound by the type was unified w/ a variable that is
+	// free in the context
+	SkolemEscape
+	// record type does not have a field another record type it was unified w/
+	// has
+	MissingLabel
+	// record type would have two fields w/ the same label
+	DuplicateLabel
+	// (warning) some values are not matched by any case of a select expression
+	NonExhaustiveMatch
+	// (warning) case can never be reached because earlier cases match all the
+	// values it matches
+	RedundantCase
+	// (warning) expression has a hole; reports the type the hole must have
+	// and the names in scope that have that type
+	TypedHole
+	// constraint between natural-number indexes was never solved
+	UnsolvedIndexConstraint
 	// unification of variables succeeded, so signals that there is nothing left 
 	// to unify
 	skipUnify
@@ -68,6 +113,60 @@ func (stat Status) String() string {
 		return "NameNotInContext"
 	case RecArgsLengthMismatch:
 		return "RecArgsLengthMismatch"
+	case TypeRedef:
+		return "TypeRedef"
+	case ConstructorRedef:
+		return "ConstructorRedef"
+	case TypeNotDefined:
+		return "TypeNotDefined"
+	case UndefinedType:
+		return "UndefinedType"
+	case UndefinedConstructor:
+		return "UndefinedConstructor"
+	case UndefinedFunction:
+		return "UndefinedFunction"
+	case AmbiguousFunction:
+		return "AmbiguousFunction"
+	case IllegalShadow:
+		return "IllegalShadow"
+	case UnsupportedExpression:
+		return "UnsupportedExpression"
+	case ModuleRedef:
+		return "ModuleRedef"
+	case UndefinedModule:
+		return "UndefinedModule"
+	case ConstructorArityMismatch:
+		return "ConstructorArityMismatch"
+	case IllegalPattern:
+		return "IllegalPattern"
+	case UndefinedClass:
+		return "UndefinedClass"
+	case ClassRedef:
+		return "ClassRedef"
+	case InstanceRedef:
+		return "InstanceRedef"
+	case NoInstance:
+		return "NoInstance"
+	case AmbiguousConstraint:
+		return "AmbiguousConstraint"
+	case KindMismatch:
+		return "KindMismatch"
+	case InfiniteKind:
+		return "InfiniteKind"
+	case SkolemEscape:
+		return "SkolemEscape"
+	case MissingLabel:
+		return "MissingLabel"
+	case DuplicateLabel:
+		return "DuplicateLabel"
+	case NonExhaustiveMatch:
+		return "NonExhaustiveMatch"
+	case RedundantCase:
+		return "RedundantCase"
+	case TypedHole:
+		return "TypedHole"
+	case UnsolvedIndexConstraint:
+		return "UnsolvedIndexConstraint"
 	case skipUnify:
 		return "skipUnify"
 	default:
@@ -75,6 +174,16 @@ func (stat Status) String() string {
 	}
 }
 
+// returns true iff `stat` is returned when two types or kinds cannot be unified
+func (stat Status) isUnificationFailure() bool {
+	return (stat >= ConstantMismatch && stat <= OccursCheckFailed) || stat == MissingLabel || stat == DuplicateLabel
+}
+
+// returns true iff `stat` reports an ill-kinded type
+func (stat Status) isKindFailure() bool {
+	return stat == KindMismatch || stat == InfiniteKind
+}
+
 func (stat Status) IsOk() bool {
 	return stat == Ok
 }
@@ -44,7 +44,7 @@ func (cxt *Context[T]) occurs(v types.Variable[T], t types.Monotyped[T]) (vOccur
 		return false
 	}
 
-	for _, u := range t.GetFreeVariables() {
+	for _, u := range cxt.monoFreeVariables(t) {
 		if v.Equals(u) {
 			return true
 		}
@@ -76,6 +76,7 @@ func (cxt *Context[T]) kindOccurs(v expr.Variable[T], e expr.Referable[T]) (vOcc
 // if v ∈ t, then union returns `OccursCheckFailed`; else, skipUnify is returned
 func (cxt *Context[T]) union(v types.Variable[T], t types.Monotyped[T]) Status {
 	if cxt.occurs(v, t) {
+		cxt.mismatch = []types.Type[T]{v, cxt.GetSub(t)}
 		return OccursCheckFailed
 	}
 
@@ -83,7 +84,13 @@ func (cxt *Context[T]) union(v types.Variable[T], t types.Monotyped[T]) Status {
 	// 	t = cxt.reindex(d)
 	// }
 
+	if stat := cxt.checkLacks(v, t); stat.NotOk() {
+		return stat
+	}
+
+	cxt.adjustLevels(v, t)
 	cxt.typeSubs.Add(v, t)
+	cxt.recordSubstitution(v, t)
 	return skipUnify
 }
 
@@ -134,22 +141,36 @@ func DependentGeneralization[T nameable.Nameable](ty types.DependentTyped[T]) ty
 	return ty
 }
 
+// removes repeated variables from `vs`, keeping the first occurrence of each
+func uniqueVariables[T nameable.Nameable](vs []types.Variable[T]) []types.Variable[T] {
+	seen := make(map[string]bool, len(vs))
+	return fun.Filter(func(v types.Variable[T]) bool {
+		name := v.GetReferred().GetName()
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+		return true
+	}, vs)
+}
+
 // generalizes a type: binds all free variables w/in monotype
 func (cxt *Context[T]) Gen(ty types.Type[T]) types.Polytype[T] {
 	if t, ok := ty.(types.DependentTyped[T]); ok {
 		// DependentGeneralization(`(t a0 .. aK; x0 .. xN)`) = `mapval (x0: X0) .. (xN: XN) . (t a0 .. aK)`
 		dep := DependentGeneralization(t)
 		// (t a0 .. aK; x0 .. xN) -> a0 .. aK
-		vs := t.GetFreeVariables()
+		vs := uniqueVariables(t.GetFreeVariables())
 		// forall a0 .. aK . mapval (x0: X0) .. (xN: XN) . (t a0 .. aK)
 		return types.Forall(vs...).Bind(dep)
 	}
 	return ty.(types.Polytype[T])
 }
 
+// returns true iff `ty` is a variable that is not rigid
 func IsVariable[T nameable.Nameable](ty types.Monotyped[T]) bool {
-	_, ok := ty.(types.Variable[T])
-	return ok
+	v, ok := ty.(types.Variable[T])
+	return ok && !v.IsRigid()
 }
 
 func checkStatus[T nameable.Nameable](c0, c1 string, ms0, ms1 []types.Monotyped[T], isA, isB types.Indexes[T]) Status {
@@ -194,7 +215,8 @@ func (cxt *Context[T]) kindUnion(v expr.Variable[T], e expr.Referable[T]) Status
 		return OccursCheckFailed
 	}
 
-	cxt.exprSubs.Add(v.GetReferred(), e)
+	cxt.exprSubs.Add(v, e)
+	cxt.recordSubstitution(v, e)
 	return skipUnify
 }
 
@@ -217,6 +239,15 @@ func (cxt otherwiseDo[T]) otherwiseUnifyKind(a, b expr.Referable[T]) Status {
 		return fixSkip(cxt.stat)
 	}
 
+	// indexes that cannot be reduced further are compared structurally
+	sa, aIsStuck := a.(expr.Computed[T])
+	sb, bIsStuck := b.(expr.Computed[T])
+	if aIsStuck && bIsStuck {
+		return cxt.unifyStuck(sa, sb)
+	} else if aIsStuck || bIsStuck {
+		return KindConstantMismatch
+	}
+
 	// get constants and mems
 	ca, memsOfA := SplitKind(a)
 	cb, memsOfB := SplitKind(b)
@@ -228,7 +259,7 @@ func (cxt otherwiseDo[T]) otherwiseUnifyKind(a, b expr.Referable[T]) Status {
 	for i := 0; stat.IsOk() && i < len(memsOfA); i++ {
 		ma, _ := memsOfA[i].GetExpressionAndType()
 		mb, _ := memsOfB[i].GetExpressionAndType()
-		stat = cxt.UnifyKind(ma.(expr.Referable[T]), mb.(expr.Referable[T]))
+		stat = cxt.UnifyKind(asIndex(ma), asIndex(mb))
 	}
 
 	return stat
@@ -247,9 +278,11 @@ func (cxt *Context[T]) substituteKind(ea, eb expr.Referable[T]) otherwiseDo[T] {
 	return otherwiseDo[T]{stat, cxt}
 }
 
+// unifies two indexes a, b once each is normalized (see normalizeIndex), so
+// indexes unify iff they are equal up to beta and delta reduction
 func (cxt *Context[T]) UnifyKind(a, b expr.Referable[T]) Status {
-	ea := cxt.FindKind(a)
-	eb := cxt.FindKind(b)
+	ea := cxt.normalizeIndex(a)
+	eb := cxt.normalizeIndex(b)
 
 	return cxt.substituteKind(ea, eb).otherwiseUnifyKind(ea, eb)
 }
@@ -269,11 +302,18 @@ func (cxt *Context[T]) UnifyIndex(indexOfA, indexOfB types.ExpressionJudgment[T,
 	// unify types
 	stat := cxt.Unify(ma, mb)
 
-	// if type union Ok, then unify kinds
-	if stat.IsOk() {
+	// if type union Ok, then unify kinds; sums and products of natural numbers
+	// are solved for instead (see solveIndexes)
+	if stat.IsOk() && isNatural(types.Type[T](cxt.GetSub(ma))) && cxt.isArithmetic(ra, rb) {
+		stat = cxt.unifyArithmetic(ra, rb, ma)
+	} else if stat.IsOk() {
 		stat = cxt.UnifyKind(ra, rb)
 	}
 
+	// new substitutions may solve deferred constraints
+	if stat.IsOk() {
+		stat = cxt.recheckIndexConstraints()
+	}
 	return stat
 }
 
@@ -292,12 +332,35 @@ func (cxt otherwiseDo[T]) otherwiseUnify(a, b types.Monotyped[T]) Status {
 		return fixSkip(cxt.stat)
 	}
 
+	// nested polytypes only unify w/ each other
+	na, aIsNested := a.(types.Nested[T])
+	nb, bIsNested := b.(types.Nested[T])
+	if aIsNested && bIsNested {
+		return cxt.unifyNested(na, nb)
+	} else if aIsNested || bIsNested {
+		cxt.mismatch = []types.Type[T]{cxt.GetSub(a), cxt.GetSub(b)}
+		return ConstantMismatch
+	}
+
+	// record types only unify w/ each other
+	ra, aIsRecord := a.(types.Record[T])
+	rb, bIsRecord := b.(types.Record[T])
+	if aIsRecord && bIsRecord {
+		return cxt.unifyRecords(ra, rb)
+	} else if aIsRecord || bIsRecord {
+		cxt.mismatch = []types.Type[T]{cxt.GetSub(a), cxt.GetSub(b)}
+		return ConstantMismatch
+	}
+
 	// get constants, params, and indexes
 	ca, paramsOfA, indexesOfA := Split(a)
 	cb, paramsOfB, indexesOfB := Split(b)
 
 	// check if alright to use in loop
 	stat := checkStatus(ca, cb, paramsOfA, paramsOfB, indexesOfA, indexesOfB)
+	if stat.NotOk() {
+		cxt.mismatch = []types.Type[T]{cxt.GetSub(a), cxt.GetSub(b)}
+	}
 
 	// it. through all params while stat is ok, unifying params
 	for i := 0; stat.IsOk() && i < len(paramsOfA); i++ {
@@ -323,9 +386,10 @@ type otherwiseDo[T nameable.Nameable] struct {
 func (cxt *Context[T]) substitute(ta, tb types.Monotyped[T]) otherwiseDo[T] {
 	stat := Ok
 
-	if v, ok := ta.(types.Variable[T]); ok {
+	// skolems are not substituted for (see types.(*Context).Skolem)
+	if v, ok := ta.(types.Variable[T]); ok && !v.IsRigid() {
 		stat = cxt.union(v, tb)
-	} else if v, ok := tb.(types.Variable[T]); ok {
+	} else if v, ok := tb.(types.Variable[T]); ok && !v.IsRigid() {
 		stat = cxt.union(v, ta)
 	}
 
@@ -334,6 +398,7 @@ func (cxt *Context[T]) substitute(ta, tb types.Monotyped[T]) otherwiseDo[T] {
 
 // unifies two monotypes a, b
 func (cxt *Context[T]) Unify(a, b types.Monotyped[T]) Status {
+	cxt.mismatch = nil
 	ta := cxt.Find(a)
 	tb := cxt.Find(b)
 
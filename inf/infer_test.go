@@ -821,7 +821,7 @@ func TestFind(t *testing.T) {
 		}
 
 		// now add substitution
-		cxt.typeSubs.Add(test.targ.GetReferred(), test.sub)
+		cxt.typeSubs.Add(test.targ, test.sub)
 
 		// test find after substitution added
 		afterSub := cxt.Find(test.targ)
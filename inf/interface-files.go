@@ -0,0 +1,861 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: module interface files--reading and writing exported contexts (see
+// ExportableContext)
+//
+// Notes: interface files are JSON documents holding a format version, the
+// content hash of the module they describe, and the module itself: its name,
+// its exported symbols and their types, its exported types and their kinds and
+// constructors, and its classes and instances. Symbols, types, classes, and
+// instances are kept in order of their names, so
+// writing the same module twice gives the same file and the same hash. Names
+// are written w/ GetName and read back w/ the name maker given to
+// ReadInterface. Type variables lose their let-nesting depths, which only
+// matter while a type is being inferred
+// =============================================================================
+package inf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// version of the interface file format written by WriteInterface; files of
+// other versions cannot be read
+const InterfaceVersion int = 1
+
+type interfaceFile struct {
+	Version int           `json:"version"`
+	Hash    string        `json:"hash"`
+	Module  encodedModule `json:"module"`
+}
+
+type encodedModule struct {
+	Name    string              `json:"name"`
+	Symbols []encodedSymbol     `json:"symbols"`
+	Types   []encodedDefinition `json:"types"`
+	// omitted when empty, so modules w/o classes keep their hashes
+	Classes   []encodedClass    `json:"classes,omitempty"`
+	Instances []encodedInstance `json:"instances,omitempty"`
+}
+
+// class, its superclasses, and the names of its methods; the types of the
+// methods are written w/ the other symbols
+type encodedClass struct {
+	Name    string             `json:"name"`
+	Params  []encodedType      `json:"params"`
+	Supers  []encodedPredicate `json:"supers,omitempty"`
+	Methods []string           `json:"methods"`
+}
+
+// instance `forall binders . context => head` whose dictionary is named `Name`
+type encodedInstance struct {
+	Name    string             `json:"name"`
+	Binders []encodedType      `json:"binders,omitempty"`
+	Context []encodedPredicate `json:"context,omitempty"`
+	Head    encodedPredicate   `json:"head"`
+}
+
+// exported name and its type
+type encodedSymbol struct {
+	Name string      `json:"name"`
+	Type encodedType `json:"type"`
+}
+
+// exported type, its kind if known, and its exported constructors
+type encodedDefinition struct {
+	Name         string               `json:"name"`
+	Type         encodedType          `json:"type"`
+	Kind         *encodedKind         `json:"kind,omitempty"`
+	Constructors []encodedConstructor `json:"constructors"`
+}
+
+// forms of encoded kinds
+const (
+	starForm  string = "*"
+	arrowForm string = "->"
+	indexForm string = "index"
+)
+
+// encoding of a Kind w/o kind variables; only the fields used by `Form` are set
+type encodedKind struct {
+	Form string `json:"form"`
+	// kinds of the parameter and result of a type constructor
+	From *encodedKind `json:"from,omitempty"`
+	To   *encodedKind `json:"to,omitempty"`
+	// type of a value index
+	Type string `json:"type,omitempty"`
+}
+
+// constructor `λparams . data` and its type
+type encodedConstructor struct {
+	Params []string    `json:"params"`
+	Data   encodedExpr `json:"data"`
+	Type   encodedType `json:"type"`
+}
+
+// kinds of encoded types
+const (
+	variableKind    string = "var"
+	constantKind    string = "con"
+	infixKind       string = "infix"
+	enclosingKind   string = "enclosing"
+	applicationKind string = "app"
+	instanceKind    string = "instance"
+	dependentKind   string = "mapval"
+	polytypeKind    string = "forall"
+	nestedKind      string = "nested"
+	qualifiedKind   string = "qualified"
+	recordKind      string = "record"
+)
+
+// encoding of any types.Type; only the fields used by `Kind` are set
+type encodedType struct {
+	Kind string `json:"kind"`
+	// name of a variable or constant
+	Name string `json:"name,omitempty"`
+	// context that binds a variable; see types.(Variable).BoundIn
+	BoundIn int32 `json:"boundIn,omitempty"`
+	// where the name of an enclosing constant is split
+	SplitAt uint `json:"splitAt,omitempty"`
+	// head of an application or family of a dependent type instance
+	Head *encodedType `json:"head,omitempty"`
+	// parameters of an application or of a predicate
+	Params []encodedType `json:"params,omitempty"`
+	// indexes of a dependent type instance
+	Indexes []encodedJudgment `json:"indexes,omitempty"`
+	// variables bound by a dependent type
+	Mapval []encodedJudgment `json:"mapval,omitempty"`
+	// variables bound by a polytype
+	Binders []encodedType `json:"binders,omitempty"`
+	// type bound by a polytype or dependent type, or nested polytype
+	Bound *encodedType `json:"bound,omitempty"`
+	// predicates of a qualified type
+	Context []encodedPredicate `json:"context,omitempty"`
+	// fields and row variable of a record type
+	Fields []encodedField `json:"fields,omitempty"`
+	Row    *encodedType   `json:"row,omitempty"`
+}
+
+type encodedPredicate struct {
+	Class  string        `json:"class"`
+	Params []encodedType `json:"params"`
+}
+
+type encodedField struct {
+	Label string      `json:"label"`
+	Type  encodedType `json:"type"`
+}
+
+// kinds of encoded expressions
+const (
+	variableExpr string = "var"
+	constantExpr string = "con"
+	dataExpr     string = "data"
+)
+
+// encoding of the expressions that can appear in types and constructors:
+// variables, constants, and data
+type encodedExpr struct {
+	Kind    string            `json:"kind"`
+	Name    string            `json:"name"`
+	Members []encodedJudgment `json:"members,omitempty"`
+}
+
+type encodedJudgment struct {
+	Expr encodedExpr `json:"expr"`
+	Type encodedType `json:"type"`
+}
+
+func encodeTypes[N nameable.Nameable, T types.Type[N]](ts []T) ([]encodedType, error) {
+	out := make([]encodedType, len(ts))
+	for i, t := range ts {
+		enc, err := encodeType[N](t)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = enc
+	}
+	return out, nil
+}
+
+func encodeVariable[N nameable.Nameable](v types.Variable[N]) encodedType {
+	return encodedType{Kind: variableKind, Name: v.GetName(), BoundIn: v.GetBoundContext()}
+}
+
+func encodePredicates[N nameable.Nameable](preds []types.Predicate[N]) ([]encodedPredicate, error) {
+	var out []encodedPredicate
+	for _, p := range preds {
+		params, err := encodeTypes[N](p.GetParams())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, encodedPredicate{p.GetClass().GetName(), params})
+	}
+	return out, nil
+}
+
+// encodes `ty`; returns an error iff `ty`, or an expression w/in it, cannot
+// be encoded
+func encodeType[N nameable.Nameable](ty types.Type[N]) (out encodedType, err error) {
+	switch t := ty.(type) {
+	case types.Variable[N]:
+		return encodeVariable(t), nil
+	case types.Constant[N]:
+		return encodedType{Kind: constantKind, Name: t.GetName()}, nil
+	case types.InfixConst[N]:
+		return encodedType{Kind: infixKind, Name: t.GetName()}, nil
+	case types.EnclosingConst[N]:
+		return encodedType{Kind: enclosingKind, Name: t.GetName(), SplitAt: t.GetSplit()}, nil
+	case types.Application[N]:
+		head, err := encodeType[N](t.GetHead())
+		if err != nil {
+			return out, err
+		}
+		_, params := t.Split()
+		out = encodedType{Kind: applicationKind, Head: &head}
+		out.Params, err = encodeTypes[N](params)
+		return out, err
+	case types.DependentTypeInstance[N]:
+		family, err := encodeType[N](t.Application)
+		if err != nil {
+			return out, err
+		}
+		out = encodedType{Kind: instanceKind, Head: &family}
+		out.Indexes, err = encodeIndexes(t.Indexes)
+		return out, err
+	case types.DependentType[N]:
+		bound, err := encodeType[N](t.Function)
+		if err != nil {
+			return out, err
+		}
+		out = encodedType{Kind: dependentKind, Bound: &bound}
+		out.Mapval, err = encodeMapval(types.GetDependees[N](t))
+		return out, err
+	case types.Polytype[N]:
+		bound, err := encodeType[N](t.GetBound())
+		if err != nil {
+			return out, err
+		}
+		binders := fun.FMap(t.GetBinders(), encodeVariable[N])
+		return encodedType{Kind: polytypeKind, Binders: binders, Bound: &bound}, nil
+	case types.Nested[N]:
+		sigma, err := encodeType[N](t.GetPolytype())
+		if err != nil {
+			return out, err
+		}
+		return encodedType{Kind: nestedKind, Bound: &sigma}, nil
+	case types.Qualified[N]:
+		sigma, err := encodeType[N](t.GetPolytype())
+		if err != nil {
+			return out, err
+		}
+		out = encodedType{Kind: qualifiedKind, Bound: &sigma}
+		out.Context, err = encodePredicates(t.GetContext())
+		return out, err
+	case types.Record[N]:
+		out = encodedType{Kind: recordKind}
+		for _, field := range t.GetFields() {
+			enc, err := encodeType[N](field.Type)
+			if err != nil {
+				return out, err
+			}
+			out.Fields = append(out.Fields, encodedField{field.Label.GetName(), enc})
+		}
+		if row, extensible := t.GetRow(); extensible {
+			enc := encodeVariable(row)
+			out.Row = &enc
+		}
+		return out, nil
+	}
+	return out, fmt.Errorf("cannot encode type %v", ty)
+}
+
+func encodeMapval[N nameable.Nameable](mapval []types.TypeJudgment[N, expr.Variable[N]]) ([]encodedJudgment, error) {
+	out := make([]encodedJudgment, len(mapval))
+	for i, j := range mapval {
+		enc, err := encodeJudgment(j.GetExpressionAndType())
+		if err != nil {
+			return nil, err
+		}
+		out[i] = enc
+	}
+	return out, nil
+}
+
+func encodeIndexes[N nameable.Nameable](indexes types.Indexes[N]) ([]encodedJudgment, error) {
+	out := make([]encodedJudgment, len(indexes))
+	for i, index := range indexes {
+		enc, err := encodeJudgment(index.AsTypeJudgment().GetExpressionAndType())
+		if err != nil {
+			return nil, err
+		}
+		out[i] = enc
+	}
+	return out, nil
+}
+
+func encodeJudgment[N nameable.Nameable](e expr.Expression[N], ty types.Type[N]) (out encodedJudgment, err error) {
+	if out.Expr, err = encodeExpr(e); err != nil {
+		return
+	}
+	out.Type, err = encodeType(ty)
+	return
+}
+
+// encodes `e`; returns an error iff `e` is not a variable, constant, or data
+// w/ encodable members
+func encodeExpr[N nameable.Nameable](e expr.Expression[N]) (out encodedExpr, err error) {
+	switch x := e.(type) {
+	case expr.Variable[N]:
+		return encodedExpr{Kind: variableExpr, Name: x.GetReferred().GetName()}, nil
+	case expr.Const[N]:
+		return encodedExpr{Kind: constantExpr, Name: x.Name.GetName()}, nil
+	case bridge.Data[N]:
+		out = encodedExpr{Kind: dataExpr, Name: x.GetTag().Name.GetName()}
+		for _, member := range x.Members {
+			ty, e := member.TypeAndExpr()
+			enc, err := encodeJudgment(e, ty)
+			if err != nil {
+				return out, err
+			}
+			out.Members = append(out.Members, enc)
+		}
+		return out, nil
+	}
+	return out, fmt.Errorf("cannot encode expression %v", e)
+}
+
+// reads encoded types and expressions back, naming them w/ `makeName`
+type decoder[N nameable.Nameable] struct {
+	makeName func(string) N
+}
+
+func (d decoder[N]) variable(enc encodedType) (types.Variable[N], error) {
+	if enc.Kind != variableKind {
+		return types.Variable[N]{}, fmt.Errorf("expected type variable, found %q", enc.Kind)
+	}
+	return types.Var(d.makeName(enc.Name)).BoundIn(enc.BoundIn), nil
+}
+
+func (d decoder[N]) variables(encs []encodedType) ([]types.Variable[N], error) {
+	out := make([]types.Variable[N], len(encs))
+	for i, enc := range encs {
+		v, err := d.variable(enc)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (d decoder[N]) predicates(encs []encodedPredicate) ([]types.Predicate[N], error) {
+	out := make([]types.Predicate[N], len(encs))
+	for i, p := range encs {
+		params, err := d.monotypes(p.Params)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = types.Pred(d.makeName(p.Class), params...)
+	}
+	return out, nil
+}
+
+func (d decoder[N]) monotypes(encs []encodedType) ([]types.Monotyped[N], error) {
+	out := make([]types.Monotyped[N], len(encs))
+	for i, enc := range encs {
+		t, err := d.monotype(enc)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = t
+	}
+	return out, nil
+}
+
+func (d decoder[N]) monotype(enc encodedType) (types.Monotyped[N], error) {
+	ty, err := d.typ(enc)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := ty.(types.Monotyped[N])
+	if !ok {
+		return nil, fmt.Errorf("expected monotype, found %v", ty)
+	}
+	return m, nil
+}
+
+func (d decoder[N]) polytype(enc encodedType) (types.Polytype[N], error) {
+	ty, err := d.typ(enc)
+	if err != nil {
+		return types.Polytype[N]{}, err
+	}
+	sigma, ok := ty.(types.Polytype[N])
+	if !ok {
+		return sigma, fmt.Errorf("expected polytype, found %v", ty)
+	}
+	return sigma, nil
+}
+
+// returns an error when `enc` is nil, which only happens for malformed files
+func (d decoder[N]) bound(enc *encodedType) (types.Type[N], error) {
+	if enc == nil {
+		return nil, fmt.Errorf("missing bound type")
+	}
+	return d.typ(*enc)
+}
+
+// decodes a type written by encodeType
+func (d decoder[N]) typ(enc encodedType) (types.Type[N], error) {
+	switch enc.Kind {
+	case variableKind:
+		return d.variable(enc)
+	case constantKind:
+		return types.MakeConst(d.makeName(enc.Name)), nil
+	case infixKind:
+		return types.MakeInfixConst(d.makeName(enc.Name)), nil
+	case enclosingKind:
+		return types.MakeEnclosingConst(enc.SplitAt, d.makeName(enc.Name)), nil
+	case applicationKind:
+		head, err := d.bound(enc.Head)
+		if err != nil {
+			return nil, err
+		}
+		params, err := d.monotypes(enc.Params)
+		if err != nil {
+			return nil, err
+		}
+		c, ok := head.(types.Monotyped[N])
+		if !ok {
+			return nil, fmt.Errorf("expected monotype, found %v", head)
+		}
+		return types.Apply(c, params...), nil
+	case instanceKind:
+		family, err := d.bound(enc.Head)
+		if err != nil {
+			return nil, err
+		}
+		app, ok := family.(types.Application[N])
+		if !ok {
+			return nil, fmt.Errorf("expected type family, found %v", family)
+		}
+		indexes := make([]types.ExpressionJudgment[N, expr.Referable[N]], len(enc.Indexes))
+		for i, index := range enc.Indexes {
+			e, ty, err := d.judgment(index)
+			if err != nil {
+				return nil, err
+			}
+			indexes[i] = types.Judgment(e.(expr.Referable[N]), ty)
+		}
+		return types.Index(app, indexes...), nil
+	case dependentKind:
+		bound, err := d.bound(enc.Bound)
+		if err != nil {
+			return nil, err
+		}
+		function, ok := bound.(types.TypeFunction[N])
+		if !ok {
+			return nil, fmt.Errorf("expected type function, found %v", bound)
+		}
+		mapval := make([]types.TypeJudgment[N, expr.Variable[N]], len(enc.Mapval))
+		for i, j := range enc.Mapval {
+			e, ty, err := d.judgment(j)
+			if err != nil {
+				return nil, err
+			}
+			v, ok := e.(expr.Variable[N])
+			if !ok {
+				return nil, fmt.Errorf("expected variable, found %v", e)
+			}
+			mapval[i] = types.Judgment(v, ty)
+		}
+		return types.MakeDependentType(mapval, function), nil
+	case polytypeKind:
+		bound, err := d.bound(enc.Bound)
+		if err != nil {
+			return nil, err
+		}
+		binders, err := d.variables(enc.Binders)
+		if err != nil {
+			return nil, err
+		}
+		dependent, ok := bound.(types.DependentTyped[N])
+		if !ok {
+			return nil, fmt.Errorf("expected dependent type, found %v", bound)
+		}
+		return types.Forall(binders...).Bind(dependent), nil
+	case nestedKind:
+		if enc.Bound == nil {
+			return nil, fmt.Errorf("missing bound type")
+		}
+		sigma, err := d.polytype(*enc.Bound)
+		if err != nil {
+			return nil, err
+		}
+		return types.Nest(sigma), nil
+	case qualifiedKind:
+		if enc.Bound == nil {
+			return nil, fmt.Errorf("missing bound type")
+		}
+		sigma, err := d.polytype(*enc.Bound)
+		if err != nil {
+			return nil, err
+		}
+		context, err := d.predicates(enc.Context)
+		if err != nil {
+			return nil, err
+		}
+		return types.Qualify(sigma, context...), nil
+	case recordKind:
+		fields := make([]types.FieldType[N], len(enc.Fields))
+		for i, field := range enc.Fields {
+			t, err := d.monotype(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = types.Field(d.makeName(field.Label), t)
+		}
+		var rest types.Monotyped[N]
+		if enc.Row != nil {
+			row, err := d.variable(*enc.Row)
+			if err != nil {
+				return nil, err
+			}
+			rest = row
+		}
+		return types.RecordOf(fields, rest), nil
+	}
+	return nil, fmt.Errorf("unknown kind of type %q", enc.Kind)
+}
+
+func (d decoder[N]) judgment(enc encodedJudgment) (expr.Expression[N], types.Type[N], error) {
+	e, err := d.expr(enc.Expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	ty, err := d.typ(enc.Type)
+	return e, ty, err
+}
+
+// decodes an expression written by encodeExpr
+func (d decoder[N]) expr(enc encodedExpr) (expr.Expression[N], error) {
+	switch enc.Kind {
+	case variableExpr:
+		return expr.Var(d.makeName(enc.Name)), nil
+	case constantExpr:
+		return expr.MakeConst(d.makeName(enc.Name)), nil
+	case dataExpr:
+		members := make([]bridge.JudgmentAsExpression[N, expr.Expression[N]], len(enc.Members))
+		for i, member := range enc.Members {
+			e, ty, err := d.judgment(member)
+			if err != nil {
+				return nil, err
+			}
+			members[i] = bridge.Judgment(e, ty)
+		}
+		return bridge.MakeData(expr.MakeConst(d.makeName(enc.Name)), members...), nil
+	}
+	return nil, fmt.Errorf("unknown kind of expression %q", enc.Kind)
+}
+
+// encodes the symbols, types, classes, and instances exported by `ecxt`
+func (ecxt *ExportableContext[N]) encode() (out encodedModule, err error) {
+	out = encodedModule{Name: ecxt.name.GetName(), Symbols: []encodedSymbol{}, Types: []encodedDefinition{}}
+	ecxt.syms.ForEach(func(key nameable.Nameable, sym Symbol[N]) bool {
+		ty, _ := sym.Get().TypeAndExpr()
+		var enc encodedType
+		if enc, err = encodeType(ty); err == nil {
+			out.Symbols = append(out.Symbols, encodedSymbol{key.GetName(), enc})
+		}
+		return err == nil
+	})
+	if err != nil {
+		return
+	}
+
+	ecxt.consTable.ForEach(func(key nameable.Nameable, cj consJudge[N]) bool {
+		var def encodedDefinition
+		def, err = encodeDefinition(key.GetName(), cj)
+		if kind, found := ecxt.kinds.Get(key); found && err == nil {
+			def.Kind, err = encodeKind(kind)
+		}
+		out.Types = append(out.Types, def)
+		return err == nil
+	})
+	if err != nil {
+		return
+	}
+
+	if out.Classes, out.Instances, err = ecxt.encodeClasses(); err != nil {
+		return
+	}
+
+	sort.Slice(out.Symbols, func(i, j int) bool { return out.Symbols[i].Name < out.Symbols[j].Name })
+	sort.Slice(out.Types, func(i, j int) bool { return out.Types[i].Name < out.Types[j].Name })
+	sort.Slice(out.Classes, func(i, j int) bool { return out.Classes[i].Name < out.Classes[j].Name })
+	sort.Slice(out.Instances, func(i, j int) bool { return out.Instances[i].Name < out.Instances[j].Name })
+	return out, nil
+}
+
+// encodes the classes and instances exported by `ecxt`, unordered
+func (ecxt *ExportableContext[N]) encodeClasses() (classes []encodedClass, instances []encodedInstance, err error) {
+	ecxt.classes.ForEach(func(_ nameable.Nameable, c class[N]) bool {
+		enc := encodedClass{Name: c.name.GetName(), Params: fun.FMap(c.params, encodeVariable[N])}
+		enc.Methods = fun.FMap(c.methods, func(m expr.Const[N]) string { return m.Name.GetName() })
+		if enc.Supers, err = encodePredicates(c.supers); err == nil {
+			classes = append(classes, enc)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return
+	}
+
+	ecxt.instances.ForEach(func(_ nameable.Nameable, insts []instance[N]) bool {
+		for _, inst := range insts {
+			enc := encodedInstance{Name: inst.name.Name.GetName(), Binders: fun.FMap(inst.binders, encodeVariable[N])}
+			if enc.Context, err = encodePredicates(inst.context); err != nil {
+				return false
+			}
+			var head []encodedPredicate
+			if head, err = encodePredicates([]types.Predicate[N]{inst.head}); err != nil {
+				return false
+			}
+			enc.Head = head[0]
+			instances = append(instances, enc)
+		}
+		return true
+	})
+	return
+}
+
+func encodeKind(k Kind) (*encodedKind, error) {
+	switch kk := k.(type) {
+	case Star:
+		return &encodedKind{Form: starForm}, nil
+	case IndexKind:
+		return &encodedKind{Form: indexForm, Type: kk.Type}, nil
+	case KindArrow:
+		from, err := encodeKind(kk.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := encodeKind(kk.To)
+		if err != nil {
+			return nil, err
+		}
+		return &encodedKind{Form: arrowForm, From: from, To: to}, nil
+	}
+	return nil, fmt.Errorf("cannot encode kind %v", k)
+}
+
+func decodeKind(enc *encodedKind) (Kind, error) {
+	if enc == nil {
+		return nil, fmt.Errorf("missing kind")
+	}
+	switch enc.Form {
+	case starForm:
+		return Star{}, nil
+	case indexForm:
+		return IndexKind{enc.Type}, nil
+	case arrowForm:
+		from, err := decodeKind(enc.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := decodeKind(enc.To)
+		if err != nil {
+			return nil, err
+		}
+		return KindArrow{from, to}, nil
+	}
+	return nil, fmt.Errorf("unknown form of kind %q", enc.Form)
+}
+
+// encodes type `name` and its constructors, leaving out the wildcard
+// constructor every type has
+func encodeDefinition[N nameable.Nameable](name string, cj consJudge[N]) (out encodedDefinition, err error) {
+	out = encodedDefinition{Name: name, Constructors: []encodedConstructor{}}
+	if out.Type, err = encodeType[N](cj.forType); err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(cj.constructors))
+	for key := range cj.constructors {
+		if key != wildcardConstructorName {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		constructor := cj.constructors[key]
+		f := constructor.GetExpression()
+		enc := encodedConstructor{Params: fun.FMap(f.GetBinders(), func(v expr.Variable[N]) string { return v.GetReferred().GetName() })}
+		if enc.Data, err = encodeExpr(f.GetBound()); err != nil {
+			return
+		}
+		if enc.Type, err = encodeType[N](constructor.GetType()); err != nil {
+			return
+		}
+		out.Constructors = append(out.Constructors, enc)
+	}
+	return
+}
+
+// returns hex encoded SHA-256 hash of `module`'s encoding
+func hashModule(module encodedModule) (string, error) {
+	bs, err := json.Marshal(module)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// returns content hash of the module `ecxt` describes. Two exported contexts
+// have the same hash iff they would be written to the same interface file
+func (ecxt *ExportableContext[N]) Hash() (string, error) {
+	module, err := ecxt.encode()
+	if err != nil {
+		return "", err
+	}
+	return hashModule(module)
+}
+
+// writes interface file for the module `ecxt` describes to `w`
+func (ecxt *ExportableContext[N]) WriteInterface(w io.Writer) error {
+	module, err := ecxt.encode()
+	if err != nil {
+		return err
+	}
+	hash, err := hashModule(module)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(interfaceFile{InterfaceVersion, hash, module})
+}
+
+// reads interface file written by WriteInterface from `r`, naming everything
+// in it w/ `makeName`. Returns an error when the file has a different version
+// or its content does not match its hash
+func ReadInterface[N nameable.Nameable](r io.Reader, makeName func(string) N) (*ExportableContext[N], error) {
+	var file interfaceFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+	if file.Version != InterfaceVersion {
+		return nil, fmt.Errorf("interface file has version %d, expected version %d", file.Version, InterfaceVersion)
+	}
+	if hash, err := hashModule(file.Module); err != nil {
+		return nil, err
+	} else if hash != file.Hash {
+		return nil, fmt.Errorf("interface file content does not match its hash")
+	}
+
+	d := decoder[N]{makeName}
+	ecxt := NewExportableContext[N]()
+	ecxt.name = makeName(file.Module.Name)
+
+	for _, sym := range file.Module.Symbols {
+		ty, err := d.typ(sym.Type)
+		if err != nil {
+			return nil, err
+		}
+		name := makeName(sym.Name)
+		symbol := MakeSymbol[N]()
+		symbol.Shadow(expr.MakeConst(name), ty)
+		if stat := ecxt.export(name, symbol); stat.NotOk() {
+			return nil, fmt.Errorf("interface file exports %s more than once", sym.Name)
+		}
+	}
+
+	// makes wildcard constructors
+	cxt := NewContext[N]()
+	cxt.ExprContext = cxt.ExprContext.SetNameMaker(makeName)
+	for _, def := range file.Module.Types {
+		sigma, err := d.polytype(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		cj := cxt.makeConsJudge(sigma)
+		for _, constructor := range def.Constructors {
+			data, err := d.expr(constructor.Data)
+			if err != nil {
+				return nil, err
+			}
+			ty, err := d.polytype(constructor.Type)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := data.(bridge.Data[N]); !ok {
+				return nil, fmt.Errorf("expected data, found %v", data)
+			}
+			params := fun.FMap(constructor.Params, func(s string) expr.Variable[N] { return expr.Var(makeName(s)) })
+			tag := data.(bridge.Data[N]).GetTag().Name.GetName()
+			cj.constructors[tag] = types.TypedJudge[N](expr.Bind(params...).In(data), ty)
+		}
+		ecxt.consTable.Add(makeName(def.Name), cj)
+		if def.Kind != nil {
+			kind, err := decodeKind(def.Kind)
+			if err != nil {
+				return nil, err
+			}
+			ecxt.kinds.Add(makeName(def.Name), kind)
+		}
+	}
+
+	if err := d.classes(ecxt, file.Module); err != nil {
+		return nil, err
+	}
+	return ecxt, nil
+}
+
+// decodes the classes and instances of `module` into `ecxt`
+func (d decoder[N]) classes(ecxt *ExportableContext[N], module encodedModule) error {
+	for _, enc := range module.Classes {
+		params, err := d.variables(enc.Params)
+		if err != nil {
+			return err
+		}
+		supers, err := d.predicates(enc.Supers)
+		if err != nil {
+			return err
+		}
+		methods := fun.FMap(enc.Methods, func(s string) expr.Const[N] { return expr.MakeConst(d.makeName(s)) })
+		name := d.makeName(enc.Name)
+		ecxt.classes.Add(name, class[N]{name, params, supers, methods})
+	}
+
+	for _, enc := range module.Instances {
+		binders, err := d.variables(enc.Binders)
+		if err != nil {
+			return err
+		}
+		context, err := d.predicates(enc.Context)
+		if err != nil {
+			return err
+		}
+		heads, err := d.predicates([]encodedPredicate{enc.Head})
+		if err != nil {
+			return err
+		}
+		inst := instance[N]{expr.MakeConst(d.makeName(enc.Name)), binders, context, heads[0]}
+		class := inst.head.GetClass()
+		insts, _ := ecxt.instances.Get(class)
+		ecxt.instances.Add(class, append(insts, inst))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package inf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestEncodeType(t *testing.T) {
+	name := nameable.MakeTestable
+	con := func(s string) types.Constant[nameable.Testable] { return types.MakeConst(name(s)) }
+	a, r := types.Var(name("a")), types.Var(name("r"))
+	n := expr.Var(name("n"))
+	list := types.MakeEnclosingConst[nameable.Testable](1, name("[]"))
+	arrow := types.MakeInfixConst[nameable.Testable](name("->"))
+	aToA := types.Apply[nameable.Testable](arrow, a, a)
+	array := types.Index(
+		types.Apply[nameable.Testable](list, a),
+		types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]](types.Judgment[nameable.Testable, expr.Referable[nameable.Testable]](n, con("Uint"))),
+	)
+	mapval := []types.TypeJudgment[nameable.Testable, expr.Variable[nameable.Testable]]{types.Judgment[nameable.Testable](n, types.Type[nameable.Testable](con("Uint")))}
+
+	tests := []struct {
+		description string
+		input       types.Type[nameable.Testable]
+	}{
+		{"variable", a},
+		{"non-bindable variable", types.NonBindableVar(name("a"))},
+		{"constant", con("Int")},
+		{"infix constant", arrow},
+		{"enclosing constant", list},
+		{"application", types.Apply[nameable.Testable](list, con("Int"))},
+		{"dependent type instance", array},
+		{"dependent type", types.MakeDependentType[nameable.Testable](mapval, array)},
+		{"polytype", types.Forall(a).Bind(types.MakeDependentType[nameable.Testable](mapval, array))},
+		{"nested polytype", types.Apply[nameable.Testable](arrow, types.Nest(types.Forall(a).Bind(aToA)), con("Int"))},
+		{"qualified type", types.Qualify(types.Forall(a).Bind(aToA), types.Pred[nameable.Testable](name("Eq"), a))},
+		{"closed record", types.Closed(types.Field[nameable.Testable](name("name"), con("Int")))},
+		{"extensible record", types.RecordOf([]types.FieldType[nameable.Testable]{types.Field[nameable.Testable](name("name"), a)}, types.Monotyped[nameable.Testable](r))},
+	}
+
+	d := decoder[nameable.Testable]{name}
+	for i, test := range tests {
+		enc, err := encodeType(test.input)
+		if err != nil {
+			t.Fatal(testutil.Testing("encode", test.description).FailMessage(nil, err, i))
+		}
+		actual, err := d.typ(enc)
+		if err != nil {
+			t.Fatal(testutil.Testing("decode", test.description).FailMessage(nil, err, i))
+		}
+		if !actual.Equals(test.input) || actual.String() != test.input.String() {
+			t.Fatal(testutil.Testing("round trip", test.description).FailMessage(test.input, actual, i))
+		}
+	}
+}
+
+func TestInterfaceFiles(t *testing.T) {
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	moduleName := nameable.MakeTestable("M")
+
+	var file bytes.Buffer
+	module := makeTestModule(t)
+	if err := module.WriteInterface(&file); err != nil {
+		t.Fatal(testutil.Testing("write").FailMessage(nil, err, 0))
+	}
+	written := file.String()
+
+	read, err := ReadInterface(strings.NewReader(written), nameable.MakeTestable)
+	if err != nil {
+		t.Fatal(testutil.Testing("read").FailMessage(nil, err, 0))
+	}
+
+	// same content, same hash
+	expect, _ := module.Hash()
+	if actual, _ := read.Hash(); actual != expect {
+		t.Fatal(testutil.Testing("hash").FailMessage(expect, actual, 0))
+	}
+
+	// module read back can be imported
+	cxt := NewTestableContext()
+	cxt.Add(zero, Int)
+	cxt.RegisterModule(read)
+	if stat := cxt.Import(NotQualified, moduleName, moduleName); stat.NotOk() {
+		t.Fatal(testutil.Testing("import").FailMessage(Ok, stat, 0))
+	}
+	just := expr.Const[nameable.Testable]{Name: "Just"}
+	actual, reports := cxt.Infer(expr.Apply[nameable.Testable](just, zero))
+	if len(reports) != 0 {
+		t.Fatal(testutil.Testing("constructor errors").FailMessage(nil, reports, 0))
+	}
+	if expect := "(Maybe Int)"; actual.String() != expect {
+		t.Fatal(testutil.Testing("constructor").FailMessage(expect, actual, 0))
+	}
+	if kind, found := cxt.GetKind(nameable.MakeTestable("Maybe")); !found || kind.String() != "* -> *" {
+		t.Fatal(testutil.Testing("kind").FailMessage("* -> *", kind, 0))
+	}
+
+	// files w/ changed content or another version are rejected
+	tests := []struct {
+		description string
+		file        string
+	}{
+		{"changed content", strings.Replace(written, `"Just"`, `"Jest"`, 1)},
+		{"other version", strings.Replace(written, `"version":1`, `"version":0`, 1)},
+	}
+	for i, test := range tests {
+		if _, err := ReadInterface(strings.NewReader(test.file), nameable.MakeTestable); err == nil {
+			t.Fatal(testutil.Testing("reject", test.description).FailMessage("error", nil, i))
+		}
+	}
+}
+
+func TestInterfaceFilesClasses(t *testing.T) {
+	var file bytes.Buffer
+	module := makeClassModule(t)
+	if err := module.WriteInterface(&file); err != nil {
+		t.Fatal(testutil.Testing("write").FailMessage(nil, err, 0))
+	}
+
+	read, err := ReadInterface(&file, nameable.MakeTestable)
+	if err != nil {
+		t.Fatal(testutil.Testing("read").FailMessage(nil, err, 0))
+	}
+
+	expect, _ := module.Hash()
+	if actual, _ := read.Hash(); actual != expect {
+		t.Fatal(testutil.Testing("hash").FailMessage(expect, actual, 0))
+	}
+
+	testClassImport(t, read, "import C from interface file")
+}
@@ -0,0 +1,408 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: kinds--the "types" of types--and their inference and checking
+//
+// Notes: the kind of a type is inferred when the type is added to the context
+// and refined by the members of its constructors. Kind variables that are
+// never refined default to `*`. Imported types keep the kinds they were
+// exported w/. Type constants that are not in the context (e.g., builtin
+// types) are opaque: each occurrence is given a new kind variable.
+//
+// Not to be confused w/ the "kinds" of the expression substitution table,
+// which are the values that index dependent types
+// =============================================================================
+package inf
+
+import (
+	"strconv"
+
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// kinds classify types. Kinds are either
+//
+//	`*` (the kind of types that have values, e.g., Int and (Maybe Int)),
+//	`k1 -> k2` (the kind of type constructors, e.g., Maybe: * -> *), or
+//	`(A)` (the kind of a value index of type A, e.g., Array: * -> (Uint) -> *)
+type Kind interface {
+	String() string
+	isKind()
+}
+
+// kind of types that have values
+type Star struct{}
+
+// kind of type constructors
+type KindArrow struct{ From, To Kind }
+
+// kind of a value index of type `Type`
+type IndexKind struct{ Type string }
+
+// unknown kind
+type KindVariable string
+
+func (Star) isKind()         {}
+func (KindArrow) isKind()    {}
+func (IndexKind) isKind()    {}
+func (KindVariable) isKind() {}
+
+func (Star) String() string { return "*" }
+
+// KindArrow{KindArrow{Star{}, Star{}}, Star{}}.String() == "(* -> *) -> *"
+func (k KindArrow) String() string {
+	from := k.From.String()
+	if _, ok := k.From.(KindArrow); ok {
+		from = "(" + from + ")"
+	}
+	return from + " -> " + k.To.String()
+}
+
+func (k IndexKind) String() string { return "(" + k.Type + ")" }
+
+func (k KindVariable) String() string { return string(k) }
+
+func (k KindVariable) GetName() string { return string(k) }
+
+// k1 -> .. -> kN -> result
+func kindArrows(ks []Kind, result Kind) Kind {
+	for i := len(ks) - 1; i >= 0; i-- {
+		result = KindArrow{ks[i], result}
+	}
+	return result
+}
+
+// kind of a type added w/ AddType along w/ the kinds of the type's params
+type typeKind struct {
+	kind   Kind
+	params map[string]Kind
+}
+
+// substitutions for kind variables
+func newKindVariableSubstitutions() substitutions[Kind] {
+	return newSubstitutions(func(k Kind) (nameable.Nameable, bool) {
+		v, ok := k.(KindVariable)
+		return v, ok
+	})
+}
+
+// returns a new kind variable
+func (cxt *Context[N]) newKindVar() KindVariable {
+	v := KindVariable("k" + strconv.FormatUint(uint64(cxt.kindVars), 10))
+	cxt.kindVars++
+	return v
+}
+
+// applies all kind substitutions to `k`
+func (cxt *Context[N]) zonkKind(k Kind) Kind {
+	switch kk := k.(type) {
+	case KindVariable:
+		if sub, found := cxt.kindVarSubs.Get(kk); found {
+			return cxt.zonkKind(sub)
+		}
+	case KindArrow:
+		return KindArrow{cxt.zonkKind(kk.From), cxt.zonkKind(kk.To)}
+	}
+	return k
+}
+
+// like zonkKind, but kind variables w/o substitutions default to `*`
+func (cxt *Context[N]) defaultKind(k Kind) Kind {
+	switch kk := cxt.zonkKind(k).(type) {
+	case KindVariable:
+		return Star{}
+	case KindArrow:
+		return KindArrow{cxt.defaultKind(kk.From), cxt.defaultKind(kk.To)}
+	default:
+		return kk
+	}
+}
+
+// returns true iff `v` appears in `k`
+func (cxt *Context[N]) kindVarOccurs(v KindVariable, k Kind) bool {
+	switch kk := cxt.zonkKind(k).(type) {
+	case KindVariable:
+		return kk == v
+	case KindArrow:
+		return cxt.kindVarOccurs(v, kk.From) || cxt.kindVarOccurs(v, kk.To)
+	}
+	return false
+}
+
+// unifies kinds `a` and `b`
+func (cxt *Context[N]) unifyKinds(a, b Kind) Status {
+	a, b = cxt.zonkKind(a), cxt.zonkKind(b)
+	if v, ok := a.(KindVariable); ok {
+		return cxt.kindVarUnion(v, b)
+	}
+	if v, ok := b.(KindVariable); ok {
+		return cxt.kindVarUnion(v, a)
+	}
+
+	switch ka := a.(type) {
+	case Star:
+		if _, ok := b.(Star); ok {
+			return Ok
+		}
+	case IndexKind:
+		if kb, ok := b.(IndexKind); ok && ka.Type == kb.Type {
+			return Ok
+		}
+	case KindArrow:
+		if kb, ok := b.(KindArrow); ok {
+			if stat := cxt.unifyKinds(ka.From, kb.From); stat.NotOk() {
+				return stat
+			}
+			return cxt.unifyKinds(ka.To, kb.To)
+		}
+	}
+	return KindMismatch
+}
+
+// declares `v` = `k`
+func (cxt *Context[N]) kindVarUnion(v KindVariable, k Kind) Status {
+	if u, ok := k.(KindVariable); ok && u == v {
+		return Ok
+	}
+	if cxt.kindVarOccurs(v, k) {
+		return InfiniteKind
+	}
+	cxt.kindVarSubs.Add(v, k)
+	return Ok
+}
+
+// returns the kind of the type named `typeName`. Second return value is false
+// iff no such type is in the context
+func (cxt *Context[N]) GetKind(typeName N) (kind Kind, found bool) {
+	var tk typeKind
+	if tk, found = cxt.typeKinds.Get(typeName); found {
+		kind = cxt.defaultKind(tk.kind)
+	}
+	return
+}
+
+// reports that `t` was expected to have kind `expected` but has kind `actual`.
+// Unknown kinds are reported as `*` unless the kinds are infinite; then they
+// are named k1, k2, .. in order of appearance
+func (cxt *Context[N]) reportKind(during string, stat Status, t types.Type[N], expected, actual Kind) {
+	report := makeTypeReport(during, stat, t)
+	if stat.Is(InfiniteKind) {
+		report.KindsInvolved = canonicalKinds(cxt.zonkKind(expected), cxt.zonkKind(actual))
+	} else {
+		report.KindsInvolved = []Kind{cxt.defaultKind(expected), cxt.defaultKind(actual)}
+	}
+	cxt.appendReport(report)
+}
+
+// renames the kind variables in `ks` to k1, k2, .. in order of appearance, so
+// names do not depend on how many kind variables were made before
+func canonicalKinds(ks ...Kind) []Kind {
+	names := make(map[KindVariable]KindVariable)
+	var rename func(Kind) Kind
+	rename = func(k Kind) Kind {
+		switch kk := k.(type) {
+		case KindVariable:
+			if _, found := names[kk]; !found {
+				names[kk] = KindVariable("k" + strconv.Itoa(len(names)+1))
+			}
+			return names[kk]
+		case KindArrow:
+			return KindArrow{rename(kk.From), rename(kk.To)}
+		}
+		return k
+	}
+	out := make([]Kind, len(ks))
+	for i, k := range ks {
+		out[i] = rename(k)
+	}
+	return out
+}
+
+// infers the kind of `t`. Type variables are given the kinds in `env`;
+// variables not in `env` are given new kind variables, which are added to
+// `env`
+func (cxt *Context[N]) inferKind(during string, env map[string]Kind, t types.Monotyped[N]) (Kind, Status) {
+	switch tt := t.(type) {
+	case types.Variable[N]:
+		k, found := env[tt.GetName()]
+		if !found {
+			k = cxt.newKindVar()
+			env[tt.GetName()] = k
+		}
+		return k, Ok
+	case types.InfixConst[N]:
+		if tt.GetName() == "->" {
+			return kindArrows([]Kind{Star{}, Star{}}, Star{}), Ok
+		}
+		return cxt.newKindVar(), Ok
+	case types.Constant[N]:
+		if tk, found := cxt.typeKinds.Get(tt.GetReferred()); found {
+			return tk.kind, Ok
+		}
+		return cxt.newKindVar(), Ok
+	case types.Application[N]:
+		return cxt.inferApplicationKind(during, env, tt, nil)
+	case types.DependentTypeInstance[N]:
+		return cxt.inferApplicationKind(during, env, tt.Application, tt.Indexes)
+	case types.Nested[N]:
+		return cxt.inferNestedKind(during, env, tt)
+	case types.Record[N]:
+		return cxt.inferRecordKind(during, env, tt)
+	}
+	// opaque
+	return cxt.newKindVar(), Ok
+}
+
+// infers the kind of the application of `app`'s head to its params and to
+// `indexes`
+func (cxt *Context[N]) inferApplicationKind(during string, env map[string]Kind, app types.Application[N], indexes types.Indexes[N]) (Kind, Status) {
+	k, stat := cxt.inferKind(during, env, app.GetHead())
+	_, params := app.Split()
+	args := make([]Kind, 0, len(params)+len(indexes))
+	for _, param := range params {
+		var kp Kind
+		if kp, stat = cxt.inferKind(during, env, param); stat.NotOk() {
+			return kp, stat
+		}
+		args = append(args, kp)
+	}
+	for _, index := range indexes {
+		_, ty := index.AsTypeJudgment().GetExpressionAndType()
+		args = append(args, IndexKind{ty.String()})
+	}
+
+	if stat.NotOk() {
+		return k, stat
+	}
+
+	result := cxt.newKindVar()
+	if stat = cxt.unifyKinds(k, kindArrows(args, result)); stat.NotOk() {
+		cxt.reportKind(during, stat, types.Type[N](app.GetHead()), kindArrows(args, result), k)
+		return k, stat
+	}
+	return result, Ok
+}
+
+// nested polytypes are types of values. Variables bound by the nested polytype
+// shadow those in `env`
+func (cxt *Context[N]) inferNestedKind(during string, env map[string]Kind, nested types.Nested[N]) (Kind, Status) {
+	sigma := nested.GetPolytype()
+	inner := make(map[string]Kind, len(env))
+	for name, k := range env {
+		inner[name] = k
+	}
+	for _, binder := range sigma.GetBinders() {
+		inner[binder.GetName()] = cxt.newKindVar()
+	}
+
+	if stat := cxt.checkKind(during, inner, sigma); stat.NotOk() {
+		return nil, stat
+	}
+	// propagate kinds of variables free in the nested polytype
+	for name, k := range inner {
+		if _, bound := env[name]; !bound && !isBinderOf(sigma, name) {
+			env[name] = k
+		}
+	}
+	return Star{}, Ok
+}
+
+// records are types of values, and so are the types of their fields
+func (cxt *Context[N]) inferRecordKind(during string, env map[string]Kind, record types.Record[N]) (Kind, Status) {
+	for _, field := range record.GetFields() {
+		if stat := cxt.checkKind(during, env, field.Type); stat.NotOk() {
+			return nil, stat
+		}
+	}
+	return Star{}, Ok
+}
+
+// returns true iff `sigma` binds a variable named `name`
+func isBinderOf[N nameable.Nameable](sigma types.Polytype[N], name string) bool {
+	for _, binder := range sigma.GetBinders() {
+		if binder.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checks that `ty` is the type of some values, i.e., that it has kind `*`
+func (cxt *Context[N]) checkKind(during string, env map[string]Kind, ty types.Type[N]) Status {
+	m := kindCheckable(ty)
+	k, stat := cxt.inferKind(during, env, m)
+	if stat.NotOk() {
+		return stat
+	}
+	if stat = cxt.unifyKinds(k, Star{}); stat.NotOk() {
+		cxt.reportKind(during, stat, ty, Star{}, k)
+	}
+	return stat
+}
+
+// returns the monotype w/in `ty`
+func kindCheckable[N nameable.Nameable](ty types.Type[N]) types.Monotyped[N] {
+	if q, ok := ty.(types.Qualified[N]); ok {
+		ty = q.GetPolytype()
+	}
+	if sigma, ok := ty.(types.Polytype[N]); ok {
+		ty = sigma.GetBound()
+	}
+	return types.GetDependent(ty.(types.DependentTyped[N]))
+}
+
+// infers the kind of the type `sigma` added under `name`
+func (cxt *Context[N]) addTypeKind(name N, sigma types.Polytype[N]) {
+	params := make(map[string]Kind)
+	var args []Kind
+	bound := sigma.GetBound()
+	if d, ok := bound.(types.DependentType[N]); ok {
+		for _, dependee := range types.GetDependees[N](d) {
+			_, ty := dependee.GetExpressionAndType()
+			args = append(args, IndexKind{ty.String()})
+		}
+	}
+
+	if app, ok := types.GetDependent(bound).(types.TypeFunction[N]); ok {
+		function, _ := app.FunctionAndIndexes()
+		_, ts := function.Split()
+		kinds := make([]Kind, len(ts))
+		for i, t := range ts {
+			kinds[i] = cxt.newKindVar()
+			if v, isVar := t.(types.Variable[N]); isVar {
+				params[v.GetName()] = kinds[i]
+			}
+		}
+		args = append(kinds, args...)
+	}
+
+	cxt.typeKinds.Add(name, typeKind{kindArrows(args, Star{}), params})
+	cxt.onRollback(func() { cxt.typeKinds.Remove(name) })
+}
+
+// gives the imported type `name` the kind `kind` it was exported w/
+func (cxt *Context[N]) addImportedKind(name N, kind Kind) {
+	cxt.typeKinds.Add(name, typeKind{kind, nil})
+	cxt.onRollback(func() { cxt.typeKinds.Remove(name) })
+}
+
+// checks that each member of a constructor for the type named `typeName` has
+// kind `*`
+func (cxt *Context[N]) checkMemberKinds(typeName N, memberTypes []types.Type[N]) Status {
+	tk, found := cxt.typeKinds.Get(typeName)
+	if !found {
+		return Ok
+	}
+
+	env := make(map[string]Kind, len(tk.params))
+	for name, k := range tk.params {
+		env[name] = k
+	}
+	for _, ty := range memberTypes {
+		if stat := cxt.checkKind("Add Constructor", env, ty); stat.NotOk() {
+			return stat
+		}
+	}
+	return Ok
+}
@@ -0,0 +1,133 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// creates a context w/
+//
+//	List a = Nil
+//	Fix f = In (f (Fix f))
+//	Array a; (n: Uint)
+func makeKindTestContext() *Context[nameable.Testable] {
+	cxt := NewTestableContext()
+	name := nameable.MakeTestable
+	a, f := types.Var(name("a")), types.Var(name("f"))
+	x := expr.Var(name("x"))
+
+	cxt.AddType(name("List"), types.Apply[nameable.Testable](types.MakeConst(name("List")), a))
+	cxt.AddConstructorFor(name("List"), bridge.MakeData(expr.MakeConst(name("Nil"))))
+
+	Fix_f := types.Apply[nameable.Testable](types.MakeConst(name("Fix")), f)
+	cxt.AddType(name("Fix"), Fix_f)
+	member := bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, types.Apply[nameable.Testable](f, Fix_f))
+	cxt.AddConstructorFor(name("Fix"), bridge.MakeData(expr.MakeConst(name("In")), member))
+
+	n := expr.Var(name("n"))
+	Uint := types.MakeConst(name("Uint"))
+	n_Uint := types.Judgment(expr.Referable[nameable.Testable](n), types.Type[nameable.Testable](Uint))
+	Array_a := types.Apply[nameable.Testable](types.MakeConst(name("Array")), a)
+	domain := []types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]]{n_Uint}
+	cxt.AddType(name("Array"), types.Index(Array_a, domain...))
+	return cxt
+}
+
+func TestGetKind(t *testing.T) {
+	tests := []struct {
+		typeName string
+		expect   string
+	}{
+		{"List", "* -> *"},
+		{"Fix", "(* -> *) -> *"},
+		{"Array", "* -> (Uint) -> *"},
+	}
+
+	for i, test := range tests {
+		cxt := makeKindTestContext()
+		if len(cxt.GetReports()) != 0 {
+			t.Fatal(testutil.Testing("reports", test.typeName).FailMessage(nil, cxt.GetReports(), i))
+		}
+		kind, found := cxt.GetKind(nameable.MakeTestable(test.typeName))
+		if !found {
+			t.Fatal(testutil.Testing("found", test.typeName).FailMessage(true, found, i))
+		}
+		if kind.String() != test.expect {
+			t.Fatal(testutil.Testing("kind", test.typeName).FailMessage(test.expect, kind.String(), i))
+		}
+	}
+}
+
+func TestKindErrors(t *testing.T) {
+	name := nameable.MakeTestable
+	Int := types.MakeConst(name("Int"))
+	List := types.MakeConst(name("List"))
+	a := types.Var(name("a"))
+	x := expr.Var(name("x"))
+	nil_ := expr.MakeConst(name("Nil"))
+	member := func(ty types.Monotyped[nameable.Testable]) bridge.Data[nameable.Testable] {
+		j := bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](x, ty)
+		return bridge.MakeData(expr.MakeConst(name("C")), j)
+	}
+
+	tests := []struct {
+		description string
+		run         func(cxt *Context[nameable.Testable]) Status
+		expect      Status
+		message     string
+	}{
+		{
+			`Nil: List Int Int`,
+			func(cxt *Context[nameable.Testable]) Status {
+				return cxt.Check(nil_, types.Apply[nameable.Testable](List, Int, Int)).Status
+			},
+			KindMismatch,
+			"List does not have kind * -> * -> * (it has kind * -> *)",
+		},
+		{
+			`Nil: List`,
+			func(cxt *Context[nameable.Testable]) Status {
+				return cxt.Check(nil_, List).Status
+			},
+			KindMismatch,
+			"List does not have kind * (it has kind * -> *)",
+		},
+		{
+			`List a = C (List a a)`,
+			func(cxt *Context[nameable.Testable]) Status {
+				return cxt.AddConstructorFor(name("List"), member(types.Apply[nameable.Testable](List, a, a)))
+			},
+			KindMismatch,
+			"List does not have kind * -> * -> * (it has kind * -> *)",
+		},
+		{
+			`T a = C (a a)`,
+			func(cxt *Context[nameable.Testable]) Status {
+				cxt.AddType(name("T"), types.Apply[nameable.Testable](types.MakeConst(name("T")), a))
+				return cxt.AddConstructorFor(name("T"), member(types.Apply[nameable.Testable](a, a)))
+			},
+			InfiniteKind,
+			"a cannot have infinite kind k1 -> k2 (it has kind k1)",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeKindTestContext()
+		actual := test.run(cxt)
+		if !actual.Is(test.expect) {
+			t.Fatal(testutil.Testing("status", test.description).FailMessage(test.expect, actual, i))
+		}
+		reports := cxt.GetReports()
+		if len(reports) != 1 {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(1, len(reports), i))
+		}
+		if msg := reports[0].Message(); msg != test.message {
+			t.Fatal(testutil.Testing("message", test.description).FailMessage(test.message, msg, i))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: normalization of the indexes of dependent types, so indexes that
+// are equal by computation unify
+//
+// Notes: indexes are reduced w/ expr's own reduction machinery--beta reduction
+// via Again and instruction calls via ForceRequest--and names defined w/
+// AddDefinition are unfolded (delta reduction). Indexes are only reduced to
+// weak head normal form; unification reduces the members of data it compares
+// when it gets to them. Every normalization is limited to a number of steps
+// (see SetFuel), so normalization always terminates. An index that runs out of
+// fuel is compared as it is
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// default number of reduction steps allowed to normalize a single index
+const DefaultFuel uint = 1000
+
+// sets number of reduction steps allowed to normalize a single index
+func (cxt *Context[N]) SetFuel(fuel uint) {
+	cxt.fuel = fuel
+}
+
+// defines `name` as `definition` for the purpose of normalizing indexes: when
+// `name` is the head of an index, it is replaced by `definition`. Returns false
+// (and reports the redefinition) iff `name` is already defined
+func (cxt *Context[N]) AddDefinition(name expr.Const[N], definition expr.Expression[N]) (added bool) {
+	key := name.Name
+	if _, found := cxt.definitions.Get(key); found {
+		cxt.appendReport(makeNameReport("Define", IllegalShadow, name))
+		return false
+	}
+	cxt.definitions.Add(key, definition)
+	cxt.onRollback(func() { cxt.definitions.Remove(key) })
+	return true
+}
+
+// returns `e` as an index
+func asIndex[N nameable.Nameable](e expr.Expression[N]) expr.Referable[N] {
+	if ref, ok := e.(expr.Referable[N]); ok {
+		return ref
+	}
+	return expr.Compute(e)
+}
+
+// splits `e` into the expression at its head and the arguments it is applied to
+//
+//	spine(f x y) = f, [x, y]
+func spine[N nameable.Nameable](e expr.Expression[N]) (head expr.Expression[N], args []expr.Expression[N]) {
+	head = e
+	for app, ok := head.(expr.Application[N]); ok; app, ok = head.(expr.Application[N]) {
+		var arg expr.Expression[N]
+		head, arg = app.Split()
+		args = append([]expr.Expression[N]{arg}, args...)
+	}
+	return
+}
+
+// replaces the free variables of `e` that have substitutions w/ what they are
+// substituted by
+func (cxt *Context[N]) substituteIndex(e expr.Expression[N]) expr.Expression[N] {
+	for _, v := range e.ExtractVariables(-1) {
+		if !v.StrictEquals(expr.Var(v.GetReferred())) {
+			continue // bound w/in `e`
+		}
+		if sub := cxt.GetKindSub(v); !sub.StrictEquals(v) {
+			var value expr.Expression[N] = sub
+			if c, ok := sub.(expr.Computed[N]); ok {
+				value = c.GetExpression()
+			}
+			e, _ = e.Replace(v, value)
+		}
+	}
+	return e
+}
+
+// replaces the name at the head of `e` w/ its definition, if it has one
+func (cxt *Context[N]) unfold(e expr.Expression[N]) (unfolded expr.Expression[N], ok bool) {
+	head, args := spine(e)
+	c, isConst := head.(expr.Const[N])
+	if !isConst {
+		return e, false
+	}
+	definition, found := cxt.definitions.Get(c.Name)
+	if !found {
+		return e, false
+	}
+	if len(args) == 0 {
+		return definition, true
+	}
+	return expr.Apply(definition, args[0], args[1:]...), true
+}
+
+// reduces `e` until it cannot be reduced further or `fuel` runs out
+func (cxt *Context[N]) reduce(e expr.Expression[N], fuel *uint) expr.Expression[N] {
+	for ; *fuel > 0; *fuel-- {
+		next, unfolded := cxt.unfold(e)
+		next, _ = next.Again()
+		next = next.ForceRequest()
+		if !unfolded && next.StrictEquals(e) {
+			break
+		}
+		e = next
+	}
+	return e
+}
+
+// returns the weak head normal form of index `e`, or `e` reduced as far as its
+// fuel allows
+func (cxt *Context[N]) normalizeIndex(e expr.Referable[N]) expr.Referable[N] {
+	fuel := cxt.fuel
+	return cxt.normalize(e, &fuel)
+}
+
+func (cxt *Context[N]) normalize(e expr.Referable[N], fuel *uint) expr.Referable[N] {
+	switch x := cxt.FindKind(e).(type) {
+	case expr.Computed[N]:
+		res := cxt.reduce(cxt.substituteIndex(x.GetExpression()), fuel)
+		if ref, ok := res.(expr.Referable[N]); ok {
+			if _, computed := ref.(expr.Computed[N]); !computed {
+				return cxt.normalize(ref, fuel)
+			}
+		}
+		return expr.Compute(res)
+	case expr.Const[N]:
+		if definition, found := cxt.definitions.Get(x.Name); found && *fuel > 0 {
+			*fuel--
+			return cxt.normalize(asIndex(definition), fuel)
+		}
+		return x
+	default:
+		return x
+	}
+}
+
+// unifies indexes `a` and `b` that are stuck--they cannot be reduced further
+// but are not data, constants, or variables--by unifying the heads and
+// arguments of their spines
+func (cxt *Context[N]) unifyStuck(a, b expr.Computed[N]) Status {
+	headA, argsA := spine(a.GetExpression())
+	headB, argsB := spine(b.GetExpression())
+	if len(argsA) != len(argsB) || !headA.StrictEquals(headB) {
+		return KindConstantMismatch
+	}
+	for i := range argsA {
+		if stat := cxt.UnifyKind(asIndex(argsA[i]), asIndex(argsB[i])); stat.NotOk() {
+			return stat
+		}
+	}
+	return Ok
+}
@@ -0,0 +1,77 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestNormalizeIndexes(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	n, x := expr.Var(name("n")), expr.Var(name("x"))
+	a := types.Var(name("a"))
+	Nat, Int := types.MakeConst(name("Nat")), types.MakeConst(name("Int"))
+	Vec := types.MakeConst(name("Vec"))
+
+	// Succ e
+	succ := func(e expr.Expression[nameable.Testable]) expr.Referable[nameable.Testable] {
+		return bridge.MakeData(c("Succ"), bridge.Judgment(e, types.Type[nameable.Testable](Nat)))
+	}
+	// e + k for k = 0 or k = 1
+	plus := func(e, k expr.Expression[nameable.Testable]) expr.Referable[nameable.Testable] {
+		head := expr.DefineInstruction[nameable.Testable]("+", 2, func(args expr.InstructionArgs[nameable.Testable]) expr.Expression[nameable.Testable] {
+			if args.GetArgAtIndex(1).String() == "0" {
+				return args.GetArgAtIndex(0)
+			}
+			return succ(args.GetArgAtIndex(0))
+		})
+		return expr.Compute[nameable.Testable](expr.Apply[nameable.Testable](head.MakeInstance(), e, k))
+	}
+	apply := func(e0, e1 expr.Expression[nameable.Testable]) expr.Referable[nameable.Testable] {
+		return expr.Compute[nameable.Testable](expr.Apply(e0, e1))
+	}
+	// (Vec t; e)
+	vec := func(t types.Monotyped[nameable.Testable], e expr.Referable[nameable.Testable]) types.Monotyped[nameable.Testable] {
+		index := types.ExpressionJudgment[nameable.Testable, expr.Referable[nameable.Testable]](types.Judgment(e, types.Type[nameable.Testable](Nat)))
+		return types.Index(types.Apply[nameable.Testable](Vec, t), index)
+	}
+
+	tests := []struct {
+		description string
+		a, b        types.Monotyped[nameable.Testable]
+		expect      Status
+	}{
+		{`(Vec a; n + 1) = (Vec a; Succ n)`, vec(a, plus(n, c("1"))), vec(a, succ(n)), Ok},
+		{`(Vec a; n + 0) = (Vec Int; n)`, vec(a, plus(n, c("0"))), vec(Int, n), Ok},
+		{`(Vec a; n + 1) = (Vec a; Succ (Succ Zero))`, vec(a, plus(n, c("1"))), vec(a, succ(succ(c("Zero")))), Ok},
+		{`(Vec a; inc n) = (Vec a; Succ n)`, vec(a, apply(c("inc"), n)), vec(a, succ(n)), Ok},
+		{`(Vec a; two) = (Vec a; Succ (Succ Zero))`, vec(a, c("two")), vec(a, succ(succ(c("Zero")))), Ok},
+		{`(Vec a; Succ (inc Zero)) = (Vec a; two)`, vec(a, succ(apply(c("inc"), c("Zero")))), vec(a, c("two")), Ok},
+		{`(Vec a; f n) = (Vec a; f Zero)`, vec(a, apply(c("f"), n)), vec(a, apply(c("f"), c("Zero"))), Ok},
+		{`(Vec a; inc n) = (Vec a; Zero)`, vec(a, apply(c("inc"), n)), vec(a, c("Zero")), KindConstantMismatch},
+		{`(Vec a; f n) = (Vec a; g n)`, vec(a, apply(c("f"), n)), vec(a, apply(c("g"), n)), KindConstantMismatch},
+		{`(Vec a; loop) = (Vec a; Zero)`, vec(a, c("loop")), vec(a, c("Zero")), KindConstantMismatch},
+		{`(Vec a; loop n) = (Vec a; Zero)`, vec(a, apply(c("loop"), n)), vec(a, c("Zero")), KindConstantMismatch},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		cxt.SetFuel(100)
+		// inc = λx . Succ x
+		cxt.AddDefinition(c("inc"), expr.Bind(x).In(expr.Expression[nameable.Testable](succ(x))))
+		// one = Succ Zero; two = Succ one
+		cxt.AddDefinition(c("one"), succ(c("Zero")))
+		cxt.AddDefinition(c("two"), succ(c("one")))
+		// loop = loop
+		cxt.AddDefinition(c("loop"), c("loop"))
+
+		if stat := cxt.Unify(test.a, test.b); !stat.Is(test.expect) {
+			t.Fatal(testutil.Testing("unify", test.description).FailMessage(test.expect, stat, i))
+		}
+	}
+}
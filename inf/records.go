@@ -0,0 +1,291 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: inference rules for records and unification of record types
+//
+// Notes: record types (see types.Record) never have two fields w/ the same
+// label. So, each row variable "lacks" the labels of the fields it extends
+// and the labels of fields added to it w/ extension expressions. Lacks
+// constraints are recorded per row variable and checked when the variable is
+// unified; lacks constraints are not kept in polytypes, but those implied by
+// the records a row variable extends are recovered when a polytype is
+// instantiated
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// returns labels row variable `v` lacks
+func (cxt *Context[N]) lacksOf(v types.Variable[N]) []string {
+	labels, _ := cxt.lacks.Get(v.GetReferred())
+	return labels
+}
+
+// constrains row variable `v` to lack each of `labels`
+func (cxt *Context[N]) addLacks(v types.Variable[N], labels ...string) {
+	old, found := cxt.lacks.Get(v.GetReferred())
+	lacks := append([]string{}, old...)
+	for _, label := range labels {
+		if !hasLabel(lacks, label) {
+			lacks = append(lacks, label)
+		}
+	}
+	if len(lacks) == len(old) {
+		return
+	}
+
+	name := v.GetReferred()
+	cxt.lacks.Add(name, lacks)
+	if found {
+		cxt.onRollback(func() { cxt.lacks.Add(name, old) })
+	} else {
+		cxt.onRollback(func() { cxt.lacks.Remove(name) })
+	}
+}
+
+// returns true iff `label` is in `labels`
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// returns labels of fields of `r`
+func labelsOf[N nameable.Nameable](r types.Record[N]) []string {
+	return fun.FMap(r.GetFields(), func(f types.FieldType[N]) string { return f.Label.GetName() })
+}
+
+// adds the lacks constraints implied by the record types in `m`: the row
+// variable of each record type lacks the labels of the record's fields
+func (cxt *Context[N]) impliedLacks(m types.Monotyped[N]) {
+	switch t := m.(type) {
+	case types.Record[N]:
+		if row, extensible := t.GetRow(); extensible {
+			cxt.addLacks(row, labelsOf(t)...)
+		}
+		for _, field := range t.GetFields() {
+			cxt.impliedLacks(field.Type)
+		}
+	case types.TypeFunction[N]:
+		function, _ := t.FunctionAndIndexes()
+		_, params := function.Split()
+		for _, param := range params {
+			cxt.impliedLacks(param)
+		}
+	}
+}
+
+// checks that `t` has none of the labels `v` lacks, then constrains the row
+// variables in `t` to lack them too. Called when `v` = `t` is declared
+func (cxt *Context[N]) checkLacks(v types.Variable[N], t types.Monotyped[N]) Status {
+	lacks := cxt.lacksOf(v)
+	switch r := t.(type) {
+	case types.Variable[N]:
+		// either variable may end up representing the other
+		cxt.addLacks(r, lacks...)
+		cxt.addLacks(v, cxt.lacksOf(r)...)
+	case types.Record[N]:
+		if len(lacks) == 0 {
+			return Ok
+		}
+		for _, label := range labelsOf(r) {
+			if hasLabel(lacks, label) {
+				cxt.mismatch = []types.Type[N]{v, r}
+				return DuplicateLabel
+			}
+		}
+		if row, extensible := r.GetRow(); extensible {
+			cxt.addLacks(row, lacks...)
+		}
+	}
+	return Ok
+}
+
+// unifies record types `a` and `b`:
+//
+//	{l1: a1, .., lN: aN | ρa} = {l1: b1, .., lN: bN, m1: c1, .. | ρb}
+//
+// holds iff each ai = bi, and--for a new row variable ρ--
+//
+//	ρa = {m1: c1, .. | ρ}    and    ρb = {.. | ρ}
+//
+// where the fields of ρb are those only `a` has. When a record type is closed,
+// the other record type cannot have fields it does not have. A skolem row (see
+// types.(*Context).Skolem) is never substituted for, so it takes the place of
+// ρ, and the record type it ends cannot gain fields
+func (cxt *Context[N]) unifyRecords(a, b types.Record[N]) Status {
+	rowA, extensibleA := a.GetRow()
+	rowB, extensibleB := b.GetRow()
+	if extensibleA {
+		cxt.addLacks(rowA, labelsOf(a)...)
+	}
+	if extensibleB {
+		cxt.addLacks(rowB, labelsOf(b)...)
+	}
+
+	var onlyA, onlyB []types.FieldType[N]
+	for _, field := range a.GetFields() {
+		if ty, found := b.Get(field.Label.GetName()); found {
+			if stat := cxt.Unify(field.Type, ty); stat.NotOk() {
+				return stat
+			}
+		} else {
+			onlyA = append(onlyA, field)
+		}
+	}
+	for _, field := range b.GetFields() {
+		if _, found := a.Get(field.Label.GetName()); !found {
+			onlyB = append(onlyB, field)
+		}
+	}
+
+	missing := (len(onlyB) != 0 && !extensibleA) || (len(onlyA) != 0 && !extensibleB)
+	if missing || (extensibleA && extensibleB && rowA.Equals(rowB) && len(onlyA)+len(onlyB) != 0) {
+		cxt.mismatch = []types.Type[N]{cxt.GetSub(a), cxt.GetSub(b)}
+		return MissingLabel
+	}
+
+	var rest types.Monotyped[N]
+	if extensibleA && extensibleB {
+		if rowA.Equals(rowB) {
+			return Ok
+		}
+		if rowA.IsRigid() {
+			rest = rowA
+		} else if rowB.IsRigid() {
+			rest = rowB
+		} else {
+			rest = cxt.TypeContext.NewVar()
+		}
+	}
+	if extensibleA {
+		if stat := cxt.unifyRow(rowA, onlyB, rest); stat.NotOk() {
+			return stat
+		}
+	}
+	if extensibleB {
+		return cxt.unifyRow(rowB, onlyA, rest)
+	}
+	return Ok
+}
+
+// unifies row variable `row` w/ `{fields | rest}`; `row` is already `rest`
+// when it is the skolem ending both record types
+func (cxt *Context[N]) unifyRow(row types.Variable[N], fields []types.FieldType[N], rest types.Monotyped[N]) Status {
+	if len(fields) == 0 && rest != nil && row.Equals(rest) {
+		return Ok
+	}
+	return cxt.Unify(row, types.RecordOf(fields, rest))
+}
+
+// [Record] rule:
+//
+//	𝚪 ⊢ e1: t1    ...    𝚪 ⊢ eN: tN
+//	------------------------------------------------ [Record]
+//	𝚪 ⊢ {l1 = e1, .., lN = eN}: {l1: t1, .., lN: tN}
+//
+// no two fields may have the same label
+func (cxt *Context[N]) Record(labels []N) func(js []TypeJudgment[N]) Conclusion[N, expr.Record[N], types.Monotyped[N]] {
+	return func(js []TypeJudgment[N]) Conclusion[N, expr.Record[N], types.Monotyped[N]] {
+		record := make(expr.Record[N], len(labels))
+		fields := make([]types.FieldType[N], len(labels))
+		for i, label := range labels {
+			if _, found := record[:i].Get(label); found {
+				cxt.appendReport(makeNameReport("Record", DuplicateLabel, expr.MakeConst(label)))
+				return CannotConclude[N, expr.Record[N], types.Monotyped[N]](DuplicateLabel)
+			}
+
+			e, t := js[i].GetExpressionAndType()
+			record[i] = expr.MakeField(label, e)
+			fields[i] = types.Field(label, t.(types.Monotyped[N]))
+		}
+		return Conclude[N](record, types.Monotyped[N](types.Closed(fields...)))
+	}
+}
+
+// [Access] rule:
+//
+//	𝚪 ⊢ e: t    a = newvar    ρ = newvar    t = {l: a | ρ}
+//	----------------------------------------------------- [Access]
+//	                     𝚪 ⊢ e.l: a
+func (cxt *Context[N]) Access(label N, j TypeJudgment[N]) Conclusion[N, expr.Access[N], types.Monotyped[N]] {
+	e, tmp := j.GetExpressionAndType()
+	a := cxt.TypeContext.NewVar()
+	rho := cxt.TypeContext.NewVar()
+	record := types.RecordOf([]types.FieldType[N]{types.Field[N](label, a)}, types.Monotyped[N](rho))
+	if stat := cxt.Unify(tmp.(types.Monotyped[N]), record); stat.NotOk() {
+		cxt.appendReport(makeReport("Access", stat, j))
+		return CannotConclude[N, expr.Access[N], types.Monotyped[N]](stat)
+	}
+	return Conclude[N](expr.AccessField(e, label), cxt.GetSub(a))
+}
+
+// [Extend] rule:
+//
+//	𝚪 ⊢ e0: t0    𝚪 ⊢ e1: t1    ρ = newvar    ρ lacks l    t1 = {ρ}
+//	-------------------------------------------------------------- [Extend]
+//	              𝚪 ⊢ {l = e0 | e1}: {l: t0 | ρ}
+func (cxt *Context[N]) Extend(label N, j0, j1 TypeJudgment[N]) Conclusion[N, expr.Extension[N], types.Monotyped[N]] {
+	e0, t0 := j0.GetExpressionAndType()
+	e1, t1 := j1.GetExpressionAndType()
+	rho := cxt.TypeContext.NewVar()
+	cxt.addLacks(rho, label.GetName())
+	base := types.RecordOf(nil, types.Monotyped[N](rho))
+	if stat := cxt.Unify(base, t1.(types.Monotyped[N])); stat.NotOk() {
+		cxt.appendReport(makeReport("Extend", stat, j0, j1))
+		return CannotConclude[N, expr.Extension[N], types.Monotyped[N]](stat)
+	}
+
+	fields := []types.FieldType[N]{types.Field(label, t0.(types.Monotyped[N]))}
+	t := cxt.GetSub(types.RecordOf(fields, types.Monotyped[N](rho)))
+	return Conclude[N](expr.Extend(e1, label, e0), t)
+}
+
+func (cxt *Context[N]) inferRecord(record expr.Record[N]) exprConclusion[N] {
+	labels := make([]N, len(record))
+	js := make([]TypeJudgment[N], len(record))
+	fields := make([]Core[N], len(record))
+	for i, field := range record {
+		c := cxt.infer(field.Value)
+		if c.NotOk() {
+			return c
+		}
+		labels[i], js[i], fields[i] = field.Label, c.judgment, c.core
+	}
+	return generalConclusion(cxt.Record(labels)(js)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreRecord[N]{labels, fields, t}
+	})
+}
+
+func (cxt *Context[N]) inferAccess(access expr.Access[N]) exprConclusion[N] {
+	c := cxt.infer(access.GetRecord())
+	if c.NotOk() {
+		return c
+	}
+	return generalConclusion(cxt.Access(access.GetLabel(), c.judgment)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreAccess[N]{c.core, access.GetLabel(), t}
+	})
+}
+
+func (cxt *Context[N]) inferExtension(extension expr.Extension[N]) exprConclusion[N] {
+	field := extension.GetField()
+	c0 := cxt.infer(field.Value)
+	if c0.NotOk() {
+		return c0
+	}
+	c1 := cxt.infer(extension.GetRecord())
+	if c1.NotOk() {
+		return c1
+	}
+	return generalConclusion(cxt.Extend(field.Label, c0.judgment, c1.judgment)).withCore(func(t types.Monotyped[N]) Core[N] {
+		return CoreExtension[N]{field.Label, c0.core, c1.core, t}
+	})
+}
@@ -0,0 +1,109 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestRecords(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	field := func(label string, value expr.Expression[nameable.Testable]) expr.Field[nameable.Testable] {
+		return expr.MakeField[nameable.Testable](name(label), value)
+	}
+	r := expr.Var(name("r"))
+
+	// λr . r.name
+	getName := expr.Bind(r).In(expr.AccessField[nameable.Testable](r, name("name")))
+	// λr . {name = 0 | r}
+	setName := expr.Bind(r).In(expr.Extend[nameable.Testable](r, name("name"), c("0")))
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		stat        Status
+		expect      string
+	}{
+		{
+			`λr . r.name`,
+			getName,
+			Ok,
+			"({name: $1 | $2} -> $1)",
+		},
+		{
+			`(λr . r.name) {age = 0, name = true}`,
+			expr.Apply[nameable.Testable](getName, expr.Record[nameable.Testable]{field("age", c("0")), field("name", c("true"))}),
+			Ok,
+			"Bool",
+		},
+		{
+			`{name = 0 | {age = true}}`,
+			expr.Extend[nameable.Testable](expr.Record[nameable.Testable]{field("age", c("true"))}, name("name"), c("0")),
+			Ok,
+			"{age: Bool, name: Int}",
+		},
+		{
+			`λr . {name = 0 | r}`,
+			setName,
+			Ok,
+			"({$1} -> {name: Int | $1})",
+		},
+		{
+			`{name = 0 | 0}`,
+			expr.Extend[nameable.Testable](c("0"), name("name"), c("0")),
+			ConstantMismatch,
+			"",
+		},
+		{
+			`(λr . {name = 0 | r}) 0`,
+			expr.Apply[nameable.Testable](setName, c("0")),
+			ConstantMismatch,
+			"",
+		},
+		{
+			`{age = 0}.name`,
+			expr.AccessField[nameable.Testable](expr.Record[nameable.Testable]{field("age", c("0"))}, name("name")),
+			MissingLabel,
+			"",
+		},
+		{
+			`{name = 0 | {name = true}}`,
+			expr.Extend[nameable.Testable](expr.Record[nameable.Testable]{field("name", c("true"))}, name("name"), c("0")),
+			DuplicateLabel,
+			"",
+		},
+		{
+			`let setName = λr . {name = 0 | r} in setName {name = true}`,
+			expr.Let[nameable.Testable](c("setName"), setName, expr.Apply[nameable.Testable](c("setName"), expr.Record[nameable.Testable]{field("name", c("true"))})),
+			DuplicateLabel,
+			"",
+		},
+		{
+			`{name = 0, name = true}`,
+			expr.Record[nameable.Testable]{field("name", c("0")), field("name", c("true"))},
+			DuplicateLabel,
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		cxt.Add(c("0"), types.MakeConst(name("Int")))
+		cxt.Add(c("true"), types.MakeConst(name("Bool")))
+		sigma, reports := cxt.Infer(test.input)
+		if test.stat.IsOk() {
+			if len(reports) != 0 {
+				t.Fatal(testutil.Testing("reports", test.description).FailMessage(nil, reports, i))
+			}
+			if actual := types.GetDependent(sigma.GetBound()).String(); actual != test.expect {
+				t.Fatal(testutil.Testing("type", test.description).FailMessage(test.expect, actual, i))
+			}
+		} else if len(reports) != 1 || !reports[0].Status.Is(test.stat) {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(test.stat, reports, i))
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package inf
 
 import (
+	"strings"
+
 	"github.com/petersalex27/yew-packages/expr"
 	"github.com/petersalex27/yew-packages/nameable"
 	"github.com/petersalex27/yew-packages/types"
@@ -12,18 +14,298 @@ type errorReport[N nameable.Nameable] struct {
 	TermsInvolved []TypeJudgment[N]
 	Names         []expr.Const[N]
 	TypesInvolved []types.Type[N]
+	KindsInvolved []Kind
+	// source code of the expression being inferred when the report was made
+	Span sourceSpan
 }
 
 // creates an errorReport for a failed rule
 func makeReport[N nameable.Nameable](duringRule string, status Status, withTerms ...TypeJudgment[N]) errorReport[N] {
-	return errorReport[N]{duringRule, status, withTerms, nil, nil}
+	return errorReport[N]{During: duringRule, Status: status, TermsInvolved: withTerms}
 }
 
 // creates an errorReport for a failed context lookup
 func makeNameReport[N nameable.Nameable](duringRule string, status Status, withNames ...expr.Const[N]) errorReport[N] {
-	return errorReport[N]{duringRule, status, nil, withNames, nil}
+	return errorReport[N]{During: duringRule, Status: status, Names: withNames}
 }
 
 func makeTypeReport[N nameable.Nameable](during string, status Status, withTypes ...types.Type[N]) errorReport[N] {
-	return errorReport[N]{during, status, nil, nil, withTypes}
+	return errorReport[N]{During: during, Status: status, TypesInvolved: withTypes}
+}
+
+// creates an errorReport for a class constraint `p`
+func makePredicateReport[N nameable.Nameable](during string, status Status, p types.Predicate[N]) errorReport[N] {
+	ts := make([]types.Type[N], len(p.GetParams()))
+	for i, param := range p.GetParams() {
+		ts[i] = param
+	}
+	return errorReport[N]{During: during, Status: status, Names: []expr.Const[N]{expr.MakeConst(p.GetClass())}, TypesInvolved: ts}
+}
+
+// returns class constraint reported by a report made w/ makePredicateReport
+func (report errorReport[N]) predicate() string {
+	out := report.subject()
+	for _, t := range report.types() {
+		out = out + " " + t
+	}
+	return out
+}
+
+// names that know where they were written in source code. Reports are located
+// at the expression being inferred when they were made, which spans the
+// locatable names in it
+type Locatable interface {
+	// returns path of source file and line and character number (each starting
+	// at 1) of the first character of the name
+	GetLocation() (path string, line, char int)
+}
+
+// source code from line `line`, character `char` up to, but not including,
+// character `char + size`; line is 0 when the source code is unknown
+type sourceSpan struct {
+	path             string
+	line, char, size int
+}
+
+// returns span from the first locatable name in `names` to the end of the last
+// one on the same line
+func spanOf[N nameable.Nameable](names []N) (span sourceSpan) {
+	end := 0
+	for _, name := range names {
+		loc, ok := any(name).(Locatable)
+		if !ok {
+			continue
+		}
+		path, line, char := loc.GetLocation()
+		if line == 0 {
+			continue
+		}
+		if span.line == 0 || line < span.line || (line == span.line && char < span.char) {
+			if line != span.line {
+				end = 0
+			}
+			span.path, span.line, span.char = path, line, char
+		}
+		if line == span.line && char+len(name.GetName()) > end {
+			end = char + len(name.GetName())
+		}
+	}
+	span.size = end - span.char
+	return
+}
+
+// returns status of report
+func (report errorReport[N]) GetStatus() Status {
+	return report.Status
+}
+
+// returns kind of problem reported: "Kind" for ill-kinded types and "Type"
+// otherwise
+func (report errorReport[N]) GetSubtype() string {
+	if report.Status.isKindFailure() {
+		return "Kind"
+	}
+	return "Type"
+}
+
+// returns span of source code report is about: the span of the expression
+// being inferred when it was made or, when there is none, the first locatable
+// name or term involved in it. Types involved are never used; the names in
+// them are where the types were declared
+func (report errorReport[N]) span() sourceSpan {
+	if report.Span.line != 0 {
+		return report.Span
+	}
+	for _, name := range report.Names {
+		if span := spanOf([]N{name.Name}); span.line != 0 {
+			return span
+		}
+	}
+	for _, term := range report.TermsInvolved {
+		e, _ := term.GetExpressionAndType()
+		if span := spanOf(e.Collect()); span.line != 0 {
+			return span
+		}
+	}
+	return sourceSpan{}
+}
+
+// returns location of report; line is 0 when its source code is unknown
+func (report errorReport[N]) GetLocation() (path string, line, char int) {
+	span := report.span()
+	return span.path, span.line, span.char
+}
+
+// returns number of characters of source code report is located at
+func (report errorReport[N]) GetRange() int {
+	if span := report.span(); span.size > 0 {
+		return span.size
+	}
+	return 1
+}
+
+// returns "t <problem> k0 (it has kind k1)" for kinds k0 and k1 and type t
+// involved in report
+func (report errorReport[N]) kinds(problem string) string {
+	out := report.subject()
+	if len(report.KindsInvolved) == 2 {
+		out = out + " " + problem + " " + report.KindsInvolved[0].String() +
+			" (it has kind " + report.KindsInvolved[1].String() + ")"
+	}
+	return out
+}
+
+// returns first name, term, or type involved in report as a string
+func (report errorReport[N]) subject() string {
+	if len(report.Names) != 0 {
+		return report.Names[0].String()
+	}
+	if len(report.TermsInvolved) != 0 {
+		e, _ := report.TermsInvolved[0].GetExpressionAndType()
+		return e.String()
+	}
+	if len(report.TypesInvolved) != 0 {
+		return report.types()[0]
+	}
+	return "name"
+}
+
+// returns types involved in report as they are printed in its message; see
+// types.PrettyAll
+func (report errorReport[N]) types() []string {
+	return types.PrettyAll(report.TypesInvolved...)
+}
+
+// returns terms involved in report as a comma-separated string
+func (report errorReport[N]) terms() string {
+	terms := make([]string, len(report.TermsInvolved))
+	for i, term := range report.TermsInvolved {
+		e, _ := term.GetExpressionAndType()
+		terms[i] = e.String()
+	}
+	return strings.Join(terms, ", ")
+}
+
+// returns " to have type <t>" for the type t of the last term involved in
+// report
+func (report errorReport[N]) annotated() string {
+	if len(report.TermsInvolved) == 0 {
+		return ""
+	}
+	_, t := report.TermsInvolved[len(report.TermsInvolved)-1].GetExpressionAndType()
+	return " to have type " + types.Pretty(t)
+}
+
+// returns "hole ?h has type t" for the hole ?h: t involved first in report,
+// followed by "; it can be filled by x1, .., xN" for the names x1, .., xN w/ the
+// terms involved after it
+func (report errorReport[N]) hole() string {
+	if len(report.TermsInvolved) == 0 {
+		return "hole has unknown type"
+	}
+	e, t := report.TermsInvolved[0].GetExpressionAndType()
+	out := "hole " + e.String() + " has type " + types.Pretty(t)
+	if len(report.TermsInvolved) == 1 {
+		return out
+	}
+	fits := make([]string, len(report.TermsInvolved)-1)
+	for i, term := range report.TermsInvolved[1:] {
+		x, _ := term.GetExpressionAndType()
+		fits[i] = x.String()
+	}
+	return out + "; it can be filled by " + strings.Join(fits, ", ")
+}
+
+// returns "<a> with <b>" for the first two types involved in report
+func (report errorReport[N]) unified(otherwise string) string {
+	if len(report.TypesInvolved) < 2 {
+		return otherwise
+	}
+	ts := report.types()
+	return ts[0] + " with " + ts[1]
+}
+
+// returns human-readable description of report
+func (report errorReport[N]) Message() string {
+	switch report.Status {
+	case ConstantMismatch:
+		return "cannot unify " + report.unified("types")
+	case KindConstantMismatch:
+		return "cannot unify " + report.unified("kinds")
+	case ParamLengthMismatch:
+		return "cannot unify " + report.unified("types") + ": different number of type parameters"
+	case IndexLengthMismatch:
+		return "cannot unify " + report.unified("types") + ": different number of indexes"
+	case MemsLengthMismatch:
+		return "cannot unify " + report.unified("kinds") + ": different number of members"
+	case OccursCheckFailed:
+		if len(report.TypesInvolved) < 2 {
+			return "cannot construct infinite type"
+		}
+		ts := report.types()
+		return "cannot construct infinite type " + ts[0] + " = " + ts[1]
+	case NameNotInContext, UndefinedFunction:
+		return report.subject() + " is not defined"
+	case RecArgsLengthMismatch:
+		return "rec expression does not define each name it binds exactly once"
+	case TypeRedef:
+		return "type " + report.subject() + " is already defined"
+	case ConstructorRedef:
+		return "constructor " + report.subject() + " is already defined"
+	case TypeNotDefined, UndefinedType:
+		return "type " + report.subject() + " is not defined"
+	case UndefinedConstructor:
+		return "constructor " + report.subject() + " is not defined"
+	case AmbiguousFunction:
+		return report.subject() + " has more than one definition"
+	case IllegalShadow:
+		return report.subject() + " is already defined"
+	case UnsupportedExpression:
+		return "cannot infer type of " + report.subject()
+	case ModuleRedef:
+		return "module " + report.subject() + " is already defined"
+	case UndefinedModule:
+		return "module " + report.subject() + " is not defined"
+	case ConstructorArityMismatch:
+		return "constructor pattern " + report.subject() + " has the wrong number of arguments"
+	case IllegalPattern:
+		return report.subject() + " cannot be used as a pattern"
+	case UndefinedClass:
+		return "class " + report.subject() + " is not defined"
+	case ClassRedef:
+		return "class " + report.subject() + " is already defined"
+	case InstanceRedef:
+		return "instance " + report.subject() + " overlaps with an existing instance"
+	case NoInstance:
+		return "no instance for " + report.predicate()
+	case AmbiguousConstraint:
+		return "ambiguous constraint " + report.predicate()
+	case KindMismatch:
+		return report.kinds("does not have kind")
+	case InfiniteKind:
+		return report.kinds("cannot have infinite kind")
+	case SkolemEscape:
+		return report.subject() + " is not polymorphic enough" + report.annotated()
+	case MissingLabel:
+		return "cannot unify " + report.unified("records") + ": different fields"
+	case DuplicateLabel:
+		if len(report.TypesInvolved) < 2 {
+			return "record has more than one field labeled " + report.subject()
+		}
+		return "cannot unify " + report.unified("records") + ": duplicate field"
+	case NonExhaustiveMatch:
+		return "select does not match all values; unmatched values include " + report.terms()
+	case RedundantCase:
+		return "case " + report.subject() + " is never reached"
+	case TypedHole:
+		return report.hole()
+	case UnsolvedIndexConstraint:
+		return "cannot solve index constraint " + strings.Replace(report.terms(), ", ", " = ", 1)
+	}
+	return report.Status.String()
+}
+
+// "<message> (during <rule>)"
+func (report errorReport[N]) String() string {
+	return report.Message() + " (during " + report.During + ")"
 }
@@ -0,0 +1,203 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// name w/ a location in source code
+type locatedName struct {
+	name       string
+	line, char int
+}
+
+func (name locatedName) GetName() string { return name.name }
+
+func (name locatedName) GetLocation() (path string, line, char int) {
+	return "test", name.line, name.char
+}
+
+func TestReportMessage(t *testing.T) {
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	List := types.MakeConst(nameable.MakeTestable("List"))
+	a := types.Var(nameable.MakeTestable("a"))
+	listA := types.Apply[nameable.Testable](List, a)
+	x := expr.Const[nameable.Testable]{Name: nameable.MakeTestable("x")}
+
+	tests := []struct {
+		description string
+		report      func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable]
+		expect      string
+	}{
+		{
+			`Unify(Int, List a)`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				cxt.appendReport(makeReport[nameable.Testable]("App", cxt.Unify(Int, listA)))
+				return cxt.GetReports()[0]
+			},
+			"cannot unify Int with List a",
+		},
+		{
+			`Unify(a, List a)`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				cxt.appendReport(makeReport[nameable.Testable]("App", cxt.Unify(a, listA)))
+				return cxt.GetReports()[0]
+			},
+			"cannot construct infinite type a = List a",
+		},
+		{
+			`Unify(List a, List Int Int)`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				stat := cxt.Unify(listA, types.Apply[nameable.Testable](List, Int, Int))
+				cxt.appendReport(makeReport[nameable.Testable]("App", stat))
+				return cxt.GetReports()[0]
+			},
+			"cannot unify List a with List Int Int: different number of type parameters",
+		},
+		{
+			`Unify(Int, $0 -> $0)`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				v := cxt.TypeContext.NewVar()
+				cxt.appendReport(makeReport[nameable.Testable]("App", cxt.Unify(Int, cxt.TypeContext.Function(v, v))))
+				return cxt.GetReports()[0]
+			},
+			"cannot unify Int with a -> a",
+		},
+		{
+			`Unify(skolem a, skolem a)`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				stat := cxt.Unify(cxt.newSkolem(a), cxt.newSkolem(a))
+				cxt.appendReport(makeReport[nameable.Testable]("App", stat))
+				return cxt.GetReports()[0]
+			},
+			"cannot unify a with a1",
+		},
+		{
+			`Eq (skolem a) has no instance`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				p := types.Pred[nameable.Testable](nameable.MakeTestable("Eq"), cxt.newSkolem(a))
+				return makePredicateReport("Gen", NoInstance, p)
+			},
+			"no instance for Eq a",
+		},
+		{
+			`x not in context`,
+			func(cxt *Context[nameable.Testable]) errorReport[nameable.Testable] {
+				return makeNameReport("Var", NameNotInContext, x)
+			},
+			"x is not defined",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := NewTestableContext()
+		actual := test.report(cxt).Message()
+		if actual != test.expect {
+			t.Fatal(testutil.Testing("message", test.description).FailMessage(test.expect, actual, i))
+		}
+	}
+}
+
+func TestReportLocation(t *testing.T) {
+	makeName := func(s string) locatedName { return locatedName{s, 0, 0} }
+	cxt := NewContext[locatedName]()
+	cxt.TypeContext = cxt.TypeContext.SetNameMaker(makeName)
+	cxt.ExprContext = cxt.ExprContext.SetNameMaker(makeName)
+	// Int and f are declared on lines 3 and 4; reports are never located there
+	Int := types.MakeConst(locatedName{"Int", 3, 7})
+	cxt.Add(expr.Const[locatedName]{Name: locatedName{"0", 3, 1}}, Int)
+	f := expr.Const[locatedName]{Name: locatedName{"f", 4, 1}}
+	cxt.Add(f, cxt.TypeContext.Function(Int, Int))
+
+	zero := func(char int) expr.Const[locatedName] {
+		return expr.Const[locatedName]{Name: locatedName{"0", 1, char}}
+	}
+	x := expr.Const[locatedName]{Name: locatedName{"x", 0, 0}}
+
+	tests := []struct {
+		description      string
+		input            expr.Expression[locatedName]
+		line, char, rnge int
+	}{
+		// `0 0` at 1:5
+		{`0 0`, expr.Apply[locatedName](zero(5), zero(7)), 1, 5, 3},
+		// `f (0 0)` at 1:1; the report is about `0 0`
+		{`f (0 0)`, expr.Apply[locatedName](f, expr.Apply[locatedName](zero(4), zero(6))), 1, 4, 3},
+		// `x` not in context, and neither x nor any expression has a location
+		{`x`, x, 0, 0, 1},
+	}
+
+	for i, test := range tests {
+		_, reports := cxt.Infer(test.input)
+		if len(reports) != i+1 {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(i+1, reports, i))
+		}
+		report := reports[i]
+		_, line, char := report.GetLocation()
+		if line != test.line || char != test.char {
+			t.Fatal(testutil.Testing("location", test.description).FailMessage([]int{test.line, test.char}, []int{line, char}, i))
+		}
+		if rnge := report.GetRange(); rnge != test.rnge {
+			t.Fatal(testutil.Testing("range", test.description).FailMessage(test.rnge, rnge, i))
+		}
+	}
+}
+
+func TestWarningLocation(t *testing.T) {
+	makeName := func(s string) locatedName { return locatedName{s, 0, 0} }
+	cxt := NewContext[locatedName]()
+	cxt.TypeContext = cxt.TypeContext.SetNameMaker(makeName)
+	cxt.ExprContext = cxt.ExprContext.SetNameMaker(makeName)
+	// Maybe a = Just a | Nothing, declared on line 3
+	maybe, a := makeName("Maybe"), types.Var(makeName("a"))
+	cxt.AddType(maybe, types.Apply[locatedName](types.MakeConst(maybe), a))
+	member := bridge.Judgment[locatedName, expr.Expression[locatedName]](expr.Var(makeName("x")), a)
+	cxt.AddConstructorFor(maybe, bridge.MakeData(expr.Const[locatedName]{Name: locatedName{"Just", 3, 11}}, member))
+	cxt.AddConstructorFor(maybe, bridge.MakeData(expr.Const[locatedName]{Name: locatedName{"Nothing", 3, 20}}))
+	cxt.Add(expr.Const[locatedName]{Name: locatedName{"0", 3, 1}}, types.MakeConst(makeName("Int")))
+
+	at := func(name string, char int) expr.Const[locatedName] {
+		return expr.Const[locatedName]{Name: locatedName{name, 1, char}}
+	}
+	// `select Just 0 when Nothing -> 0` on line 1; `Just` of the case is at 1:8
+	input := expr.Select[locatedName](expr.Apply[locatedName](at("Just", 8), at("0", 13)),
+		expr.Bind[locatedName]().InCase(at("Nothing", 20), at("0", 31)),
+	)
+	if _, reports := cxt.Infer(input); len(reports) != 0 {
+		t.Fatal(testutil.Testing("reports").FailMessage(nil, reports, 0))
+	}
+	warnings := cxt.GetWarnings()
+	if len(warnings) != 1 || !warnings[0].Status.Is(NonExhaustiveMatch) {
+		t.Fatal(testutil.Testing("warnings").FailMessage(NonExhaustiveMatch, warnings, 0))
+	}
+	_, line, char := warnings[0].GetLocation()
+	if line != 1 || char != 8 {
+		t.Fatal(testutil.Testing("location").FailMessage([]int{1, 8}, []int{line, char}, 0))
+	}
+	if rnge := warnings[0].GetRange(); rnge != 24 {
+		t.Fatal(testutil.Testing("range").FailMessage(24, rnge, 0))
+	}
+}
+
+func TestReportSubtype(t *testing.T) {
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	tests := []struct {
+		report errorReport[nameable.Testable]
+		expect string
+	}{
+		{makeTypeReport[nameable.Testable]("App", ConstantMismatch, Int, Int), "Type"},
+		{makeTypeReport[nameable.Testable]("Check", KindMismatch, Int), "Kind"},
+		{makeTypeReport[nameable.Testable]("Check", InfiniteKind, Int), "Kind"},
+	}
+
+	for i, test := range tests {
+		if actual := test.report.GetSubtype(); actual != test.expect {
+			t.Fatal(testutil.Testing("subtype", test.report.Status.String()).FailMessage(test.expect, actual, i))
+		}
+	}
+}
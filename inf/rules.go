@@ -17,6 +17,13 @@ func (cxt *Context[N]) varBody(x bridge.JudgmentAsExpression[N, expr.Const[N]])
 
 	tmp, xConst := x.TypeAndExpr()
 
+	if q, ok := tmp.(types.Qualified[N]); ok {
+		// predicates of `q` are still wanted, but the dictionaries proving them
+		// are not applied to `x`; see Overloaded
+		t, _, _ = cxt.instQualified(q)
+		return Conclude[N](xConst, t)
+	}
+
 	// grab polytype
 	sigma, ok := tmp.(types.Polytype[N])
 	if !ok { // still technically a polytype, just one w/ no zero binders, so make that explicit
@@ -26,15 +33,19 @@ func (cxt *Context[N]) varBody(x bridge.JudgmentAsExpression[N, expr.Const[N]])
 	}
 
 	// replace all bound (including kind-) variables with free variables
-	t = cxt.Inst(sigma)
+	t, args := cxt.instantiate(sigma)
 	// return judgment `x: t`
-	return Conclude[N](xConst, t)
+	c := Conclude[N](xConst, t)
+	c.core = coreInstance[N](xConst.Name, sigma, args, nil, t)
+	return c
 }
 
 // This is just the "Var" rule but for builtin primitives
 func (cxt *Context[N]) Primitive(x bridge.Prim[N]) Conclusion[N, bridge.Prim[N], types.Monotyped[N]] {
 	t := x.Val.GetType()
-	return Conclude[N](x, t)
+	c := Conclude[N](x, t)
+	c.core = CoreLiteral[N]{x, t}
+	return c
 }
 
 // [Var] rule:
@@ -53,6 +64,24 @@ func (cxt *Context[N]) Var(x expr.Const[N]) Conclusion[N, expr.Const[N], types.M
 	return cxt.varBody(xJudge)
 }
 
+// [Var] rule for names w/ qualified types. Each predicate of the instantiated
+// type is wanted, and `x` is applied to placeholders for the dictionaries that
+// prove them (see Context.resolveWanted):
+//
+//			x: ∀a.P => t0 ∈ 𝚪    t = Inst(t0)    d = evidence(Inst(P))
+//	   ---------------------------------------------------------- [Var]
+//	                        𝚪 ⊢ x d: t
+func (cxt *Context[N]) Overloaded(x expr.Const[N], q types.Qualified[N]) Conclusion[N, expr.Expression[N], types.Monotyped[N]] {
+	t, args, placeholders := cxt.instQualified(q)
+	var e expr.Expression[N] = x
+	for _, placeholder := range placeholders {
+		e = expr.Apply(e, placeholder)
+	}
+	c := Conclude[N](e, t)
+	c.core = coreInstance(x.Name, types.Type[N](q), args, placeholders, t)
+	return c
+}
+
 // [App] rule:
 //
 //			𝚪 ⊢ e0: t0    𝚪 ⊢ e1: t1    t2 = newvar    t0 = t1 -> t2
@@ -92,6 +121,57 @@ func (cxt *Context[N]) App(j0, j1 TypeJudgment[N]) Conclusion[N, expr.Applicatio
 	return Conclude[N](appliedExpression, cxt.GetSub(t2))
 }
 
+// [Annot] rule:
+//
+//	𝚪 ⊢ e ⇐ σ    t = Inst(σ)    d = evidence(Inst(P))
+//	------------------------------------------------- [Annot]
+//	               𝚪 ⊢ (e: σ) d: t
+//
+// `e` is checked against `σ`, so `σ` may have polytypes nested w/in it (see
+// types.Nested), e.g.,
+//
+//	(λf . pair (f 0) (f true)): (forall a . a -> a) -> Pair Int Bool
+//
+// the judgment `e: σ` is kept in the conclusion's expression. When `σ` is
+// qualified by predicates P, `e` is abstracted over the dictionaries for P
+// (see checkSigma), and--like a name w/ a qualified type (see Overloaded)--the
+// judgment is applied to placeholders for the dictionaries that prove the
+// instantiated predicates
+func (cxt *Context[N]) Annot(e expr.Expression[N], sigma types.Type[N]) Conclusion[N, expr.Expression[N], types.Monotyped[N]] {
+	if stat := cxt.checkKind("Annot", make(map[string]Kind), sigma); stat.NotOk() {
+		return CannotConclude[N, expr.Expression[N], types.Monotyped[N]](stat)
+	}
+
+	c := cxt.checkSigma(e, sigma)
+	if c.NotOk() {
+		return CannotConclude[N, expr.Expression[N], types.Monotyped[N]](c.Status)
+	}
+
+	var m types.Monotyped[N]
+	var args []types.Monotyped[N]
+	var placeholders []expr.Expression[N]
+	switch s := sigma.(type) {
+	case types.Qualified[N]:
+		m, args, placeholders = cxt.instQualified(s)
+	case types.Polytype[N]:
+		m, args = cxt.instantiate(s)
+	default:
+		m, args = cxt.instantiate(types.Forall[N]().Bind(sigma.(types.DependentTyped[N])))
+	}
+
+	t := cxt.GetSub(m)
+	var annotated expr.Expression[N] = bridge.Judgment(c.judgment.GetExpression(), sigma)
+	for _, placeholder := range placeholders {
+		annotated = expr.Apply(annotated, placeholder)
+	}
+	out := Conclude[N](annotated, t)
+	out.core = CoreTypeApp[N]{c.core, args, placeholders, t}
+	if len(args) == 0 && len(placeholders) == 0 {
+		out.core = c.core
+	}
+	return out
+}
+
 // [Abs] rule:
 //
 //	t0 = newvar    𝚪, param: t0 ⊢ e: t1
@@ -103,6 +183,11 @@ func (cxt *Context[N]) App(j0, j1 TypeJudgment[N]) Conclusion[N, expr.Applicatio
 //
 // curry-howard: conditional intro
 func (cxt *Context[N]) Abs(param N) func(TypeJudgment[N]) Conclusion[N, expr.Function[N], types.Monotyped[N]] {
+	return cxt.abs(param, cxt.ExprContext.NewVar)
+}
+
+// like Abs, but `param` is converted to the variable `binder` returns
+func (cxt *Context[N]) abs(param N, binder func() expr.Variable[N]) func(TypeJudgment[N]) Conclusion[N, expr.Function[N], types.Monotyped[N]] {
 	// first, add context (this is the first premise)
 	paramConst := expr.Const[N]{Name: param}
 	t0 := cxt.TypeContext.NewVar()
@@ -119,7 +204,7 @@ func (cxt *Context[N]) Abs(param N) func(TypeJudgment[N]) Conclusion[N, expr.Fun
 		t1 := tmp1.(types.Monotyped[N])
 
 		// create function body by converting param-name to param-var in e
-		v := cxt.ExprContext.NewVar()
+		v := binder()
 		e = e.BodyAbstract(v, paramConst)
 
 		// actual function creation, finish abstraction of `e`
@@ -137,13 +222,17 @@ type letAssumptionDischarge[N nameable.Nameable] func(TypeJudgment[N]) Conclusio
 
 // [Let] rule:
 //
-//	𝚪 ⊢ e0: t0    𝚪, name: Gen(t) ⊢ e1: t1
-//	-------------------------------------- [Let]
-//	     𝚪 ⊢ let name = e0 in e1: t1
+//	𝚪 ⊢ e0: t0    𝚪, name: Gen𝚪(t0) ⊢ e1: t1
+//	---------------------------------------- [Let]
+//	      𝚪 ⊢ let name = e0 in e1: t1
 //
 // notice that the second param adds context and the third premise no longer
 // has that context
 //
+// Gen𝚪 only binds variables that are not free in 𝚪 (see Generalize). When `e0`
+// is inferred between calls to TypeContext.EnterLevel and
+// TypeContext.LeaveLevel, most variables can be generalized w/o searching 𝚪
+//
 // This rule allows for a kind of polymorphism:
 //
 //	𝚪 = {0: Int, (λy.y): a -> a}:
@@ -158,20 +247,33 @@ type letAssumptionDischarge[N nameable.Nameable] func(TypeJudgment[N]) Conclusio
 //		         1 ------------------------------------------------------ [Let]
 //		                          let x = (λy.y) in x 0: Int
 func (cxt *Context[N]) Let(name N, j0 TypeJudgment[N]) letAssumptionDischarge[N] {
+	discharge, _ := cxt.let(name, j0)
+	return discharge
+}
+
+// [Let] rule, but also returns the class constraints `name`'s type is
+// qualified by
+func (cxt *Context[N]) let(name N, j0 TypeJudgment[N]) (letAssumptionDischarge[N], []constraint[N]) {
 	nameConst := expr.Const[N]{Name: name}
 	e0, tmp0 := j0.GetExpressionAndType()
-	t0 := tmp0.(types.Monotyped[N])
-	generalized_t0 := cxt.Gen(t0)
-	cxt.Shadow(nameConst, generalized_t0)
+	t0 := cxt.GetSub(tmp0.(types.Monotyped[N]))
+	generalized_t0 := cxt.Generalize(t0)
+	// class constraints on generalized variables qualify the generalized type
+	context, stat := cxt.resolveWanted([]types.Polytype[N]{generalized_t0}, false)
+	e0 = cxt.abstractEvidence(context, e0)
+	cxt.Shadow(nameConst, qualify(generalized_t0, context))
 
 	return func(j1 TypeJudgment[N]) Conclusion[N, expr.NameContext[N], types.Monotyped[N]] {
 		cxt.Remove(nameConst)
+		if stat.NotOk() {
+			return CannotConclude[N, expr.NameContext[N], types.Monotyped[N]](stat)
+		}
 
 		e1, t1 := j1.GetExpressionAndType()
 		mono := t1.(types.Monotyped[N])
 		let := expr.Let(nameConst, e0, e1)
 		return Conclude[N](let, mono)
-	}
+	}, context
 }
 
 // [Rec] rule:
@@ -181,15 +283,29 @@ func (cxt *Context[N]) Let(name N, j0 TypeJudgment[N]) letAssumptionDischarge[N]
 //	    𝚪 ⊢ rec v1 = e1 and ... and vN = eN in e0: t0
 //	where
 //	    𝚪ʹ = v1: t1, ..., vN: tN
-//	    𝚪ʹʹ = v1: Gen(t1), ..., vN: Gen(tN)
+//	    𝚪ʹʹ = v1: Gen𝚪(t1), ..., vN: Gen𝚪(tN)
+//
+// like [Let], generalization is most efficient when the first stage is called
+// and e1, ..., eN are inferred between calls to TypeContext.EnterLevel and
+// TypeContext.LeaveLevel
 func (cxt *Context[N]) Rec(names []N) func(js []TypeJudgment[N]) func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+	judge := cxt.rec(names)
+	return func(js []TypeJudgment[N]) func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+		discharge, _ := judge(js)
+		return discharge
+	}
+}
+
+// [Rec] rule, but the second stage also returns the class constraints the
+// types of the names are qualified by
+func (cxt *Context[N]) rec(names []N) func(js []TypeJudgment[N]) (func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]], []constraint[N]) {
 	// non-zero length slice of names
 	if len(names) < 1 {
 		cxt.appendReport(makeReport[N]("Rec", RecArgsLengthMismatch))
-		return func(js []TypeJudgment[N]) func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+		return func(js []TypeJudgment[N]) (func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]], []constraint[N]) {
 			return func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
 				return CannotConclude[N, expr.RecIn[N], types.Monotyped[N]](RecArgsLengthMismatch)
-			}
+			}, nil
 		}
 	}
 
@@ -209,7 +325,7 @@ func (cxt *Context[N]) Rec(names []N) func(js []TypeJudgment[N]) func(tj TypeJud
 		}
 	}
 
-	return func(js []TypeJudgment[N]) func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+	return func(js []TypeJudgment[N]) (func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]], []constraint[N]) {
 		removeNames() // discharge 𝚪ʹ
 
 		if len(js) != len(names) {
@@ -217,16 +333,48 @@ func (cxt *Context[N]) Rec(names []N) func(js []TypeJudgment[N]) func(tj TypeJud
 			cxt.appendReport(makeReport("Rec", RecArgsLengthMismatch, js...))
 			return func(TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
 				return CannotConclude[N, expr.RecIn[N], types.Monotyped[N]](RecArgsLengthMismatch)
+			}, nil
+		}
+
+		// each assumption vI: tI from 𝚪ʹ must agree w/ the judgment eI: tI
+		for i := range defs {
+			_, t := js[i].GetExpressionAndType()
+			stat := cxt.Unify(vs[i], t.(types.Monotyped[N]))
+			if stat.NotOk() {
+				cxt.appendReport(makeReport("Rec", stat, js[i]))
+				return func(TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+					return CannotConclude[N, expr.RecIn[N], types.Monotyped[N]](stat)
+				}, nil
 			}
 		}
 
+		sigmas := make([]types.Polytype[N], len(defs))
+		for i := range defs {
+			sigmas[i] = cxt.Generalize(cxt.GetSub(vs[i])) // generalize
+		}
+
+		// the whole group shares one class context
+		context, stat := cxt.resolveWanted(sigmas, false)
+		if stat.NotOk() {
+			return func(TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
+				return CannotConclude[N, expr.RecIn[N], types.Monotyped[N]](stat)
+			}, nil
+		}
+		dictionaries := make([]expr.Expression[N], len(context))
+		for i, c := range context {
+			dictionaries[i] = c.evidence
+		}
+
 		// add 𝚪ʹʹ to context
 		for i, def := range defs {
-			e, t := js[i].GetExpressionAndType()
-			m := t.(types.Monotyped[N])
-			defs[i] = def.Instantiate(e)
-			sigma := cxt.Gen(m) // generalize
-			cxt.Shadow(def.GetName(), sigma)
+			e, _ := js[i].GetExpressionAndType()
+			// recursive uses pass along the group's dictionaries
+			for j := 0; len(context) != 0 && j < len(defs); j++ {
+				name := defs[j].GetName()
+				e = cxt.substituteConst(e, name, expr.Apply[N](name, dictionaries[0], dictionaries[1:]...))
+			}
+			defs[i] = def.Instantiate(cxt.abstractEvidence(context, e))
+			cxt.Shadow(def.GetName(), qualify(sigmas[i], context))
 		}
 
 		return func(tj TypeJudgment[N]) Conclusion[N, expr.RecIn[N], types.Monotyped[N]] {
@@ -236,7 +384,7 @@ func (cxt *Context[N]) Rec(names []N) func(js []TypeJudgment[N]) func(tj TypeJud
 			mono := t0.(types.Monotyped[N])
 			rec := expr.Rec(defs...)(e0)
 			return Conclude[N](rec, mono)
-		}
+		}, context
 	}
 }
 
@@ -264,6 +412,11 @@ func (cxt *Context[N]) exportTypes(typeNames []N, constructorNames [][]N) *Expor
 			cxt.appendReport(makeNameReport("Export Type", UndefinedType, expr.MakeConst(typeName)))
 			return nil
 		}
+		// add type w/o constructors, then add each exported constructor
+		out.consTable.Add(typeName, cxt.makeConsJudge(cj.forType))
+		if kind, found := cxt.GetKind(typeName); found {
+			out.kinds.Add(typeName, kind)
+		}
 		if !out.exportConstructors(cxt, typeName, cj, constructorNames[i]) {
 			return nil
 		}
@@ -290,11 +443,43 @@ func (ecxt *ExportableContext[N]) exportNames(cxt *Context[N], names []N) *Expor
 	return ecxt
 }
 
+// exports every class declared in `cxt` along w/ its methods, and every
+// instance declared in `cxt`. Instances cannot be hidden, and the classes that
+// qualify the types of exported names must be known wherever they are
+// imported
+func (ecxt *ExportableContext[N]) exportClasses(cxt *Context[N]) *ExportableContext[N] {
+	ok := true
+	cxt.classes.ForEach(func(_ nameable.Nameable, c class[N]) bool {
+		ecxt.classes.Add(c.name, c)
+		for _, method := range c.methods {
+			if _, exported := ecxt.syms.Get(method.Name); exported {
+				continue
+			}
+			if ecxt.exportNames(cxt, []N{method.Name}) == nil {
+				ok = false
+			}
+		}
+		return ok
+	})
+	if !ok {
+		return nil
+	}
+	cxt.instances.ForEach(func(class nameable.Nameable, instances []instance[N]) bool {
+		ecxt.instances.Add(class, instances)
+		return true
+	})
+	return ecxt
+}
+
 // [Export] rule:
 //
 //	module M (x0, .., xN)    𝚪 ⊢ x0: σ0   ...   𝚪 ⊢ xN: σN
 //	------------------------------------------------------ [Export]
 //	              M = { x0: σ0, .., xN: σN }
+//
+// every class declared in the returned context is exported w/ its methods,
+// which must not be among `names`, and every instance declared in it is
+// exported
 func Export[N nameable.Nameable](name N, nameMaker func(string) N, names, typeNames []N, constructorNames [][]N) (*Context[N], func() *ExportableContext[N]) {
 	// precondition
 	if len(typeNames) != len(constructorNames) {
@@ -323,7 +508,10 @@ func Export[N nameable.Nameable](name N, nameMaker func(string) N, names, typeNa
 		}
 		ecxt.name = name
 
-		return ecxt.exportNames(cxt, names)
+		if ecxt = ecxt.exportNames(cxt, names); ecxt == nil {
+			return nil
+		}
+		return ecxt.exportClasses(cxt)
 	}
 }
 
@@ -332,8 +520,43 @@ func Export[N nameable.Nameable](name N, nameMaker func(string) N, names, typeNa
 //	M = 𝚪∗    𝚪, 𝚪∗ ⊢ e: t
 //	---------------------- [Import]
 //	 𝚪 ⊢ import M in e: t
-func (cxt *Context[N]) Import(qualification QualificationType, moduleName N, as N) {
-	// export := lookup(moduleName)
-	// cxt.import(as, export)
-	// TODO
+//
+// adds all names, types, constructors, classes, and instances exported by
+// module `moduleName` to the context. How the imported names, types, and
+// constructors are referred to depends on `qualification`:
+//
+//	NotQualified:   x
+//	NameQualified:  as.x
+//	FullyQualified: moduleName.x
+//
+// and the imported types refer to each other by those names, too. Importing
+// is all or nothing: when anything cannot be imported, each problem is
+// reported and the context is left as it was
+func (cxt *Context[N]) Import(qualification QualificationType, moduleName N, as N) Status {
+	ecxt, found := cxt.modules.Lookup(moduleName)
+	if !found {
+		cxt.appendReport(makeNameReport("Import", UndefinedModule, expr.MakeConst(moduleName)))
+		return UndefinedModule
+	}
+
+	snap := cxt.Snapshot()
+	qualify := cxt.qualifier(qualification, moduleName, as)
+	r := makeRequalifier(ecxt, qualify)
+	stat := cxt.importTypes(ecxt, qualify, r)
+	if classStat := cxt.importClasses(ecxt, r); stat.IsOk() {
+		stat = classStat
+	}
+	if nameStat := cxt.importNames(ecxt, qualify, r); stat.IsOk() {
+		stat = nameStat
+	}
+
+	if stat.IsOk() {
+		cxt.Commit(snap)
+		return stat
+	}
+	// keep the reports explaining why the import failed
+	reports := append([]errorReport[N]{}, cxt.reports[snap.reports:]...)
+	cxt.Rollback(snap)
+	cxt.reports = append(cxt.reports, reports...)
+	return stat
 }
@@ -0,0 +1,108 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestRollback(t *testing.T) {
+	a, b := types.Var(nameable.MakeTestable("a")), types.Var(nameable.MakeTestable("b"))
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	Bool := types.MakeConst(nameable.MakeTestable("Bool"))
+	pair := types.MakeConst(nameable.MakeTestable("Pair"))
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+	x := expr.Const[nameable.Testable]{Name: "x"}
+	listName := nameable.MakeTestable("List")
+	nil_ := expr.Const[nameable.Testable]{Name: "Nil"}
+
+	cxt := makeDriverTestContext()
+	typeVars, exprVars := cxt.TypeContext.GetVarCounter(), cxt.ExprContext.GetVarCounter()
+	snap := cxt.Snapshot()
+
+	// (Pair a Int) = (Pair Bool Bool) fails after a = Bool
+	stat := cxt.Unify(types.Apply[nameable.Testable](pair, a, Int), types.Apply[nameable.Testable](pair, Bool, Bool))
+	if !stat.Is(ConstantMismatch) {
+		t.Fatal(testutil.Testing("unify").FailMessage(ConstantMismatch, stat, 0))
+	}
+	cxt.Unify(b, Int)
+	cxt.TypeContext.NewVar()
+	cxt.ExprContext.NewVar()
+	cxt.Add(x, Int)
+	cxt.Shadow(zero, Bool)
+	cxt.Add(zero, Int) // fails, adding a report
+	cxt.AddType(listName, types.Apply[nameable.Testable](types.MakeConst(listName), a))
+	cxt.AddConstructorFor("Maybe", bridge.MakeData(nil_))
+
+	cxt.Rollback(snap)
+
+	if out := cxt.Find(a); !out.Equals(a) {
+		t.Fatal(testutil.Testing("type substitution").FailMessage(a, out, 1))
+	}
+	if out := cxt.Find(b); !out.Equals(b) {
+		t.Fatal(testutil.Testing("type substitution").FailMessage(b, out, 2))
+	}
+	if n := cxt.TypeContext.GetVarCounter(); n != typeVars {
+		t.Fatal(testutil.Testing("type variable counter").FailMessage(typeVars, n, 3))
+	}
+	if n := cxt.ExprContext.GetVarCounter(); n != exprVars {
+		t.Fatal(testutil.Testing("expression variable counter").FailMessage(exprVars, n, 4))
+	}
+	if _, found := cxt.Get(x); found {
+		t.Fatal(testutil.Testing("added symbol").FailMessage(false, found, 5))
+	}
+	if j, _ := cxt.Get(zero); j.String() != "(0: Int)" {
+		t.Fatal(testutil.Testing("shadowed symbol").FailMessage("(0: Int)", j, 6))
+	}
+	if cxt.HasErrors() {
+		t.Fatal(testutil.Testing("reports").FailMessage(nil, cxt.GetReports(), 7))
+	}
+	if _, found := cxt.GetConstructorsForType(listName); found {
+		t.Fatal(testutil.Testing("type").FailMessage(false, found, 8))
+	}
+	if _, found := cxt.findConstructor(nil_.Name); found {
+		t.Fatal(testutil.Testing("constructor").FailMessage(false, found, 9))
+	}
+}
+
+func TestCommit(t *testing.T) {
+	a, b := types.Var(nameable.MakeTestable("a")), types.Var(nameable.MakeTestable("b"))
+	Int := types.MakeConst(nameable.MakeTestable("Int"))
+	zero := expr.Const[nameable.Testable]{Name: "0"}
+
+	// committed changes are kept
+	cxt := makeDriverTestContext()
+	snap := cxt.Snapshot()
+	cxt.Unify(a, Int)
+	cxt.Remove(zero)
+	cxt.Commit(snap)
+	if out := cxt.Find(a); !out.Equals(Int) {
+		t.Fatal(testutil.Testing("commit").FailMessage(Int, out, 0))
+	}
+	if _, found := cxt.Get(zero); found {
+		t.Fatal(testutil.Testing("commit").FailMessage(false, found, 1))
+	}
+
+	// changes committed to an inner snapshot are undone by rolling back an
+	// outer snapshot
+	cxt = makeDriverTestContext()
+	outer := cxt.Snapshot()
+	inner := cxt.Snapshot()
+	cxt.Unify(a, b)
+	cxt.Remove(zero)
+	cxt.Commit(inner)
+	cxt.Rollback(outer)
+	if out := cxt.Find(a); !out.Equals(a) {
+		t.Fatal(testutil.Testing("nested").FailMessage(a, out, 2))
+	}
+	if _, found := cxt.Get(zero); !found {
+		t.Fatal(testutil.Testing("nested").FailMessage(true, found, 3))
+	}
+	if len(cxt.trail) != 0 {
+		t.Fatal(testutil.Testing("trail").FailMessage(0, len(cxt.trail), 4))
+	}
+}
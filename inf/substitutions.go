@@ -0,0 +1,91 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: substitutions for type and kind variables
+//
+// Notes: -
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/table"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// substitutions for variables of type T. Variables substituted for one another
+// share an equivalence class; a class substituted for a non-variable is bound
+// to it
+type substitutions[T any] struct {
+	classes *table.UnionFind[T]
+	// returns name of `t` and true iff `t` is a variable
+	asVariable func(t T) (name nameable.Nameable, isVariable bool)
+}
+
+func newSubstitutions[T any](asVariable func(T) (nameable.Nameable, bool)) substitutions[T] {
+	return substitutions[T]{table.NewUnionFind[T](), asVariable}
+}
+
+func newTypeSubstitutions[N nameable.Nameable]() substitutions[types.Monotyped[N]] {
+	return newSubstitutions(func(t types.Monotyped[N]) (nameable.Nameable, bool) {
+		v, ok := t.(types.Variable[N])
+		return v.GetReferred(), ok
+	})
+}
+
+// substitutions for the expression variables found in types, e.g., indexes
+func newExprSubstitutions[N nameable.Nameable]() substitutions[expr.Referable[N]] {
+	return newSubstitutions(func(e expr.Referable[N]) (nameable.Nameable, bool) {
+		v, ok := e.(expr.Variable[N])
+		return v.GetReferred(), ok
+	})
+}
+
+// declares `v` = `t` for variable `v`. When `t` is `v`, the class of `v` is
+// bound to `v` itself, so the substitution is still found by Get
+func (subs substitutions[T]) Add(v T, t T) {
+	name, _ := subs.asVariable(v)
+	subs.classes.MakeSet(name, v)
+	tName, isVariable := subs.asVariable(t)
+	if isVariable && tName.GetName() == name.GetName() {
+		subs.classes.Bind(name, v)
+	} else if isVariable {
+		subs.classes.MakeSet(tName, t)
+		subs.classes.Union(name, tName)
+	} else {
+		subs.classes.Bind(name, t)
+	}
+}
+
+// If the variable named `name` has no substitution, then `_, false` is
+// returned, else the representative of its equivalence class is returned and
+// true is returned
+func (subs substitutions[T]) Get(name nameable.Nameable) (val T, found bool) {
+	if subs.classes.IsRepresentative(name) {
+		return val, false
+	}
+	return subs.classes.Find(name)
+}
+
+// replaces the value bound to the equivalence class of the variable named
+// `name` w/ an equivalent value `val`, e.g., a value that has had more
+// substitutions applied to it
+func (subs substitutions[T]) rebind(name nameable.Nameable, val T) {
+	subs.classes.Bind(name, val)
+}
+
+// starts recording changes to substitutions; see table.(*UnionFind).Mark
+func (subs substitutions[T]) mark() int {
+	return subs.classes.Mark()
+}
+
+// undoes changes made after `mark`; see table.(*UnionFind).Undo
+func (subs substitutions[T]) undo(mark int) {
+	subs.classes.Undo(mark)
+}
+
+// keeps changes made after `mark`; see table.(*UnionFind).Release
+func (subs substitutions[T]) release(mark int) {
+	subs.classes.Release(mark)
+}
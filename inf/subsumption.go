@@ -0,0 +1,254 @@
+// =============================================================================
+// Author-Date: Alex Peters - 2023
+//
+// Content: higher-rank polymorphism--checking expressions against polytypes,
+// subsumption, and unification of nested polytypes (see types.Nested)
+//
+// Notes: nested polytypes are never inferred; they come from annotations
+// (see Annot) and the types of names in the context. A skolem stands for any
+// type, so it must not be unified w/ a variable that is free in the context;
+// each check against a polytype ends w/ a check that its skolems did not
+// escape into the context
+// =============================================================================
+package inf
+
+import (
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+)
+
+// checks `e` against polytype `sigma`: `e` must have the type for every
+// choice of the variables `sigma` binds. The predicates P qualifying `sigma`
+// are assumed, each proven by a dictionary parameter d that `e` is abstracted
+// over (see abstractEvidence)
+//
+//	skolemize(σ) = P => ρ    𝚪, d: P ⊢ e ⇐ ρ    skolems(ρ) ∉ free(𝚪, σ)
+//	------------------------------------------------------------------
+//	                         𝚪 ⊢ λd . e ⇐ σ
+func (cxt *Context[N]) checkSigma(e expr.Expression[N], sigma types.Type[N]) exprConclusion[N] {
+	rho, skolems := cxt.skolemize(sigma)
+	givens := cxt.assumeContext(sigma, skolems)
+	frees := fun.FMap(rho.GetFreeVariables(), func(v types.Variable[N]) types.Monotyped[N] { return v })
+	before := cxt.wantedEvidence()
+	c := cxt.check(e, rho)
+	if c.NotOk() {
+		return c
+	}
+
+	if cxt.skolemsEscape(skolems, frees...) {
+		cxt.appendReport(makeReport[N]("Check", SkolemEscape, bridge.Judgment(e, sigma)))
+		return cannotInfer[N](SkolemEscape)
+	}
+	if len(givens) != 0 {
+		cxt.discharge(givens, before)
+		abstracted := cxt.abstractEvidence(givens, c.judgment.GetExpression())
+		core := c.core
+		c = concludeInferred(abstracted, c.judgment.GetType())
+		c.core = core
+	}
+	c.core = coreGeneralization(skolems, givens, c.core, sigma)
+	return c
+}
+
+// returns true iff any of `skolems` appear in the type of a name in the
+// context or in one of `ts` (after substitutions are applied)
+func (cxt *Context[N]) skolemsEscape(skolems []types.Monotyped[N], ts ...types.Monotyped[N]) bool {
+	if len(skolems) == 0 {
+		return false
+	}
+
+	isSkolem := make(map[string]bool, len(skolems))
+	for _, skolem := range skolems {
+		isSkolem[skolem.GetReferred().GetName()] = true
+	}
+	escapesIn := func(ty types.Type[N]) bool {
+		if m, ok := ty.(types.Monotyped[N]); ok {
+			ty = cxt.GetSub(m)
+		}
+		for _, name := range ty.Collect() {
+			if isSkolem[name.GetName()] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, t := range ts {
+		if escapesIn(t) {
+			return true
+		}
+	}
+
+	escapes := false
+	cxt.forEachSymbol(func(sym Symbol[N]) bool {
+		ty, _ := sym.Get().TypeAndExpr()
+		escapes = escapesIn(ty)
+		return !escapes
+	})
+	return escapes
+}
+
+// returns true iff `m` has a nested polytype
+func hasNested[N nameable.Nameable](m types.Monotyped[N]) bool {
+	switch t := m.(type) {
+	case types.Nested[N]:
+		return true
+	case types.TypeFunction[N]:
+		function, _ := t.FunctionAndIndexes()
+		_, params := function.Split()
+		return fun.FoldLeft(false, params, func(has bool, param types.Monotyped[N]) bool {
+			return has || hasNested(param)
+		})
+	}
+	return false
+}
+
+// turns a core term of one type into a core term of another; nil leaves terms
+// as they are
+type coercion[N nameable.Nameable] func(Core[N]) Core[N]
+
+func (k coercion[N]) apply(term Core[N]) Core[N] {
+	if k == nil {
+		return term
+	}
+	return k(term)
+}
+
+// checks that a value of type `actual` can be used where a value of type
+// `expected` is wanted, i.e., that `actual` is at least as polymorphic as
+// `expected`. Returns the coercion that turns a core term of type `actual`
+// into one of type `expected`
+//
+//	skolemize(σ2) = ρ    σ1 ≤ ρ ~> k        t = Inst(σ1) = [a := u]ρ1    t ≤ t2 ~> k
+//	-----------------------------------    ------------------------------------------
+//	      σ1 ≤ σ2 ~> Λa . k(·)                       σ1 ≤ t2 ~> k(· @u)
+//
+//	t3 ≤ t1 ~> k0    t2 ≤ t4 ~> k1
+//	------------------------------------------
+//	t1 -> t2 ≤ t3 -> t4 ~> λx: t3 . k1(· k0(x))
+//
+// Otherwise, `actual` and `expected` must unify and terms are left as they are
+func (cxt *Context[N]) subsCheck(actual, expected types.Monotyped[N]) (coercion[N], Status) {
+	actual, expected = cxt.GetSub(actual), cxt.GetSub(expected)
+
+	if nested, ok := expected.(types.Nested[N]); ok {
+		rho, skolems := cxt.skolemize(nested.GetPolytype())
+		k, stat := cxt.subsCheck(actual, rho)
+		if stat.NotOk() {
+			return nil, stat
+		}
+		if cxt.skolemsEscape(skolems, actual) {
+			return nil, SkolemEscape
+		}
+		return func(term Core[N]) Core[N] {
+			return coreGeneralization[N](skolems, nil, k.apply(term), expected)
+		}, Ok
+	}
+
+	if nested, ok := actual.(types.Nested[N]); ok {
+		t, args := cxt.instantiate(nested.GetPolytype())
+		k, stat := cxt.subsCheck(t, expected)
+		if stat.NotOk() {
+			return nil, stat
+		}
+		return func(term Core[N]) Core[N] {
+			if len(args) != 0 {
+				term = CoreTypeApp[N]{term, args, nil, t}
+			}
+			return k.apply(term)
+		}, Ok
+	}
+
+	if hasNested(actual) || hasNested(expected) {
+		a0, a1, actualIsFunction := cxt.splitFunction(actual)
+		x0, x1, expectedIsFunction := cxt.splitFunction(expected)
+		if actualIsFunction && expectedIsFunction {
+			k0, stat := cxt.subsCheck(x0, a0)
+			if stat.NotOk() {
+				return nil, stat
+			}
+			k1, stat := cxt.subsCheck(a1, x1)
+			if stat.NotOk() || (k0 == nil && k1 == nil) {
+				return nil, stat
+			}
+			return func(term Core[N]) Core[N] {
+				_, x := cxt.freshName()
+				arg := k0.apply(CoreVar[N]{x.Name, x0})
+				return CoreLambda[N]{x.Name, x0, k1.apply(CoreApp[N]{term, arg, a1}), expected}
+			}, Ok
+		}
+	}
+
+	return nil, cxt.Unify(expected, actual)
+}
+
+// returns true iff `sigma1` is at least as general as `sigma2`, i.e., every
+// instance of `sigma2` is an instance of `sigma1`. The class constraints of
+// `sigma1` must follow from those of `sigma2` and the instances in the
+// context. The context is left as it was
+//
+//	skolemize(σ2) = P => ρ    Inst(σ1) = Q => t    t ≤ ρ    P ⊢ Q
+//	-------------------------------------------------------------
+//	                            σ1 ≥ σ2
+//
+// For example, `forall a . a -> a` is more general than `Int -> Int`, and
+// `forall a . Eq a => a -> a` is more general than `forall b . Ord b => b -> b`
+func (cxt *Context[N]) IsMoreGeneralThan(sigma1, sigma2 types.Type[N]) bool {
+	snap := cxt.Snapshot()
+	defer cxt.Rollback(snap)
+
+	frees := fun.FMap(cxt.freeVariables(sigma1), func(v types.Variable[N]) types.Monotyped[N] { return v })
+
+	rho, skolems := cxt.skolemize(sigma2)
+	assumptions := asAssumptions(cxt.assumeContext(sigma2, skolems))
+
+	// instantiate `sigma1` w/o wanting its constraints (see instQualified)
+	var t types.Monotyped[N]
+	var context []types.Predicate[N]
+	switch sigma := sigma1.(type) {
+	case types.Qualified[N]:
+		var vs []types.Monotyped[N]
+		t, vs = cxt.instantiate(sigma.GetPolytype())
+		context = fun.FMap(sigma.GetContext(), func(p types.Predicate[N]) types.Predicate[N] {
+			return p.ReplaceDependent(sigma.GetBinders(), vs)
+		})
+	case types.Polytype[N]:
+		t = cxt.Inst(sigma)
+	default:
+		t = cxt.Inst(types.Forall[N]().Bind(sigma1.(types.DependentTyped[N])))
+	}
+
+	if _, stat := cxt.subsCheck(t, rho); stat.NotOk() || cxt.skolemsEscape(skolems, frees...) {
+		return false
+	}
+	for _, p := range context {
+		if _, entailed := cxt.entails(assumptions, p); !entailed {
+			return false
+		}
+	}
+	return true
+}
+
+// unifies nested polytypes `a` and `b`. Nested polytypes unify iff they are
+// equal up to the names of the variables they bind
+func (cxt *Context[N]) unifyNested(a, b types.Nested[N]) Status {
+	sigmaA, sigmaB := a.GetPolytype(), b.GetPolytype()
+	if len(sigmaA.GetBinders()) != len(sigmaB.GetBinders()) {
+		cxt.mismatch = []types.Type[N]{a, b}
+		return ConstantMismatch
+	}
+
+	skolems := fun.FMap(sigmaA.GetBinders(), cxt.newSkolem)
+	ta := types.GetDependent(sigmaA.GetBound()).ReplaceDependent(sigmaA.GetBinders(), skolems)
+	tb := types.GetDependent(sigmaB.GetBound()).ReplaceDependent(sigmaB.GetBinders(), skolems)
+	if stat := cxt.Unify(ta, tb); stat.NotOk() {
+		return stat
+	}
+	if cxt.skolemsEscape(skolems, a, b) {
+		return SkolemEscape
+	}
+	return Ok
+}
@@ -0,0 +1,240 @@
+package inf
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/bridge"
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+	"github.com/petersalex27/yew-packages/types"
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+// creates a context w/
+//
+//	0: Int
+//	true: Bool
+//	pair: forall a b . a -> b -> Pair a b
+//	runST: forall b . (forall s . ST s b) -> b
+//	ret: forall s a . a -> ST s a
+//	newRef: forall s a . a -> ST s (Ref s a)
+func makeRankTestContext() *Context[nameable.Testable] {
+	cxt := NewTestableContext()
+	name := nameable.MakeTestable
+	con := func(s string) types.Constant[nameable.Testable] { return types.MakeConst(name(s)) }
+	a, b, s := types.Var(name("a")), types.Var(name("b")), types.Var(name("s"))
+	fn := cxt.TypeContext.Function
+
+	cxt.Add(expr.MakeConst(name("0")), con("Int"))
+	cxt.Add(expr.MakeConst(name("true")), con("Bool"))
+	Pair_a_b := types.Apply[nameable.Testable](con("Pair"), a, b)
+	cxt.Add(expr.MakeConst(name("pair")), types.Forall(a, b).Bind(fn(a, fn(b, Pair_a_b))))
+
+	ST := func(t types.Monotyped[nameable.Testable]) types.Monotyped[nameable.Testable] {
+		return types.Apply[nameable.Testable](con("ST"), s, t)
+	}
+	action := types.Nest(types.Forall(s).Bind(ST(b)))
+	cxt.Add(expr.MakeConst(name("runST")), types.Forall(b).Bind(fn(action, b)))
+	cxt.Add(expr.MakeConst(name("ret")), types.Forall(s, a).Bind(fn(a, ST(a))))
+	Ref_s_a := types.Apply[nameable.Testable](con("Ref"), s, a)
+	cxt.Add(expr.MakeConst(name("newRef")), types.Forall(s, a).Bind(fn(a, ST(Ref_s_a))))
+
+	id := types.Nest(types.Forall(a).Bind(fn(a, a)))
+	cxt.Add(expr.MakeConst(name("nested")), fn(con("Int"), id))
+	cxt.Add(expr.MakeConst(name("applyInt")), fn(fn(con("Int"), con("Int")), con("Int")))
+	return cxt
+}
+
+func TestHigherRank(t *testing.T) {
+	name := nameable.MakeTestable
+	c := func(s string) expr.Const[nameable.Testable] { return expr.MakeConst(name(s)) }
+	f, x, y := expr.Var(name("f")), expr.Var(name("x")), expr.Var(name("y"))
+	a := types.Var(name("a"))
+	Int, Bool := types.MakeConst(name("Int")), types.MakeConst(name("Bool"))
+	cxt := NewTestableContext()
+	fn := cxt.TypeContext.Function
+
+	// λf . pair (f 0) (f true)
+	both := expr.Bind(f).In(expr.Apply[nameable.Testable](c("pair"),
+		expr.Apply[nameable.Testable](f, c("0")),
+		expr.Apply[nameable.Testable](f, c("true")),
+	))
+	// (forall a . a -> a) -> Pair Int Bool
+	rank2 := fn(types.Nest(types.Forall(a).Bind(fn(a, a))), types.Apply[nameable.Testable](types.MakeConst(name("Pair")), Int, Bool))
+	annotated := bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](both, rank2)
+	id := expr.Bind(x).In(x)
+
+	tests := []struct {
+		description string
+		input       expr.Expression[nameable.Testable]
+		stat        Status
+		expect      string
+	}{
+		{
+			`λf . pair (f 0) (f true) : (forall a . a -> a) -> Pair Int Bool`,
+			annotated,
+			Ok,
+			"((forall a . (a -> a)) -> (Pair Int Bool))",
+		},
+		{
+			`(λf . pair (f 0) (f true) : (forall a . a -> a) -> Pair Int Bool) (λx . x)`,
+			expr.Apply[nameable.Testable](annotated, id),
+			Ok,
+			"(Pair Int Bool)",
+		},
+		{
+			`(λf . pair (f 0) (f true) : (forall a . a -> a) -> Pair Int Bool) (λx . 0)`,
+			expr.Apply[nameable.Testable](annotated, expr.Bind(x).In(c("0"))),
+			ConstantMismatch,
+			"",
+		},
+		{
+			`λf . pair (f 0) (f true)`,
+			both,
+			ConstantMismatch,
+			"",
+		},
+		{
+			`λy . (y : forall a . a)`,
+			expr.Bind(y).In(bridge.Judgment[nameable.Testable, expr.Expression[nameable.Testable]](y, types.Forall(a).Bind(a))),
+			SkolemEscape,
+			"",
+		},
+		{
+			`runST (ret 0)`,
+			expr.Apply[nameable.Testable](c("runST"), expr.Apply[nameable.Testable](c("ret"), c("0"))),
+			Ok,
+			"Int",
+		},
+		{
+			`runST (newRef 0)`,
+			expr.Apply[nameable.Testable](c("runST"), expr.Apply[nameable.Testable](c("newRef"), c("0"))),
+			SkolemEscape,
+			"",
+		},
+		{
+			`λf . runST f`,
+			expr.Bind(f).In(expr.Apply[nameable.Testable](c("runST"), f)),
+			SkolemEscape,
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeRankTestContext()
+		sigma, reports := cxt.Infer(test.input)
+		if test.stat.IsOk() {
+			if len(reports) != 0 {
+				t.Fatal(testutil.Testing("reports", test.description).FailMessage(nil, reports, i))
+			}
+			if actual := types.GetDependent(sigma.GetBound()).String(); actual != test.expect {
+				t.Fatal(testutil.Testing("type", test.description).FailMessage(test.expect, actual, i))
+			}
+		} else if len(reports) != 1 || !reports[0].Status.Is(test.stat) {
+			t.Fatal(testutil.Testing("reports", test.description).FailMessage(test.stat, reports, i))
+		}
+	}
+}
+
+func TestIsMoreGeneralThan(t *testing.T) {
+	name := nameable.MakeTestable
+	a, b := types.Var(name("a")), types.Var(name("b"))
+	Int, Bool := types.MakeConst(name("Int")), types.MakeConst(name("Bool"))
+	fn := NewTestableContext().TypeContext.Function
+	eq := func(m types.Monotyped[nameable.Testable]) types.Predicate[nameable.Testable] {
+		return types.Pred[nameable.Testable](name("Eq"), m)
+	}
+	ord := func(m types.Monotyped[nameable.Testable]) types.Predicate[nameable.Testable] {
+		return types.Pred[nameable.Testable](name("Ord"), m)
+	}
+
+	tests := []struct {
+		description    string
+		sigma1, sigma2 types.Type[nameable.Testable]
+		expect         bool
+	}{
+		{
+			`forall a . a -> a ≥ Int -> Int`,
+			types.Forall(a).Bind(fn(a, a)),
+			fn(Int, Int),
+			true,
+		},
+		{
+			`Int -> Int ≱ forall a . a -> a`,
+			fn(Int, Int),
+			types.Forall(a).Bind(fn(a, a)),
+			false,
+		},
+		{
+			`forall a . a -> a ≱ Int -> Bool`,
+			types.Forall(a).Bind(fn(a, a)),
+			fn(Int, Bool),
+			false,
+		},
+		{
+			`forall a b . a -> b ≥ forall a . a -> a`,
+			types.Forall(a, b).Bind(fn(a, b)),
+			types.Forall(a).Bind(fn(a, a)),
+			true,
+		},
+		{
+			`forall a . a -> a ≱ forall a b . a -> b`,
+			types.Forall(a).Bind(fn(a, a)),
+			types.Forall(a, b).Bind(fn(a, b)),
+			false,
+		},
+		{
+			`forall b . b -> b ≥ forall a . a -> a`,
+			types.Forall(b).Bind(fn(b, b)),
+			types.Forall(a).Bind(fn(a, a)),
+			true,
+		},
+		{
+			`a -> a ≱ forall b . b -> b`,
+			fn(a, a),
+			types.Forall(b).Bind(fn(b, b)),
+			false,
+		},
+		{
+			`forall a . Eq a => a -> a ≥ Int -> Int`,
+			types.Qualify(types.Forall(a).Bind(fn(a, a)), eq(a)),
+			fn(Int, Int),
+			true,
+		},
+		{
+			`forall a . Eq a => a -> a ≱ Bool -> Bool`,
+			types.Qualify(types.Forall(a).Bind(fn(a, a)), eq(a)),
+			fn(Bool, Bool),
+			false,
+		},
+		{
+			`forall a . Eq a => a -> a ≥ forall b . Ord b => b -> b`,
+			types.Qualify(types.Forall(a).Bind(fn(a, a)), eq(a)),
+			types.Qualify(types.Forall(b).Bind(fn(b, b)), ord(b)),
+			true,
+		},
+		{
+			`forall a . Ord a => a -> a ≱ forall b . Eq b => b -> b`,
+			types.Qualify(types.Forall(a).Bind(fn(a, a)), ord(a)),
+			types.Qualify(types.Forall(b).Bind(fn(b, b)), eq(b)),
+			false,
+		},
+		{
+			`forall a . a -> a ≥ forall b . Eq b => b -> b`,
+			types.Forall(a).Bind(fn(a, a)),
+			types.Qualify(types.Forall(b).Bind(fn(b, b)), eq(b)),
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		cxt := makeClassTestContext()
+		counter := cxt.TypeContext.GetVarCounter()
+		if actual := cxt.IsMoreGeneralThan(test.sigma1, test.sigma2); actual != test.expect {
+			t.Fatal(testutil.Testing("generality", test.description).FailMessage(test.expect, actual, i))
+		}
+		if actual := cxt.TypeContext.GetVarCounter(); actual != counter {
+			t.Fatal(testutil.Testing("rollback", test.description).FailMessage(counter, actual, i))
+		}
+	}
+}
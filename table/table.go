@@ -68,4 +68,14 @@ func (table *Table[T]) Remove(key nameable.Nameable) (val T, ok bool) {
 }
 
 // return underlying data used for table
-func (table *Table[T]) GetRawMap() map[string]tableElement[T] { return table.data }
\ No newline at end of file
+func (table *Table[T]) GetRawMap() map[string]tableElement[T] { return table.data }
+
+// Calls `f` on each key-value pair in the table until `f` returns false.
+// Iteration order is unspecified
+func (table *Table[T]) ForEach(f func(key nameable.Nameable, val T) bool) {
+	for _, elem := range table.data {
+		if !f(elem.key, elem.val) {
+			return
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package table
+
+import "github.com/petersalex27/yew-packages/nameable"
+
+// node in UnionFind forest
+//
+// see UnionFind
+type unionFindNode[T any] struct {
+	// nil iff node is the root of its class
+	parent *unionFindNode[T]
+	// upper bound on height of node's subtree
+	rank uint
+	// element named by node's key
+	elem T
+	// value bound to node's class; only meaningful for roots
+	val   T
+	bound bool
+}
+
+// disjoint sets of elements named by instances of nameable.Nameable. Each set
+// (class) can be bound to a value of type T; the representative of a class is
+// the value it is bound to, or, if it is not bound, the element at the root of
+// the class.
+//
+// Classes are merged by rank, and paths are compressed on each lookup. While a
+// mark is held (see Mark), changes are recorded so they can be undone
+type UnionFind[T any] struct {
+	nodes map[string]*unionFindNode[T]
+	// number of marks held
+	marks int
+	// changes made while marks are held, oldest first
+	trail []unionFindChange[T]
+}
+
+// change made to UnionFind
+//
+// see UnionFind
+type unionFindChange[T any] struct {
+	// node before the change; unused when `created` is true
+	old unionFindNode[T]
+	// changed node
+	node *unionFindNode[T]
+	// key of node iff node was created by the change
+	created string
+}
+
+// records that `node` is about to change
+func (uf *UnionFind[T]) record(node *unionFindNode[T]) {
+	if uf.marks != 0 {
+		uf.trail = append(uf.trail, unionFindChange[T]{old: *node, node: node})
+	}
+}
+
+// Makes a new, empty union-find structure with initial capacity cap[0].
+// Calling this function with no arguments is also valid, in which case a
+// small starting capacity is used
+func NewUnionFind[T any](cap ...uint) *UnionFind[T] {
+	out := new(UnionFind[T])
+	if len(cap) == 0 {
+		out.nodes = make(map[string]*unionFindNode[T])
+	} else {
+		out.nodes = make(map[string]*unionFindNode[T], cap[0])
+	}
+	return out
+}
+
+// number of elements in structure
+func (uf *UnionFind[T]) Len() int {
+	return len(uf.nodes)
+}
+
+// adds `elem` under `key` as the only member of a new class iff `key` is not
+// already in the structure
+func (uf *UnionFind[T]) MakeSet(key nameable.Nameable, elem T) {
+	if _, found := uf.nodes[key.GetName()]; !found {
+		uf.nodes[key.GetName()] = &unionFindNode[T]{elem: elem}
+		if uf.marks != 0 {
+			uf.trail = append(uf.trail, unionFindChange[T]{created: key.GetName()})
+		}
+	}
+}
+
+// returns root of `node`'s class, pointing each node on the path from `node`
+// to the root directly at the root
+func (uf *UnionFind[T]) root(node *unionFindNode[T]) *unionFindNode[T] {
+	root := node
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	for node != root {
+		next := node.parent
+		if next != root {
+			uf.record(node)
+			node.parent = root
+		}
+		node = next
+	}
+	return root
+}
+
+// returns root of class containing `key`, if `key` is in the structure
+func (uf *UnionFind[T]) findRoot(key nameable.Nameable) (root *unionFindNode[T], found bool) {
+	var node *unionFindNode[T]
+	if node, found = uf.nodes[key.GetName()]; found {
+		root = uf.root(node)
+	}
+	return
+}
+
+// If `key` is not in the structure, then `_, false` is returned, else the
+// representative of the class containing `key` is returned and true is
+// returned
+func (uf *UnionFind[T]) Find(key nameable.Nameable) (rep T, found bool) {
+	var root *unionFindNode[T]
+	if root, found = uf.findRoot(key); !found {
+		return
+	}
+
+	if root.bound {
+		return root.val, true
+	}
+	return root.elem, true
+}
+
+// returns true iff the element named by `key` is the representative of its
+// class, i.e., `key` is not in the structure, or `key` is the root of an
+// unbound class
+func (uf *UnionFind[T]) IsRepresentative(key nameable.Nameable) bool {
+	node, found := uf.nodes[key.GetName()]
+	return !found || (node.parent == nil && !node.bound)
+}
+
+// merges the classes containing `a` and `b`. When both classes have the same
+// rank, the root of `b`'s class becomes the root of the merged class. If only
+// one of the classes is bound, the merged class is bound to its value; if both
+// are, the merged class is bound to the value of `b`'s class.
+//
+// Returns false (and does nothing) iff `a` or `b` is not in the structure
+func (uf *UnionFind[T]) Union(a, b nameable.Nameable) bool {
+	ra, foundA := uf.findRoot(a)
+	rb, foundB := uf.findRoot(b)
+	if !foundA || !foundB {
+		return false
+	}
+
+	if ra == rb {
+		return true
+	}
+
+	val, bound := ra.val, ra.bound
+	if rb.bound {
+		val, bound = rb.val, true
+	}
+
+	child, parent := ra, rb
+	if ra.rank > rb.rank {
+		child, parent = rb, ra
+	}
+
+	uf.record(child)
+	uf.record(parent)
+	var zero T
+	child.parent = parent
+	child.val, child.bound = zero, false
+	parent.val, parent.bound = val, bound
+	if ra.rank == rb.rank {
+		parent.rank++
+	}
+	return true
+}
+
+// binds the class containing `key` to `val`, overwriting any previous value.
+//
+// Returns false (and does nothing) iff `key` is not in the structure
+func (uf *UnionFind[T]) Bind(key nameable.Nameable, val T) bool {
+	root, found := uf.findRoot(key)
+	if found {
+		uf.record(root)
+		root.val, root.bound = val, true
+	}
+	return found
+}
+
+// starts recording changes; returned mark can be passed to Undo to undo all
+// changes made after the mark was taken. Each mark must be given back by
+// calling Undo or Release
+func (uf *UnionFind[T]) Mark() (mark int) {
+	uf.marks++
+	return len(uf.trail)
+}
+
+// undoes all changes made after `mark` was taken and gives back `mark`
+func (uf *UnionFind[T]) Undo(mark int) {
+	for i := len(uf.trail) - 1; i >= mark; i-- {
+		change := uf.trail[i]
+		if change.node == nil {
+			delete(uf.nodes, change.created)
+		} else {
+			*change.node = change.old
+		}
+	}
+	uf.trail = uf.trail[:mark]
+	uf.Release(mark)
+}
+
+// gives back `mark`, keeping all changes made after it was taken. Changes are
+// no longer recorded once every mark has been given back
+func (uf *UnionFind[T]) Release(mark int) {
+	if uf.marks == 0 {
+		panic("bug: released mark that was never taken")
+	}
+	uf.marks--
+	if uf.marks == 0 {
+		uf.trail = nil
+	}
+}
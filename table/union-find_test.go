@@ -0,0 +1,114 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/petersalex27/yew-packages/util/testutil"
+)
+
+func TestUnionFind(t *testing.T) {
+	a, b, c, d := test_nameable("a"), test_nameable("b"), test_nameable("c"), test_nameable("d")
+
+	uf := NewUnionFind[string]()
+	for _, key := range []test_nameable{a, b, c, d} {
+		uf.MakeSet(key, key.GetName())
+	}
+
+	// a and b are merged into b's class
+	uf.Union(a, b)
+	// c's class has a lower rank than b's class, so it joins b's class
+	uf.Union(c, a)
+	// d is bound, then merged into b's class; merged class keeps d's value
+	uf.Bind(d, "D")
+	uf.Union(a, d)
+
+	tests := []struct {
+		key            test_nameable
+		expect         string
+		representative bool
+	}{
+		{a, "D", false},
+		{b, "D", false},
+		{c, "D", false},
+		{d, "D", false},
+		{test_nameable("e"), "", true},
+	}
+
+	for i, test := range tests {
+		actual, _ := uf.Find(test.key)
+		if actual != test.expect {
+			t.Fatal(testutil.Testing("find").FailMessage(test.expect, actual, i))
+		}
+		if rep := uf.IsRepresentative(test.key); rep != test.representative {
+			t.Fatal(testutil.Testing("representative").FailMessage(test.representative, rep, i))
+		}
+	}
+
+	// paths are compressed
+	for _, key := range []test_nameable{a, c, d} {
+		node := uf.nodes[key.GetName()]
+		if node.parent == nil || node.parent.parent != nil {
+			t.Fatal(testutil.Testing("path compression").FailMessage("depth 1", key, 0))
+		}
+	}
+}
+
+func TestUnionFindRepresentative(t *testing.T) {
+	a, b := test_nameable("a"), test_nameable("b")
+	uf := NewUnionFind[string]()
+	uf.MakeSet(a, "a")
+	uf.MakeSet(b, "b")
+
+	if !uf.IsRepresentative(a) || !uf.IsRepresentative(b) {
+		t.Fatal(testutil.Testing("singletons").FailMessage(true, false, 0))
+	}
+
+	uf.Union(a, b)
+	if rep, _ := uf.Find(a); rep != "b" || uf.IsRepresentative(a) || !uf.IsRepresentative(b) {
+		t.Fatal(testutil.Testing("union").FailMessage("b", rep, 1))
+	}
+
+	if uf.Union(a, test_nameable("c")) {
+		t.Fatal(testutil.Testing("union w/ missing key").FailMessage(false, true, 2))
+	}
+}
+
+func TestUnionFindUndo(t *testing.T) {
+	a, b, c := test_nameable("a"), test_nameable("b"), test_nameable("c")
+	uf := NewUnionFind[string]()
+	uf.MakeSet(a, "a")
+	uf.MakeSet(b, "b")
+
+	outer := uf.Mark()
+	uf.Union(a, b)
+	inner := uf.Mark()
+	uf.MakeSet(c, "c")
+	uf.Union(c, a)
+	uf.Bind(c, "C")
+
+	// undo inner changes only
+	uf.Undo(inner)
+	if _, found := uf.Find(c); found {
+		t.Fatal(testutil.Testing("undo creation").FailMessage(false, found, 0))
+	}
+	if rep, _ := uf.Find(a); rep != "b" {
+		t.Fatal(testutil.Testing("undo bind").FailMessage("b", rep, 1))
+	}
+
+	// undo outer changes
+	uf.Undo(outer)
+	if rep, _ := uf.Find(a); rep != "a" || !uf.IsRepresentative(a) {
+		t.Fatal(testutil.Testing("undo union").FailMessage("a", rep, 2))
+	}
+	if len(uf.trail) != 0 {
+		t.Fatal(testutil.Testing("trail").FailMessage(0, len(uf.trail), 3))
+	}
+
+	// released changes are kept
+	mark := uf.Mark()
+	uf.Union(a, b)
+	uf.Release(mark)
+	if rep, _ := uf.Find(a); rep != "b" || len(uf.trail) != 0 {
+		t.Fatal(testutil.Testing("release").FailMessage("b", rep, 4))
+	}
+}
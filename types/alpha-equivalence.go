@@ -0,0 +1,267 @@
+package types
+
+import (
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// variables bound by a pair of polytypes (or dependent types) being compared,
+// and which bound variable of one stands for which of the other
+type alphaScope[T nameable.Nameable] struct {
+	left, right map[string]bool
+	// bound variables matched so far
+	leftToRight, rightToLeft map[string]string
+	// index variables bound by `mapval` on the left and the index variables
+	// bound at the same positions on the right
+	indexes [][2]expr.Variable[T]
+}
+
+func newAlphaScope[T nameable.Nameable](left, right []Variable[T]) *alphaScope[T] {
+	scope := &alphaScope[T]{map[string]bool{}, map[string]bool{}, map[string]string{}, map[string]string{}, nil}
+	for _, v := range left {
+		scope.left[v.GetName()] = true
+	}
+	for _, v := range right {
+		scope.right[v.GetName()] = true
+	}
+	return scope
+}
+
+// scopes of the polytypes being compared, innermost last
+type alphaEnv[T nameable.Nameable] []*alphaScope[T]
+
+// returns env w/ `scope` as its innermost scope
+func (env alphaEnv[T]) push(scope *alphaScope[T]) alphaEnv[T] {
+	out := make(alphaEnv[T], len(env), len(env)+1)
+	copy(out, env)
+	return append(out, scope)
+}
+
+// returns index of innermost scope binding `name` on the left (or, if `left` is
+// false, on the right); -1 is returned when `name` is free
+func (env alphaEnv[T]) binding(name string, left bool) int {
+	for i := len(env) - 1; i >= 0; i-- {
+		if (left && env[i].left[name]) || (!left && env[i].right[name]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matches bound variables `l` and `r` of scope `i`; returns false iff either
+// is already matched w/ another variable
+func (env alphaEnv[T]) match(i int, l, r string) bool {
+	scope := env[i]
+	r2, leftMatched := scope.leftToRight[l]
+	l2, rightMatched := scope.rightToLeft[r]
+	if leftMatched || rightMatched {
+		return r2 == r && l2 == l
+	}
+	scope.leftToRight[l], scope.rightToLeft[r] = r, l
+	return true
+}
+
+// returns the matches of each scope of env, so they can be restored w/ restore
+func (env alphaEnv[T]) save() []map[string]string {
+	saved := make([]map[string]string, len(env))
+	for i, scope := range env {
+		saved[i] = make(map[string]string, len(scope.leftToRight))
+		for l, r := range scope.leftToRight {
+			saved[i][l] = r
+		}
+	}
+	return saved
+}
+
+// undoes the matches made since `saved` was returned by save
+func (env alphaEnv[T]) restore(saved []map[string]string) {
+	for i, scope := range env {
+		scope.leftToRight, scope.rightToLeft = saved[i], make(map[string]string, len(saved[i]))
+		for l, r := range saved[i] {
+			scope.rightToLeft[r] = l
+		}
+	}
+}
+
+// returns true iff `p` and `t` are equal up to the names of the variables they
+// bind. Binders that bind nothing are ignored, as is the order of binders and
+// of class constraints. For example,
+//
+//	(forall x1 x2 . x1) ≡ (forall y1 . y1)
+//	(forall a b . (a -> b)) ≡ (forall b a . (b -> a))
+//	(forall a . (a -> b)) ≢ (forall b . (b -> b))
+//
+// Variables bound by nested polytypes and index variables bound by `mapval`
+// are compared the same way. See (Polytype).Equals for syntactic equality
+func (p Polytype[T]) AlphaEquals(t Type[T]) bool {
+	return alphaEquals[T](nil, p, t)
+}
+
+// like (Polytype).AlphaEquals, but the class constraints of `q` and `t` must
+// be equal up to the names of the variables bound, too
+func (q Qualified[T]) AlphaEquals(t Type[T]) bool {
+	return alphaEquals[T](nil, q, t)
+}
+
+// splits `t` into the variables it binds, its class constraints, and the type
+// they bind; `ok` is false iff `t` is none of a qualified type, a polytype, or
+// a dependent type
+func splitQualified[T nameable.Nameable](t Type[T]) (binders []Variable[T], context []Predicate[T], bound DependentTyped[T], ok bool) {
+	switch x := t.(type) {
+	case Qualified[T]:
+		return x.typeBinders, x.context, x.bound, true
+	case Polytype[T]:
+		return x.typeBinders, nil, x.bound, true
+	case Nested[T]:
+		return x.sigma.typeBinders, nil, x.sigma.bound, true
+	case DependentTyped[T]:
+		return nil, nil, x, true
+	}
+	return nil, nil, nil, false
+}
+
+func alphaEquals[T nameable.Nameable](env alphaEnv[T], a, b Type[T]) bool {
+	_, aIsQualified := a.(Qualified[T])
+	_, bIsQualified := b.(Qualified[T])
+	_, aIsPolytype := a.(Polytype[T])
+	_, bIsPolytype := b.(Polytype[T])
+	if aIsQualified || bIsQualified || aIsPolytype || bIsPolytype {
+		return alphaEqualsQualified(env, a, b)
+	}
+
+	switch x := a.(type) {
+	case Variable[T]:
+		y, ok := b.(Variable[T])
+		if !ok {
+			return false
+		}
+		i, j := env.binding(x.GetName(), true), env.binding(y.GetName(), false)
+		if i != j {
+			return false
+		} else if i == -1 {
+			return x.Equals(y)
+		}
+		return env.match(i, x.GetName(), y.GetName())
+	case Nested[T]:
+		_, ok := b.(Nested[T])
+		return ok && alphaEqualsQualified(env, a, b)
+	case DependentType[T]:
+		return alphaEqualsDependent(env, x, b)
+	case DependentTypeInstance[T]:
+		y, ok := b.(DependentTypeInstance[T])
+		if !ok || len(x.Indexes) != len(y.Indexes) || !alphaEquals[T](env, x.Application, y.Application) {
+			return false
+		}
+		for i := range x.Indexes {
+			if !alphaEqualsIndex(env, x.Indexes[i].AsTypeJudgment(), y.Indexes[i].AsTypeJudgment()) {
+				return false
+			}
+		}
+		return true
+	case Application[T]:
+		y, ok := b.(Application[T])
+		if !ok || len(x.ts) != len(y.ts) || !alphaEquals[T](env, x.c, y.c) {
+			return false
+		}
+		for i := range x.ts {
+			if !alphaEquals[T](env, x.ts[i], y.ts[i]) {
+				return false
+			}
+		}
+		return true
+	case Record[T]:
+		y, ok := b.(Record[T])
+		if !ok || len(x.fields) != len(y.fields) || (x.row == nil) != (y.row == nil) {
+			return false
+		}
+		for i, field := range x.fields {
+			other := y.fields[i]
+			if field.Label.GetName() != other.Label.GetName() || !alphaEquals[T](env, field.Type, other.Type) {
+				return false
+			}
+		}
+		return x.row == nil || alphaEquals[T](env, *x.row, *y.row)
+	default:
+		return a.Equals(b)
+	}
+}
+
+// compares qualified types, polytypes, and nested polytypes `a` and `b`; either
+// may also be a type that binds nothing
+func alphaEqualsQualified[T nameable.Nameable](env alphaEnv[T], a, b Type[T]) bool {
+	binders1, context1, bound1, ok1 := splitQualified(a)
+	binders2, context2, bound2, ok2 := splitQualified(b)
+	if !ok1 || !ok2 || len(context1) != len(context2) {
+		return false
+	}
+
+	inner := env.push(newAlphaScope(binders1, binders2))
+	if !alphaEquals[T](inner, bound1, bound2) {
+		return false
+	}
+
+	// each constraint of `a` must match a different constraint of `b`
+	matched := make([]bool, len(context2))
+	for _, p := range context1 {
+		found := false
+		for i, q := range context2 {
+			if matched[i] {
+				continue
+			}
+			saved := inner.save()
+			if found = alphaEqualsPredicate(inner, p, q); found {
+				matched[i] = true
+				break
+			}
+			inner.restore(saved)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func alphaEqualsPredicate[T nameable.Nameable](env alphaEnv[T], p, q Predicate[T]) bool {
+	if p.class.GetName() != q.class.GetName() || len(p.params) != len(q.params) {
+		return false
+	}
+	for i := range p.params {
+		if !alphaEquals[T](env, p.params[i], q.params[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// compares dependent type `d` and `t`; the index variables bound by each are
+// matched by position
+func alphaEqualsDependent[T nameable.Nameable](env alphaEnv[T], d DependentType[T], t Type[T]) bool {
+	d2, ok := t.(DependentType[T])
+	if !ok || len(d.mapval) != len(d2.mapval) {
+		return false
+	}
+	scope := newAlphaScope[T](nil, nil)
+	for i, j := range d.mapval {
+		if !alphaEquals[T](env, j.ty, d2.mapval[i].ty) {
+			return false
+		}
+		scope.indexes = append(scope.indexes, [2]expr.Variable[T]{j.expression, d2.mapval[i].expression})
+	}
+	return alphaEquals[T](env.push(scope), d.Function, d2.Function)
+}
+
+// compares indexes `a` and `b` after renaming the index variables `a` uses w/
+// the index variables of `b` they were matched w/
+func alphaEqualsIndex[T nameable.Nameable](env alphaEnv[T], a, b TypeJudgment[T, expr.Referable[T]]) bool {
+	if !alphaEquals[T](env, a.ty, b.ty) {
+		return false
+	}
+	var e expr.Expression[T] = a.expression
+	for i := len(env) - 1; i >= 0; i-- {
+		for _, pair := range env[i].indexes {
+			e, _ = e.Replace(pair[0], pair[1])
+		}
+	}
+	return e.StrictEquals(b.expression)
+}
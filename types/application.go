@@ -130,6 +130,11 @@ func (a Application[T]) Collect() []T {
 	return res
 }
 
+// returns the type `a` applies to its params
+func (a Application[T]) GetHead() Monotyped[T] {
+	return a.c
+}
+
 func (a Application[T]) Split() (name string, params []Monotyped[T]) {
 	return a.c.GetReferred().GetName(), a.ts
 }
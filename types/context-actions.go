@@ -14,8 +14,86 @@ func freeVarName(n uint32) string {
 
 func (cxt *Context[T]) NewVar() Variable[T] {
 	n := cxt.varCounter
-	cxt.varCounter++
-	return cxt.Var(freeVarName(n)).BoundIn(int32(cxt.contextNumber))
+	cxt.varCounter += cxt.stride()
+	return cxt.Var(freeVarName(n)).BoundIn(int32(cxt.contextNumber)).AtLevel(cxt.level)
+}
+
+// returns difference between the counters of consecutive new variables
+func (cxt *Context[T]) stride() uint32 {
+	if cxt.varStride == 0 {
+		return 1
+	}
+	return cxt.varStride
+}
+
+// returns `n` contexts that can create variables concurrently: no two of them
+// create variables w/ the same name, and none creates a variable w/ the name
+// of a variable `cxt` has already created. Child `i` counts from the current
+// counter plus `i` in steps of `n` (times the step of `cxt`). The children
+// start at the level of `cxt`, but each has its own type stack. `cxt` must not
+// create variables until the children are given back w/ Merge
+func (cxt *Context[T]) Fork(n int) []*Context[T] {
+	children := make([]*Context[T], n)
+	for i := range children {
+		child := NewContext[T]()
+		child.contextNumber = cxt.contextNumber
+		child.varCounter = cxt.varCounter + uint32(i)*cxt.stride()
+		child.varStride = uint32(n) * cxt.stride()
+		child.level = cxt.level
+		child.makeName = cxt.makeName
+		children[i] = child
+	}
+	return children
+}
+
+// continues counting after every variable created by `children`, contexts
+// returned by Fork
+func (cxt *Context[T]) Merge(children ...*Context[T]) {
+	for _, child := range children {
+		if child.varCounter > cxt.varCounter {
+			cxt.varCounter = child.varCounter
+		}
+	}
+}
+
+// returns number of variables created by NewVar
+func (cxt *Context[T]) GetVarCounter() uint32 {
+	return cxt.varCounter
+}
+
+// returns the variables NewVar created since the counter was `counter` (see
+// GetVarCounter), in the order they were created
+func (cxt *Context[T]) VarsSince(counter uint32) []Variable[T] {
+	var vars []Variable[T]
+	for n := counter; n < cxt.varCounter; n += cxt.stride() {
+		vars = append(vars, cxt.Var(freeVarName(n)))
+	}
+	return vars
+}
+
+// sets number of variables created by NewVar; the next variable NewVar creates
+// is named after `n`
+func (cxt *Context[T]) SetVarCounter(n uint32) {
+	cxt.varCounter = n
+}
+
+// increases let-nesting depth; variables created until the matching call to
+// LeaveLevel are created at the new depth
+func (cxt *Context[T]) EnterLevel() {
+	cxt.level++
+}
+
+// decreases let-nesting depth
+func (cxt *Context[T]) LeaveLevel() {
+	if cxt.level == 0 {
+		panic("bug: tried to leave outermost level")
+	}
+	cxt.level--
+}
+
+// returns current let-nesting depth
+func (cxt *Context[T]) GetLevel() uint32 {
+	return cxt.level
 }
 
 func (cxt *Context[T]) PopMonotype() (Monotyped[T], error) {
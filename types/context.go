@@ -9,6 +9,8 @@ import (
 type Context[T nameable.Nameable] struct {
 	contextNumber int32
 	varCounter uint32
+	varStride uint32 // difference between consecutive counters, 0 iff 1
+	level uint32
 	makeName func(string)T
 	stack *stack.Stack[Type[T]]
 }
@@ -16,6 +18,7 @@ type Context[T nameable.Nameable] struct {
 func InheritContext[T nameable.Nameable](parent *Context[T]) *Context[T] {
 	child := NewContext[T]()
 	child.varCounter = parent.varCounter
+	child.level = parent.level
 	child.stack = parent.stack
 	child.makeName = parent.makeName
 
@@ -43,6 +43,11 @@ func (c EnclosingConst[T]) String() string {
 	return c.name.GetName()
 }
 
+// (EnclosingConst{1, "[]"}).GetSplit() == 1
+func (c EnclosingConst[T]) GetSplit() uint {
+	return c.splitAt
+}
+
 // (EnclosingConst{1, "[]"}).String() == ("[", "]")
 func (c EnclosingConst[T]) SplitString() (string, string) {
 	name := c.name.GetName()
@@ -0,0 +1,232 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// identifies a monotype interned by an Interner. Two monotypes interned by the
+// same interner have the same ID iff they are equal (see Equals), so IDs can
+// be compared in constant time and used as map keys in place of the monotypes
+type TypeID uint32
+
+// hash-conses monotypes: each monotype interned is given the ID of the first
+// monotype equal to it, and the monotypes returned share the subterms of the
+// monotypes interned before them. Interning is optional; monotypes that are
+// not interned behave as they always have. An interner is not safe for
+// concurrent use
+type Interner[T nameable.Nameable] struct {
+	ids   map[string]TypeID
+	nodes []internedNode[T]
+}
+
+type internedNode[T nameable.Nameable] struct {
+	// first monotype interned w/ the ID, rebuilt from interned subterms
+	ty Monotyped[T]
+	// IDs of the subterms whose free variables are free in `ty`
+	children []TypeID
+	// variables bound by `ty` (see Nested)
+	binders []Variable[T]
+	// true iff `frees` is known
+	memoized bool
+	frees    []Variable[T]
+}
+
+func NewInterner[T nameable.Nameable]() *Interner[T] {
+	return &Interner[T]{ids: map[string]TypeID{}}
+}
+
+// returns number of distinct monotypes interned, including subterms
+func (in *Interner[T]) Len() int {
+	return len(in.nodes)
+}
+
+// returns the monotype w/ ID `id`, which must have been returned by Intern
+func (in *Interner[T]) Type(id TypeID) Monotyped[T] {
+	return in.nodes[id].ty
+}
+
+// returns the ID of `m` along w/ the monotype interned for it, which shares
+// its subterms w/ every other monotype interned
+func (in *Interner[T]) Canonical(m Monotyped[T]) (id TypeID, canonical Monotyped[T]) {
+	id = in.Intern(m)
+	return id, in.nodes[id].ty
+}
+
+// key of a monotype among the monotypes interned; names are prefixed by their
+// lengths and IDs are terminated, so distinct keys are never confused
+type internKey struct{ strings.Builder }
+
+func (k *internKey) tag(t byte) { k.WriteByte(t) }
+
+func (k *internKey) name(s string) {
+	k.WriteString(strconv.Itoa(len(s)))
+	k.WriteByte(':')
+	k.WriteString(s)
+}
+
+func (k *internKey) id(id TypeID) {
+	k.WriteString(strconv.FormatUint(uint64(id), 10))
+	k.WriteByte(',')
+}
+
+func (k *internKey) variable(name string, boundContext int32) {
+	k.name(name)
+	k.WriteString(strconv.Itoa(int(boundContext)))
+	k.WriteByte(',')
+}
+
+// adds type `t` of an index to `key`, returning the ID of `t` if it is a
+// monotype
+func (in *Interner[T]) indexType(key *internKey, t Type[T]) (id TypeID, interned bool) {
+	m, ok := t.(Monotyped[T])
+	if !ok {
+		key.tag('?')
+		key.name(t.String())
+		return 0, false
+	}
+	id = in.Intern(m)
+	key.tag('=')
+	key.id(id)
+	return id, true
+}
+
+// returns ID of `m`, interning `m` and its subterms if they have not been
+// interned yet. Interning takes time linear in the size of `m`
+func (in *Interner[T]) Intern(m Monotyped[T]) TypeID {
+	var key internKey
+	node := internedNode[T]{ty: m}
+	switch x := m.(type) {
+	case Variable[T]:
+		key.tag('v')
+		key.variable(x.GetName(), x.boundContext)
+		node.memoized, node.frees = true, x.GetFreeVariables()
+	case Constant[T]:
+		key.tag('c')
+		key.name(x.GetName())
+	case InfixConst[T]:
+		key.tag('i')
+		key.name(x.GetName())
+	case EnclosingConst[T]:
+		key.tag('e')
+		key.name(x.GetName())
+		key.id(TypeID(x.splitAt))
+	case Application[T]:
+		// applications are equal when their heads have the same name
+		key.tag('a')
+		key.name(x.c.GetReferred().GetName())
+		node.children = append(node.children, in.Intern(x.c))
+		ts := make([]Monotyped[T], len(x.ts))
+		for i, t := range x.ts {
+			id := in.Intern(t)
+			key.id(id)
+			node.children = append(node.children, id)
+			ts[i] = in.nodes[id].ty
+		}
+		node.ty = Application[T]{x.c, ts}
+	case DependentTypeInstance[T]:
+		key.tag('d')
+		function := in.Intern(x.Application)
+		key.id(function)
+		node.children = append(node.children, function)
+		indexes := make(Indexes[T], len(x.Indexes))
+		for i, index := range x.Indexes {
+			e, t := GetExpression(index), GetType(index)
+			key.name(e.StrictString())
+			if id, ok := in.indexType(&key, t); ok {
+				node.children = append(node.children, id)
+				t = in.nodes[id].ty
+			}
+			indexes[i] = index.MakeJudgment(e, t)
+		}
+		node.ty = Index(in.nodes[function].ty.(Application[T]), indexes...)
+	case Record[T]:
+		key.tag('r')
+		node.ty = x.Map(func(t Monotyped[T]) Monotyped[T] {
+			id := in.Intern(t)
+			node.children = append(node.children, id)
+			return in.nodes[id].ty
+		})
+		for i, field := range x.fields {
+			key.name(field.Label.GetName())
+			key.id(node.children[i])
+		}
+		if x.row != nil {
+			key.tag('|')
+			key.variable(x.row.GetName(), x.row.boundContext)
+		}
+	case Nested[T]:
+		key.tag('n')
+		for _, binder := range x.sigma.typeBinders {
+			key.variable(binder.GetName(), binder.boundContext)
+		}
+		key.tag('.')
+		node.binders = x.sigma.typeBinders
+		if d, ok := x.sigma.bound.(DependentType[T]); ok {
+			for _, j := range d.mapval {
+				key.name(j.expression.StrictString())
+				if id, ok := in.indexType(&key, j.ty); ok {
+					node.children = append(node.children, id)
+				}
+			}
+		}
+		key.tag('.')
+		node.ty = x.Map(func(t Monotyped[T]) Monotyped[T] {
+			id := in.Intern(t)
+			key.id(id)
+			node.children = append(node.children, id)
+			return in.nodes[id].ty
+		})
+	default:
+		key.tag('?')
+		key.name(m.String())
+		node.memoized, node.frees = true, dedupVariables(m.GetFreeVariables(), nil)
+	}
+
+	if id, found := in.ids[key.String()]; found {
+		return id
+	}
+	id := TypeID(len(in.nodes))
+	in.ids[key.String()] = id
+	in.nodes = append(in.nodes, node)
+	return id
+}
+
+// returns the variables free in the monotype w/ ID `id`, each once and in the
+// order they first appear. They are found once per ID; it is NOT safe to
+// modify the slice returned
+func (in *Interner[T]) FreeVariables(id TypeID) []Variable[T] {
+	node := &in.nodes[id]
+	if node.memoized {
+		return node.frees
+	}
+	var frees []Variable[T]
+	for _, child := range node.children {
+		frees = append(frees, in.FreeVariables(child)...)
+	}
+	node.memoized, node.frees = true, dedupVariables(frees, node.binders)
+	return node.frees
+}
+
+// returns `vs` w/o repeated variables and w/o the variables in `bound`
+func dedupVariables[T nameable.Nameable](vs []Variable[T], bound []Variable[T]) []Variable[T] {
+	type variableKey struct {
+		name         string
+		boundContext int32
+	}
+	seen := make(map[variableKey]bool, len(vs)+len(bound))
+	for _, v := range bound {
+		seen[variableKey{v.GetName(), v.boundContext}] = true
+	}
+	out := []Variable[T]{}
+	for _, v := range vs {
+		key := variableKey{v.GetName(), v.boundContext}
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// polytype nested w/in a monotype, e.g., the parameter of
+//
+//	(forall a . a -> a) -> Int
+//
+// Types w/ nested polytypes are called higher-rank types. Values of a nested
+// polytype are used at every instance of it
+type Nested[T nameable.Nameable] struct {
+	sigma Polytype[T]
+}
+
+// nests polytype `sigma` so that it can be used as a monotype
+func Nest[T nameable.Nameable](sigma Polytype[T]) Nested[T] {
+	return Nested[T]{sigma}
+}
+
+// returns the polytype `n` nests
+func (n Nested[T]) GetPolytype() Polytype[T] { return n.sigma }
+
+// Nest(Forall("a").Bind(Function("a", "a"))).String() == "(forall a . (a -> a))"
+func (n Nested[T]) String() string {
+	return "(" + n.sigma.String() + ")"
+}
+
+// syntactic equality; see (Polytype).Equals
+func (n Nested[T]) Equals(t Type[T]) bool {
+	m, ok := t.(Nested[T])
+	return ok && n.sigma.Equals(m.sigma)
+}
+
+func (n Nested[T]) Collect() []T {
+	return n.sigma.Collect()
+}
+
+// returns the name of the type the nested polytype binds
+func (n Nested[T]) GetReferred() T {
+	return GetDependent(n.sigma.bound).GetReferred()
+}
+
+// returns true iff `v` is bound by the nested polytype
+func (n Nested[T]) binds(v Variable[T]) bool {
+	for _, binder := range n.sigma.typeBinders {
+		if varEquals(v, binder) {
+			return true
+		}
+	}
+	return false
+}
+
+// returns variables free in the nested polytype
+func (n Nested[T]) GetFreeVariables() []Variable[T] {
+	frees := []Variable[T]{}
+	for _, v := range n.sigma.bound.GetFreeVariables() {
+		if !n.binds(v) {
+			frees = append(frees, v)
+		}
+	}
+	return frees
+}
+
+// replaces each free `vs[i]` w/ `ms[i]`; variables bound by the nested
+// polytype are not replaced
+func (n Nested[T]) ReplaceDependent(vs []Variable[T], ms []Monotyped[T]) Monotyped[T] {
+	vs2, ms2 := make([]Variable[T], 0, len(vs)), make([]Monotyped[T], 0, len(ms))
+	for i, v := range vs {
+		if !n.binds(v) {
+			vs2, ms2 = append(vs2, v), append(ms2, ms[i])
+		}
+	}
+
+	return n.Map(func(m Monotyped[T]) Monotyped[T] {
+		return m.ReplaceDependent(vs2, ms2)
+	})
+}
+
+// replaces free `v` w/ `m`
+func (n Nested[T]) Replace(v Variable[T], m Monotyped[T]) Monotyped[T] {
+	return n.ReplaceDependent([]Variable[T]{v}, []Monotyped[T]{m})
+}
+
+// applies `f` to the monotype bound by the nested polytype
+func (n Nested[T]) Map(f func(Monotyped[T]) Monotyped[T]) Nested[T] {
+	var bound DependentTyped[T]
+	if d, ok := n.sigma.bound.(DependentType[T]); ok {
+		bound = MakeDependentType(d.mapval, f(d.Function).(TypeFunction[T]))
+	} else {
+		bound = f(n.sigma.bound.(Monotyped[T]))
+	}
+	return Nest(Forall(n.sigma.typeBinders...).Bind(bound))
+}
+
+// like (TypeFunction).Rebuild, but rebuilds the monotype bound by the nested
+// polytype
+func (n Nested[T]) Rebuild(findMono func(Monotyped[T]) Monotyped[T], findKind func(expr.Referable[T]) expr.Referable[T]) Nested[T] {
+	return n.Map(func(m Monotyped[T]) Monotyped[T] {
+		if function, ok := m.(TypeFunction[T]); ok {
+			return function.Rebuild(findMono, findKind)
+		}
+		return findMono(m)
+	})
+}
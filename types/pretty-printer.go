@@ -0,0 +1,420 @@
+package types
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// precedence of what a type is printed as part of; a type is parenthesized iff
+// it binds less tightly than what it is part of
+const (
+	topPrec int = 0  // whole type, or between delimiters
+	appPrec int = 10 // head of an application
+	argPrec int = 11 // argument of an application
+)
+
+// precedence and associativity of infix constants, which bind less tightly
+// than applications; infix constants not listed have precedence 4 and do not
+// associate
+var infixPrecs = map[string]struct {
+	prec       int
+	rightAssoc bool
+}{
+	"->": {1, true},
+	"|":  {2, true},
+	"&":  {3, true},
+}
+
+// names given to type variables, then names given to index variables; when a
+// pool runs out, its names are reused w/ increasing numeric suffixes
+var (
+	typeNamePool  = strings.Split("abcdefghijklmnopqrstuvwxyz", "")
+	indexNamePool = []string{"n", "m", "k", "i", "j"}
+)
+
+// matches names of variables created by NewVar and expr.(*Context).NewVar
+var generatedName = regexp.MustCompile(`\$[0-9]+`)
+
+// returns true iff `name` was created by NewVar or expr.(*Context).NewVar
+func isGenerated(name string) bool {
+	return strings.HasPrefix(name, "$")
+}
+
+type prettyPrinter[T nameable.Nameable] struct {
+	// names type variables are printed w/
+	names map[string]string
+	// names index variables are printed w/
+	indexNames map[string]string
+	// names that cannot be given to variables
+	taken     map[string]bool
+	nextType  int
+	nextIndex int
+}
+
+// returns `t` as a human would write it:
+//   - variables created by NewVar and variables bound by `forall` are renamed
+//     `a`, `b`, `c`, .. in the order they are first printed, and index variables
+//     created by expr.(*Context).NewVar are renamed `n`, `m`, `k`, ..; no
+//     variable is given a name already used in `t`
+//   - skolems (see (*Context).Skolem) keep the names of the variables they
+//     were created for, w/ a numeric suffix when that name is already used
+//   - `forall` binders that do not occur in what they bind are dropped, as are
+//     empty `forall` and `mapval` prefixes
+//   - only parentheses that are needed are printed: applications bind more
+//     tightly than infix constants, `->` associates to the right, and the
+//     indexes of a type are printed w/o their types
+//
+// For example,
+//
+//	Pretty(forall $3 $7 . (($3 -> $7) -> ((List $3) -> (List $7))))
+//		== "forall a b . (a -> b) -> List a -> List b"
+func Pretty[T nameable.Nameable](t Type[T]) string {
+	return PrettyAll(t)[0]
+}
+
+// like Pretty, but a variable that occurs free in more than one of `ts` is
+// given the same name in each, e.g., for the two types of a failed unification
+func PrettyAll[T nameable.Nameable](ts ...Type[T]) []string {
+	p := &prettyPrinter[T]{
+		names:      map[string]string{},
+		indexNames: map[string]string{},
+		taken:      map[string]bool{},
+	}
+	for _, t := range ts {
+		p.reserve(t, map[string]bool{})
+	}
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		out[i] = p.print(t, topPrec)
+	}
+	return out
+}
+
+// returns the `i`-th name of `pool`
+func poolName(pool []string, i int) string {
+	name := pool[i%len(pool)]
+	if suffix := i / len(pool); suffix != 0 {
+		name = name + strconv.Itoa(suffix)
+	}
+	return name
+}
+
+// returns name from `pool` that is not taken, starting from `*next`, and takes
+// it
+func (p *prettyPrinter[T]) fresh(pool []string, next *int) string {
+	name := poolName(pool, *next)
+	for ; p.taken[name]; name = poolName(pool, *next) {
+		*next++
+	}
+	*next++
+	p.taken[name] = true
+	return name
+}
+
+// takes every name in `t` that is printed as it is written: names of
+// constants, of free variables not created by NewVar, and of index variables
+// not created by expr.(*Context).NewVar. `bound` holds the names of the
+// variables bound by the polytypes `t` is in
+func (p *prettyPrinter[T]) reserve(t Type[T], bound map[string]bool) {
+	switch x := t.(type) {
+	case Variable[T]:
+		if name := x.GetName(); !bound[name] && !isGenerated(name) {
+			p.taken[name] = true
+		}
+	case Qualified[T]:
+		inner := p.bind(bound, x.typeBinders)
+		for _, pred := range x.context {
+			for _, param := range pred.params {
+				p.reserve(param, inner)
+			}
+		}
+		p.reserve(x.bound, inner)
+	case Polytype[T]:
+		p.reserve(x.bound, p.bind(bound, x.typeBinders))
+	case Nested[T]:
+		p.reserve(x.sigma, bound)
+	case DependentType[T]:
+		for _, j := range x.mapval {
+			p.reserveIndex(j.expression)
+			p.reserve(j.ty, bound)
+		}
+		p.reserve(x.Function, bound)
+	case DependentTypeInstance[T]:
+		p.reserve(x.Application, bound)
+		for _, index := range x.Indexes {
+			p.reserveIndex(GetExpression(index))
+		}
+	case Application[T]:
+		p.reserve(x.c, bound)
+		for _, param := range x.ts {
+			p.reserve(param, bound)
+		}
+	case Record[T]:
+		for _, field := range x.fields {
+			p.reserve(field.Type, bound)
+		}
+		if x.row != nil {
+			p.reserve(*x.row, bound)
+		}
+	default:
+		for _, name := range t.Collect() {
+			p.taken[name.GetName()] = true
+		}
+	}
+}
+
+func (p *prettyPrinter[T]) reserveIndex(e expr.Expression[T]) {
+	for _, name := range e.Collect() {
+		if !isGenerated(name.GetName()) {
+			p.taken[name.GetName()] = true
+		}
+	}
+}
+
+// returns `bound` w/ the names of `binders` added
+func (p *prettyPrinter[T]) bind(bound map[string]bool, binders []Variable[T]) map[string]bool {
+	inner := make(map[string]bool, len(bound)+len(binders))
+	for name := range bound {
+		inner[name] = true
+	}
+	for _, v := range binders {
+		inner[v.GetName()] = true
+	}
+	return inner
+}
+
+// returns `s` in parentheses iff `prec` is greater than `least`
+func parenthesize(s string, prec, least int) string {
+	if prec > least {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+func (p *prettyPrinter[T]) print(t Type[T], prec int) string {
+	switch x := t.(type) {
+	case Variable[T]:
+		return p.variable(x)
+	case InfixConst[T]:
+		return "(" + x.GetName() + ")"
+	case Qualified[T]:
+		return p.polytype(x.typeBinders, x.context, x.bound, prec)
+	case Polytype[T]:
+		return p.polytype(x.typeBinders, nil, x.bound, prec)
+	case Nested[T]:
+		return p.print(x.sigma, prec)
+	case DependentType[T]:
+		return p.dependent(x, prec)
+	case DependentTypeInstance[T]:
+		return p.instance(x, prec)
+	case Application[T]:
+		return p.application(x, prec)
+	case Record[T]:
+		return p.record(x)
+	default:
+		return t.String()
+	}
+}
+
+// returns name `v` is printed w/, naming `v` if it was created by NewVar and
+// has not been named yet
+func (p *prettyPrinter[T]) variable(v Variable[T]) string {
+	if name, found := p.names[v.GetName()]; found {
+		return name
+	}
+	if v.IsRigid() && !isGenerated(v.String()) {
+		return p.rigid(v)
+	}
+	if !isGenerated(v.GetName()) {
+		return v.GetName()
+	}
+	name := p.fresh(typeNamePool, &p.nextType)
+	p.names[v.GetName()] = name
+	return name
+}
+
+// names skolem `v` after the variable it was created for, e.g., `a`, or, if
+// that name is taken, `a1`, `a2`, ..
+func (p *prettyPrinter[T]) rigid(v Variable[T]) string {
+	name := v.String()
+	for i := 1; p.taken[name]; i++ {
+		name = v.String() + strconv.Itoa(i)
+	}
+	p.taken[name] = true
+	p.names[v.GetName()] = name
+	return name
+}
+
+// returns `forall a1 .. aN . P => bound`; binders that do not occur in `bound`
+// or `context` are dropped
+func (p *prettyPrinter[T]) polytype(binders []Variable[T], context []Predicate[T], bound DependentTyped[T], prec int) string {
+	occurs := map[string]bool{}
+	for _, v := range bound.GetFreeVariables() {
+		occurs[v.GetName()] = true
+	}
+	for _, pred := range context {
+		for _, v := range pred.GetFreeVariables() {
+			occurs[v.GetName()] = true
+		}
+	}
+
+	// binders shadow variables w/ the same name until `bound` is printed
+	shadowed := map[string]string{}
+	var renamed, names []string
+	for _, v := range binders {
+		if !occurs[v.GetName()] {
+			continue
+		}
+		if old, found := p.names[v.GetName()]; found {
+			shadowed[v.GetName()] = old
+		}
+		name := p.fresh(typeNamePool, &p.nextType)
+		p.names[v.GetName()] = name
+		renamed, names = append(renamed, v.GetName()), append(names, name)
+	}
+	if len(names) == 0 && len(context) == 0 {
+		return p.print(bound, prec)
+	}
+	defer func() {
+		for _, name := range renamed {
+			delete(p.names, name)
+		}
+		for name, old := range shadowed {
+			p.names[name] = old
+		}
+	}()
+
+	out := ""
+	switch len(context) {
+	case 0:
+	case 1:
+		out = p.predicate(context[0]) + " => "
+	default:
+		preds := make([]string, len(context))
+		for i, pred := range context {
+			preds[i] = p.predicate(pred)
+		}
+		out = "(" + strings.Join(preds, ", ") + ") => "
+	}
+	out = out + p.print(bound, topPrec)
+	if len(names) == 0 {
+		return parenthesize(out, prec, topPrec)
+	}
+	return parenthesize("forall "+strings.Join(names, " ")+" . "+out, prec, topPrec)
+}
+
+// returns `C t1 .. tN`
+func (p *prettyPrinter[T]) predicate(pred Predicate[T]) string {
+	out := pred.class.GetName()
+	for _, param := range pred.params {
+		out = out + " " + p.print(param, argPrec)
+	}
+	return out
+}
+
+// returns `mapval (x1: X1) .. (xN: XN) . F`
+func (p *prettyPrinter[T]) dependent(d DependentType[T], prec int) string {
+	if len(d.mapval) == 0 {
+		return p.print(d.Function, prec)
+	}
+	judgments := make([]string, len(d.mapval))
+	for i, j := range d.mapval {
+		judgments[i] = "(" + p.index(j.expression) + ": " + p.print(j.ty, topPrec) + ")"
+	}
+	out := "mapval " + strings.Join(judgments, " ") + " . " + p.print(d.Function, topPrec)
+	return parenthesize(out, prec, topPrec)
+}
+
+// returns `e` w/ its index variables created by expr.(*Context).NewVar renamed
+func (p *prettyPrinter[T]) index(e expr.Expression[T]) string {
+	return generatedName.ReplaceAllStringFunc(e.String(), func(generated string) string {
+		if name, found := p.indexNames[generated]; found {
+			return name
+		}
+		name := p.fresh(indexNamePool, &p.nextIndex)
+		p.indexNames[generated] = name
+		return name
+	})
+}
+
+// returns `(F t1 .. tN; e1 .. eM)`, or `[t1 .. tN; e1 .. eM]` for an enclosing
+// constant `[]`
+func (p *prettyPrinter[T]) instance(dti DependentTypeInstance[T], prec int) string {
+	if len(dti.Indexes) == 0 {
+		return p.application(dti.Application, prec)
+	}
+	indexes := make([]string, len(dti.Indexes))
+	for i, index := range dti.Indexes {
+		indexes[i] = p.index(GetExpression(index))
+	}
+	lclose, rclose := "(", ")"
+	app := ""
+	if ec, ok := dti.Application.c.(EnclosingConst[T]); ok {
+		lclose, rclose = ec.SplitString()
+		app = p.enclosed(dti.Application.ts)
+	} else {
+		app = p.application(dti.Application, topPrec)
+	}
+	return lclose + app + "; " + strings.Join(indexes, " ") + rclose
+}
+
+// returns `ts` as they are printed between the delimiters of an enclosing
+// constant
+func (p *prettyPrinter[T]) enclosed(ts []Monotyped[T]) string {
+	if len(ts) == 1 {
+		return p.print(ts[0], topPrec)
+	}
+	params := make([]string, len(ts))
+	for i, t := range ts {
+		params[i] = p.print(t, argPrec)
+	}
+	return strings.Join(params, " ")
+}
+
+func (p *prettyPrinter[T]) application(a Application[T], prec int) string {
+	if len(a.ts) == 0 {
+		return p.print(a.c, prec)
+	}
+	if ec, ok := a.c.(EnclosingConst[T]); ok {
+		lclose, rclose := ec.SplitString()
+		return lclose + p.enclosed(a.ts) + rclose
+	}
+	if ic, ok := a.c.(InfixConst[T]); ok && len(a.ts) == 2 {
+		op, found := infixPrecs[ic.GetName()]
+		if !found {
+			op.prec = 4
+		}
+		leftPrec, rightPrec := op.prec+1, op.prec+1
+		if op.rightAssoc {
+			rightPrec = op.prec
+		}
+		out := p.print(a.ts[0], leftPrec) + " " + ic.GetName() + " " + p.print(a.ts[1], rightPrec)
+		return parenthesize(out, prec, op.prec)
+	}
+
+	out := p.print(a.c, appPrec)
+	for _, t := range a.ts {
+		out = out + " " + p.print(t, argPrec)
+	}
+	return parenthesize(out, prec, appPrec)
+}
+
+// returns `{l1: t1, .., lN: tN | r}`
+func (p *prettyPrinter[T]) record(r Record[T]) string {
+	fields := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		fields[i] = field.Label.GetName() + ": " + p.print(field.Type, topPrec)
+	}
+	out := strings.Join(fields, ", ")
+	if r.row != nil {
+		if len(fields) == 0 {
+			return "{" + p.variable(*r.row) + "}"
+		}
+		out = out + " | " + p.variable(*r.row)
+	}
+	return "{" + out + "}"
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	str "github.com/petersalex27/yew-packages/stringable"
+)
+
+// class constraint on one or more monotypes, e.g.,
+//
+//	Eq a
+//	Convert a (List b)
+type Predicate[T nameable.Nameable] struct {
+	class  T
+	params []Monotyped[T]
+}
+
+// creates predicate `class params[0] .. params[N]`
+func Pred[T nameable.Nameable](class T, params ...Monotyped[T]) Predicate[T] {
+	return Predicate[T]{class: class, params: params}
+}
+
+// returns name of class predicate constrains its params by
+func (p Predicate[T]) GetClass() T { return p.class }
+
+// returns the same slice of monotypes that `p` has access to; it is NOT safe
+// to modify the slice returned
+func (p Predicate[T]) GetParams() []Monotyped[T] { return p.params }
+
+// Pred("Eq", Apply("List", Var("a"))).String() == "Eq (List a)"
+func (p Predicate[T]) String() string {
+	if len(p.params) == 0 {
+		return p.class.GetName()
+	}
+	return p.class.GetName() + " " + str.Join(p.params, str.String(" "))
+}
+
+// syntactic equality of predicates
+func (p Predicate[T]) Equals(q Predicate[T]) bool {
+	if p.class.GetName() != q.class.GetName() || len(p.params) != len(q.params) {
+		return false
+	}
+	for i, param := range p.params {
+		if !param.Equals(q.params[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// applies `f` to each param of `p`
+func (p Predicate[T]) Map(f func(Monotyped[T]) Monotyped[T]) Predicate[T] {
+	return Predicate[T]{class: p.class, params: fun.FMap(p.params, f)}
+}
+
+// replaces each `vs[i]` in the params of `p` w/ `with[i]`
+func (p Predicate[T]) ReplaceDependent(vs []Variable[T], with []Monotyped[T]) Predicate[T] {
+	return p.Map(func(m Monotyped[T]) Monotyped[T] {
+		return m.ReplaceDependent(vs, with)
+	})
+}
+
+// returns free variables in params of `p`
+func (p Predicate[T]) GetFreeVariables() []Variable[T] {
+	vs := []Variable[T]{}
+	for _, param := range p.params {
+		vs = append(vs, param.GetFreeVariables()...)
+	}
+	return vs
+}
+
+func (p Predicate[T]) Collect() []T {
+	res := []T{p.class}
+	for _, param := range p.params {
+		res = append(res, param.Collect()...)
+	}
+	return res
+}
+
+// polytype whose bound variables are constrained by classes. Written in its
+// most general form, qualified types have the form
+//
+//	(forall t1 t2 ...) . (P1, P2, ...) => T
+//
+// where each Pi is a predicate (see Predicate) and the rest is a polytype (see
+// Polytype)
+type Qualified[T nameable.Nameable] struct {
+	Polytype[T]
+	context []Predicate[T]
+}
+
+// constrains the variables bound by `sigma` w/ the predicates in `context`
+func Qualify[T nameable.Nameable](sigma Polytype[T], context ...Predicate[T]) Qualified[T] {
+	return Qualified[T]{Polytype: sigma, context: context}
+}
+
+// returns the same slice of predicates that `q` has access to; it is NOT safe
+// to modify the slice returned
+func (q Qualified[T]) GetContext() []Predicate[T] { return q.context }
+
+// returns polytype constrained by `q`
+func (q Qualified[T]) GetPolytype() Polytype[T] { return q.Polytype }
+
+// Qualify(Forall("a").Bind(Function("a", Var("a"))), Pred("Eq", Var("a"))).String()
+//
+//	== "forall a . Eq a => (a -> a)"
+func (q Qualified[T]) String() string {
+	context := ""
+	switch len(q.context) {
+	case 0:
+	case 1:
+		context = q.context[0].String() + " => "
+	default:
+		context = "(" + str.Join(q.context, str.String(", ")) + ") => "
+	}
+
+	if len(q.typeBinders) == 0 {
+		return context + q.bound.String()
+	}
+	return "forall " +
+		str.Join(q.typeBinders, str.String(" ")) +
+		" . " +
+		context +
+		q.bound.String()
+}
+
+func (q Qualified[T]) Collect() []T {
+	res := q.Polytype.Collect()
+	for _, p := range q.context {
+		res = append(res, p.Collect()...)
+	}
+	return res
+}
+
+// syntactic equality; see (Polytype).Equals
+func (q Qualified[T]) Equals(t Type[T]) bool {
+	r, ok := t.(Qualified[T])
+	if !ok || len(q.context) != len(r.context) || !q.Polytype.Equals(r.Polytype) {
+		return false
+	}
+
+	for i, p := range q.context {
+		if !p.Equals(r.context[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/fun"
+	"github.com/petersalex27/yew-packages/nameable"
+	str "github.com/petersalex27/yew-packages/stringable"
+)
+
+// type of a record's field, e.g., `name: String`
+type FieldType[T nameable.Nameable] struct {
+	Label T
+	Type  Monotyped[T]
+}
+
+// creates field type `label: ty`
+func Field[T nameable.Nameable](label T, ty Monotyped[T]) FieldType[T] {
+	return FieldType[T]{label, ty}
+}
+
+func (f FieldType[T]) String() string {
+	return f.Label.GetName() + ": " + f.Type.String()
+}
+
+// record type, a row of field types, e.g.,
+//
+//	{age: Int, name: String}
+//
+// Records are extensible when their row ends w/ a row variable that stands
+// for the rest of the fields, e.g., the record type
+//
+//	{name: String | r}
+//
+// is the type of all records w/ a field `name: String`. Fields are kept in
+// order of their labels
+type Record[T nameable.Nameable] struct {
+	fields []FieldType[T]
+	// nil iff record is closed
+	row *Variable[T]
+}
+
+// creates a record type w/ `fields`. When `rest` is a record type, its fields
+// are added to the record type returned and its row variable ends the record
+// type returned; when `rest` is a variable, it ends the record type returned;
+// when `rest` is nil, the record type returned is closed. Any other `rest` is
+// not a row, and RecordOf panics
+func RecordOf[T nameable.Nameable](fields []FieldType[T], rest Monotyped[T]) Record[T] {
+	out := Record[T]{fields: make([]FieldType[T], len(fields))}
+	copy(out.fields, fields)
+	switch r := rest.(type) {
+	case Record[T]:
+		out.fields = append(out.fields, r.fields...)
+		out.row = r.row
+	case Variable[T]:
+		out.row = &r
+	case nil:
+	default:
+		panic("illegal argument: " + rest.String() + " is not a row")
+	}
+
+	sort.SliceStable(out.fields, func(i, j int) bool {
+		return out.fields[i].Label.GetName() < out.fields[j].Label.GetName()
+	})
+	return out
+}
+
+// creates closed record type w/ `fields`
+func Closed[T nameable.Nameable](fields ...FieldType[T]) Record[T] {
+	return RecordOf[T](fields, nil)
+}
+
+// returns the same slice of fields that `r` has access to; it is NOT safe to
+// modify the slice returned
+func (r Record[T]) GetFields() []FieldType[T] { return r.fields }
+
+// returns row variable ending `r`; second return value is false iff `r` is
+// closed
+func (r Record[T]) GetRow() (row Variable[T], extensible bool) {
+	if r.row == nil {
+		return row, false
+	}
+	return *r.row, true
+}
+
+// returns the type of the field labeled `label`
+func (r Record[T]) Get(label string) (ty Monotyped[T], found bool) {
+	for _, field := range r.fields {
+		if field.Label.GetName() == label {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+// RecordOf([]FieldType{Field("name", Con("String"))}, Var("r")).String()
+//
+//	== "{name: String | r}"
+func (r Record[T]) String() string {
+	fields := str.Join(r.fields, str.String(", "))
+	if r.row != nil {
+		if len(r.fields) == 0 {
+			return "{" + r.row.String() + "}"
+		}
+		fields = fields + " | " + r.row.String()
+	}
+	return "{" + fields + "}"
+}
+
+func (r Record[T]) Equals(t Type[T]) bool {
+	r2, ok := t.(Record[T])
+	if !ok || len(r.fields) != len(r2.fields) || (r.row == nil) != (r2.row == nil) {
+		return false
+	}
+	if r.row != nil && !r.row.Equals(*r2.row) {
+		return false
+	}
+	for i, field := range r.fields {
+		other := r2.fields[i]
+		if field.Label.GetName() != other.Label.GetName() || !field.Type.Equals(other.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Record[T]) Collect() []T {
+	res := []T{}
+	for _, field := range r.fields {
+		res = append(res, field.Label)
+		res = append(res, field.Type.Collect()...)
+	}
+	if r.row != nil {
+		res = append(res, r.row.Collect()...)
+	}
+	return res
+}
+
+// records are not named; the zero value of T is returned
+func (r Record[T]) GetReferred() (name T) { return }
+
+func (r Record[T]) GetFreeVariables() []Variable[T] {
+	vs := []Variable[T]{}
+	for _, field := range r.fields {
+		vs = append(vs, field.Type.GetFreeVariables()...)
+	}
+	if r.row != nil {
+		vs = append(vs, r.row.GetFreeVariables()...)
+	}
+	return vs
+}
+
+// applies `f` to the type of each field and to the row variable of `r`; see
+// RecordOf
+func (r Record[T]) Map(f func(Monotyped[T]) Monotyped[T]) Record[T] {
+	fields := fun.FMap(r.fields, func(field FieldType[T]) FieldType[T] {
+		return FieldType[T]{field.Label, f(field.Type)}
+	})
+	var rest Monotyped[T]
+	if r.row != nil {
+		rest = f(*r.row)
+	}
+	return RecordOf(fields, rest)
+}
+
+func (r Record[T]) Replace(v Variable[T], m Monotyped[T]) Monotyped[T] {
+	return r.Map(func(t Monotyped[T]) Monotyped[T] { return t.Replace(v, m) })
+}
+
+func (r Record[T]) ReplaceDependent(vs []Variable[T], ms []Monotyped[T]) Monotyped[T] {
+	return r.Map(func(t Monotyped[T]) Monotyped[T] { return t.ReplaceDependent(vs, ms) })
+}
+
+// like (TypeFunction).Rebuild, but rebuilds the types of the fields and the row
+// of `r`
+func (r Record[T]) Rebuild(findMono func(Monotyped[T]) Monotyped[T], findKind func(expr.Referable[T]) expr.Referable[T]) Record[T] {
+	return r.Map(func(t Monotyped[T]) Monotyped[T] {
+		if function, ok := t.(TypeFunction[T]); ok {
+			return function.Rebuild(findMono, findKind)
+		}
+		return findMono(t)
+	})
+}
@@ -0,0 +1,777 @@
+package types
+
+import (
+	"strconv"
+	"unicode"
+
+	"github.com/petersalex27/yew-packages/expr"
+	"github.com/petersalex27/yew-packages/nameable"
+)
+
+// error found while parsing a type; `Line` and `Column` (both counted from 1)
+// locate the token the error was found at
+type ParseError struct {
+	Line, Column int
+	Message      string
+}
+
+func (e ParseError) Error() string {
+	return strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column) + ": " + e.Message
+}
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	numberToken
+	operatorToken
+	// one of `( ) [ ] { } , ; : .`
+	punctToken
+	endToken
+)
+
+type token struct {
+	kind         tokenKind
+	text         string
+	line, column int
+}
+
+// returns `tok` as it is written in error messages
+func (tok token) describe() string {
+	if tok.kind == endToken {
+		return "end of input"
+	}
+	return "`" + tok.text + "`"
+}
+
+const punctuation string = "()[]{},;:."
+
+func isOperatorRune(r rune) bool {
+	switch r {
+	case '-', '>', '<', '=', '|', '&', '*', '+', '!', '%', '^', '~', '/', '?', '@', '#', '\\':
+		return true
+	}
+	return false
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || r == '$'
+}
+
+func isIdentRune(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '\''
+}
+
+// splits `source` into tokens, ending w/ a token of kind endToken
+func lexType(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	line, column := 1, 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if r == '\n' {
+			line, column, i = line+1, 1, i+1
+			continue
+		} else if unicode.IsSpace(r) {
+			column, i = column+1, i+1
+			continue
+		}
+
+		start, kind := i, punctToken
+		switch {
+		case isIdentStart(r):
+			kind = identToken
+			for i++; i < len(runes) && isIdentRune(runes[i]); i++ {
+			}
+		case unicode.IsDigit(r):
+			kind = numberToken
+			for i++; i < len(runes) && unicode.IsDigit(runes[i]); i++ {
+			}
+		case isOperatorRune(r):
+			kind = operatorToken
+			for i++; i < len(runes) && isOperatorRune(runes[i]); i++ {
+			}
+		default:
+			found := false
+			for _, p := range punctuation {
+				found = found || p == r
+			}
+			if !found {
+				return nil, ParseError{line, column, "unexpected character `" + string(r) + "`"}
+			}
+			i++
+		}
+		tokens = append(tokens, token{kind, string(runes[start:i]), line, column})
+		column += i - start
+	}
+	return append(tokens, token{endToken, "", line, column}), nil
+}
+
+type typeParser[T nameable.Nameable] struct {
+	// names variables and constants, and creates the variables typing indexes
+	// written w/o types
+	cxt      *Context[T]
+	makeName func(string) T
+	tokens   []token
+	pos      int
+	// index variables bound by enclosing `mapval`s, innermost last
+	mapval []TypeJudgment[T, expr.Variable[T]]
+	// infix constant that ends a type instead of being applied, e.g., the `|`
+	// ending the fields of a record; empty between delimiters
+	stop string
+}
+
+// parses `source` as a type, naming its variables and constants w/ `makeName`.
+// Types are read as (*Type).String writes them, so that parsing what String
+// returns gives back an equal type, e.g.,
+//
+//	forall a . mapval (n: Uint) . (((Array a); (n: Uint)) -> a)
+//
+// Some parentheses can be left out, though: applications bind more tightly
+// than infix constants, `->` binds least tightly and associates to the right,
+// `|` and `&` associate to the right, and other infix constants do not
+// associate. As String writes them, the types following an infix constant
+// are all its arguments, e.g., `(a -> b c)` applies `->` to `a`, `b`, and `c`,
+// so an application right of an infix constant must be parenthesized. The
+// index of a dependent type instance can be written w/o its type, e.g.,
+//
+//	forall a . mapval (n: Uint) . (Array a; n) -> a
+//
+// An index variable bound by an enclosing `mapval` has the type it is bound
+// w/. Any other index w/o a type is typed by a new type variable (see
+// FreeJudge), but only where String writes such indexes: after a delimited
+// type, as in `[Int; n]` and `((Array a); n)`. A parenthesized application bound by `mapval` is the dependent
+// type function indexed by the variables `mapval` binds, as in
+//
+//	mapval (n: Uint) . (Array a)
+//
+// Names beginning w/ a lowercase letter, `_`, or `$` are variables; all
+// other names are constants. String writes a constant w/ such a name, e.g.,
+// `x` of `(Type x)`, just like a variable, so it is read back as a variable. Between `[` and `]`, and between `{` and `}`
+// unless they enclose a record type, types are arguments of the enclosing
+// constant `[]` or `{}`, e.g., `[Type a]` applies `[]` to `Type` and `a`;
+// `{}` is only applied to types in arguments, e.g., `[{A}]`, and a type that
+// is not an argument and begins w/ `{` is a record type.
+// `[]` and an infix constant in parentheses, e.g., `(->)`, are applications
+// to no types. On failure, a ParseError is returned
+func Parse[T nameable.Nameable](makeName func(string) T, source string) (Type[T], error) {
+	return NewContext[T]().SetNameMaker(makeName).Parse(source)
+}
+
+// parses `source` as a type, naming its variables and constants w/ the name
+// maker of `cxt` and creating the type variables of indexes written w/o types
+// w/ `cxt`; see Parse
+func (cxt *Context[T]) Parse(source string) (Type[T], error) {
+	tokens, e := lexType(source)
+	if e != nil {
+		return nil, e
+	}
+	p := &typeParser[T]{cxt: cxt, makeName: cxt.makeName, tokens: tokens}
+	t, e := p.sigma()
+	if e != nil {
+		return nil, e
+	}
+	if tok := p.peek(); tok.kind != endToken {
+		return nil, p.unexpected(tok, "end of input")
+	}
+	return t, nil
+}
+
+func (p *typeParser[T]) peek() token { return p.tokens[p.pos] }
+
+func (p *typeParser[T]) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != endToken {
+		p.pos++
+	}
+	return tok
+}
+
+// returns true iff the next token is `text` and not a name
+func (p *typeParser[T]) at(text string) bool {
+	tok := p.peek()
+	return tok.kind != identToken && tok.text == text
+}
+
+// returns true iff the next token is keyword `keyword`
+func (p *typeParser[T]) atKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == identToken && tok.text == keyword
+}
+
+func (p *typeParser[T]) fail(tok token, message string) error {
+	return ParseError{tok.line, tok.column, message}
+}
+
+func (p *typeParser[T]) unexpected(tok token, expected string) error {
+	return p.fail(tok, "expected "+expected+" but found "+tok.describe())
+}
+
+// consumes next token, which must be `text`
+func (p *typeParser[T]) expect(text string) error {
+	if !p.at(text) {
+		return p.unexpected(p.peek(), "`"+text+"`")
+	}
+	p.next()
+	return nil
+}
+
+func isKeyword(name string) bool {
+	return name == "forall" || name == "mapval"
+}
+
+func isVariableName(name string) bool {
+	r := []rune(name)[0]
+	return unicode.IsLower(r) || r == '_' || r == '$'
+}
+
+// consumes a variable's name
+func (p *typeParser[T]) variableName() (token, error) {
+	tok := p.next()
+	if tok.kind != identToken || isKeyword(tok.text) || !isVariableName(tok.text) {
+		return tok, p.unexpected(tok, "a variable")
+	}
+	return tok, nil
+}
+
+// parses a type, which may bind variables and be constrained by classes
+//
+//	σ ::= forall a1 .. aN . C => D | C => D | D
+func (p *typeParser[T]) sigma() (Type[T], error) {
+	var binders []Variable[T]
+	if p.atKeyword("forall") {
+		p.next()
+		for !p.at(".") {
+			tok, e := p.variableName()
+			if e != nil {
+				return nil, e
+			}
+			binders = append(binders, Var(p.makeName(tok.text)))
+		}
+		if len(binders) == 0 {
+			return nil, p.unexpected(p.peek(), "a variable")
+		}
+		p.next()
+	}
+
+	context, qualified := p.context()
+	bound, e := p.dependent()
+	if e != nil {
+		return nil, e
+	}
+
+	if qualified {
+		return Qualify(Forall(binders...).Bind(bound), context...), nil
+	} else if len(binders) != 0 {
+		return Forall(binders...).Bind(bound), nil
+	}
+	return bound, nil
+}
+
+// parses `C =>` or `(C1, .., CN) =>` if it comes next; otherwise, nothing is
+// consumed and `qualified` is false
+func (p *typeParser[T]) context() (context []Predicate[T], qualified bool) {
+	start := p.pos
+	if p.at("(") {
+		p.next()
+		for {
+			pred, e := p.predicate()
+			if e != nil {
+				break
+			}
+			context = append(context, pred)
+			if !p.at(",") {
+				qualified = p.expect(")") == nil
+				break
+			}
+			p.next()
+		}
+	} else if pred, e := p.predicate(); e == nil {
+		context, qualified = []Predicate[T]{pred}, true
+	}
+
+	if qualified = qualified && p.at("=>"); !qualified {
+		p.pos = start
+		return nil, false
+	}
+	p.next()
+	return context, true
+}
+
+// parses a class constraint
+//
+//	P ::= C t1 .. tN
+func (p *typeParser[T]) predicate() (Predicate[T], error) {
+	tok := p.next()
+	if tok.kind != identToken || isKeyword(tok.text) || isVariableName(tok.text) {
+		return Predicate[T]{}, p.unexpected(tok, "a class")
+	}
+	var params []Monotyped[T]
+	for p.atAtom() {
+		param, e := p.argument()
+		if e != nil {
+			return Predicate[T]{}, e
+		}
+		params = append(params, param)
+	}
+	return Pred(p.makeName(tok.text), params...), nil
+}
+
+// parses a type that may depend on indexes
+//
+//	D ::= mapval (x1: X1) .. (xN: XN) . F | t
+func (p *typeParser[T]) dependent() (DependentTyped[T], error) {
+	if !p.atKeyword("mapval") {
+		return p.monotype(0)
+	}
+
+	p.next()
+	var mapval []TypeJudgment[T, expr.Variable[T]]
+	for !p.at(".") {
+		judgment, e := p.binding()
+		if e != nil {
+			return nil, e
+		}
+		mapval = append(mapval, judgment)
+	}
+	if len(mapval) == 0 {
+		return nil, p.unexpected(p.peek(), "`(`")
+	}
+	p.next()
+
+	outer := p.mapval
+	p.mapval = append(append([]TypeJudgment[T, expr.Variable[T]]{}, outer...), mapval...)
+	defer func() { p.mapval = outer }()
+
+	tok := p.peek()
+	m, e := p.monotype(0)
+	if e != nil {
+		return nil, e
+	}
+	if app, ok := m.(Application[T]); ok && !isInfix(app) {
+		// dependent type function, e.g., `Array a` of `(Array a; n)`
+		m = Index(app)
+	}
+	function, ok := m.(TypeFunction[T])
+	if !ok {
+		return nil, p.fail(tok, "expected an application after `mapval`")
+	}
+	return MakeDependentType(mapval, function), nil
+}
+
+// parses an index variable bound by `mapval`
+//
+//	(x: X)
+func (p *typeParser[T]) binding() (TypeJudgment[T, expr.Variable[T]], error) {
+	var judgment TypeJudgment[T, expr.Variable[T]]
+	if e := p.expect("("); e != nil {
+		return judgment, e
+	}
+	tok, e := p.variableName()
+	if e != nil {
+		return judgment, e
+	}
+	if e = p.expect(":"); e != nil {
+		return judgment, e
+	}
+	ty, e := p.monotype(0)
+	if e != nil {
+		return judgment, e
+	}
+	return Judgment(expr.Var(p.makeName(tok.text)), Type[T](ty)), p.expect(")")
+}
+
+// parses an infix application of constants w/ precedences of at least
+// `least` (see infixPrecs)
+//
+//	t ::= t0 op t1 .. tN | F t1 .. tN
+func (p *typeParser[T]) monotype(least int) (Monotyped[T], error) {
+	return p.infix(least, p.application)
+}
+
+// like monotype, but `operand` parses the type left of the first infix
+// constant; the types right of each infix constant are atoms (see atom)
+func (p *typeParser[T]) infix(least int, operand func() (Monotyped[T], error)) (Monotyped[T], error) {
+	left, e := operand()
+	if e != nil {
+		return nil, e
+	}
+
+	// precedence of the last infix constant that does not associate
+	nonAssoc := -1
+	for {
+		tok := p.peek()
+		if tok.kind != operatorToken || tok.text == "=>" || tok.text == p.stop {
+			return left, nil
+		}
+		op, found := infixPrecs[tok.text]
+		if !found {
+			op.prec = 4
+		}
+		if op.prec < least {
+			return left, nil
+		} else if op.prec == nonAssoc {
+			return nil, p.fail(tok, "`"+tok.text+"` does not associate; add parentheses")
+		}
+
+		p.next()
+		rightLeast := op.prec + 1
+		if op.rightAssoc {
+			rightLeast = op.prec
+		}
+		right, e := p.infix(rightLeast, p.operand)
+		if e != nil {
+			return nil, e
+		}
+		args := []Monotyped[T]{left, right}
+		for p.atAtom() {
+			arg, e := p.argument()
+			if e != nil {
+				return nil, e
+			}
+			args = append(args, arg)
+		}
+		left = Apply[T](MakeInfixConst(p.makeName(tok.text)), args...)
+		if !found {
+			nonAssoc = op.prec
+		}
+	}
+}
+
+// parses an atom right of an infix constant
+func (p *typeParser[T]) operand() (Monotyped[T], error) {
+	if !p.atAtom() {
+		return nil, p.unexpected(p.peek(), "a type")
+	}
+	return p.atom()
+}
+
+// returns true iff `app` applies an infix constant
+func isInfix[T nameable.Nameable](app Application[T]) bool {
+	_, ok := app.c.(InfixConst[T])
+	return ok
+}
+
+// parses an application, or the type applied if there are no arguments
+//
+//	F t1 .. tN
+func (p *typeParser[T]) application() (Monotyped[T], error) {
+	if !p.atAtom() {
+		return nil, p.unexpected(p.peek(), "a type")
+	}
+	head, e := p.atom()
+	if e != nil {
+		return nil, e
+	}
+	var params []Monotyped[T]
+	for p.atAtom() {
+		param, e := p.argument()
+		if e != nil {
+			return nil, e
+		}
+		params = append(params, param)
+	}
+	if len(params) == 0 {
+		return head, nil
+	}
+	return Apply(head, params...), nil
+}
+
+// returns true iff the next token begins a type that is not an application
+func (p *typeParser[T]) atAtom() bool {
+	tok := p.peek()
+	if tok.kind == identToken {
+		return !isKeyword(tok.text)
+	}
+	return p.at("(") || p.at("[") || p.at("{")
+}
+
+// parses an atom that is not an argument (see argument)
+func (p *typeParser[T]) atom() (Monotyped[T], error) {
+	return p.atomAt(false)
+}
+
+// parses an atom that is an argument of a type or class. Only arguments can be
+// applications of `{}`, e.g., `{A}` of `[{A}]`
+func (p *typeParser[T]) argument() (Monotyped[T], error) {
+	return p.atomAt(true)
+}
+
+func (p *typeParser[T]) atomAt(argument bool) (Monotyped[T], error) {
+	tok := p.next()
+	if tok.kind == punctToken {
+		stop := p.stop
+		p.stop = ""
+		defer func() { p.stop = stop }()
+	}
+	switch {
+	case tok.kind == identToken && isVariableName(tok.text):
+		return Var(p.makeName(tok.text)), nil
+	case tok.kind == identToken:
+		return MakeConst(p.makeName(tok.text)), nil
+	case tok.text == "(":
+		return p.parenthesized(tok)
+	case tok.text == "[":
+		return p.enclosed()
+	case tok.text == "{" && argument:
+		return p.braced()
+	case tok.text == "{":
+		return p.record()
+	}
+	return nil, p.unexpected(tok, "a type")
+}
+
+// parses what follows `open`, the `(` beginning one of
+//
+//	(op)    (forall a1 .. aN . D)    (t)    (F t1 .. tN; e1 .. eM)
+func (p *typeParser[T]) parenthesized(open token) (Monotyped[T], error) {
+	if tok := p.peek(); tok.kind == operatorToken && p.tokens[p.pos+1].text == ")" {
+		p.pos += 2
+		return Application[T]{c: MakeInfixConst(p.makeName(tok.text))}, nil
+	}
+
+	if p.atKeyword("forall") {
+		sigma, e := p.sigma()
+		if e != nil {
+			return nil, e
+		}
+		poly, ok := sigma.(Polytype[T])
+		if !ok {
+			return nil, p.fail(open, "nested polytypes cannot be constrained by classes")
+		}
+		return Nest(poly), p.expect(")")
+	}
+
+	delimited := p.at("(") && p.tokens[p.closing(p.pos)+1].text == ";"
+	m, e := p.monotype(0)
+	if e != nil {
+		return nil, e
+	}
+	if !p.at(";") {
+		return m, p.expect(")")
+	}
+	p.next()
+	indexes, e := p.indexes(")", delimited)
+	if e != nil {
+		return nil, e
+	}
+	return Index(Apply[T](m), indexes...), nil
+}
+
+// parses what follows the `[` of `[t1 .. tN]` or `[t1 .. tN; e1 .. eM]`
+func (p *typeParser[T]) enclosed() (Monotyped[T], error) {
+	return p.enclosedBy(MakeEnclosingConst(1, p.makeName("[]")), "]")
+}
+
+// parses the types enclosing constant `enclosing` is applied to, up to and
+// including `close`, and the indexes that follow them
+func (p *typeParser[T]) enclosedBy(enclosing EnclosingConst[T], close string) (Monotyped[T], error) {
+	var params []Monotyped[T]
+	for p.atAtom() {
+		param, e := p.argument()
+		if e != nil {
+			return nil, e
+		}
+		params = append(params, param)
+	}
+	app := Application[T]{c: enclosing, ts: params}
+	if len(params) == 0 || !p.at(";") {
+		return app, p.expect(close)
+	}
+	p.next()
+	indexes, e := p.indexes(close, true)
+	if e != nil {
+		return nil, e
+	}
+	return Index(app, indexes...), nil
+}
+
+// parses what follows a `{`, which begins a record type (see record) unless it
+// begins an application of the enclosing constant `{}`, e.g., `{A}`
+func (p *typeParser[T]) braced() (Monotyped[T], error) {
+	first, second := p.peek(), p.tokens[p.pos+1]
+	isRecord := p.at("}") ||
+		(first.kind == identToken && second.text == ":") ||
+		(first.kind == identToken && isVariableName(first.text) && !isKeyword(first.text) && second.text == "}")
+	if !isRecord {
+		return p.enclosedBy(MakeEnclosingConst(1, p.makeName("{}")), "}")
+	}
+	return p.record()
+}
+
+// parses what follows the `{` of `{l1: t1, .., lN: tN}`, `{l1: t1, .., lN: tN
+// | r}`, or `{r}`. The type of a field ends at a `|` that is not between
+// delimiters
+func (p *typeParser[T]) record() (Monotyped[T], error) {
+	if p.at("}") {
+		p.next()
+		return Closed[T](), nil
+	}
+	if tok := p.peek(); tok.kind == identToken && p.tokens[p.pos+1].text == "}" {
+		row, e := p.variableName()
+		if e != nil {
+			return nil, e
+		}
+		p.next()
+		return RecordOf[T](nil, Var(p.makeName(row.text))), nil
+	}
+
+	var fields []FieldType[T]
+	for {
+		label := p.next()
+		if label.kind != identToken {
+			return nil, p.unexpected(label, "a label")
+		}
+		if e := p.expect(":"); e != nil {
+			return nil, e
+		}
+		p.stop = "|"
+		ty, e := p.monotype(0)
+		p.stop = ""
+		if e != nil {
+			return nil, e
+		}
+		fields = append(fields, Field(p.makeName(label.text), ty))
+		if !p.at(",") {
+			break
+		}
+		p.next()
+	}
+
+	var rest Monotyped[T]
+	if p.at("|") {
+		p.next()
+		row, e := p.variableName()
+		if e != nil {
+			return nil, e
+		}
+		rest = Var(p.makeName(row.text))
+	}
+	return RecordOf(fields, rest), p.expect("}")
+}
+
+// returns the position of the token closing the delimiter at `pos`
+func (p *typeParser[T]) closing(pos int) int {
+	depth := 0
+	for ; p.tokens[pos].kind != endToken; pos++ {
+		switch tok := p.tokens[pos]; {
+		case tok.kind != punctToken:
+		case tok.text == "(" || tok.text == "[" || tok.text == "{":
+			depth++
+		case tok.text == ")" || tok.text == "]" || tok.text == "}":
+			if depth--; depth == 0 {
+				return pos
+			}
+		}
+	}
+	return pos
+}
+
+// parses the indexes of a dependent type instance up to and including `close`.
+// Indexes w/o types that are not bound by `mapval` are allowed iff `free`
+func (p *typeParser[T]) indexes(close string, free bool) ([]ExpressionJudgment[T, expr.Referable[T]], error) {
+	var indexes []ExpressionJudgment[T, expr.Referable[T]]
+	for !p.at(close) {
+		index, e := p.index(free)
+		if e != nil {
+			return nil, e
+		}
+		indexes = append(indexes, index)
+	}
+	if len(indexes) == 0 {
+		return nil, p.unexpected(p.peek(), "an index")
+	}
+	p.next()
+	return indexes, nil
+}
+
+// parses an index of a dependent type instance
+//
+//	(e: X) | (e) | x
+//
+// where an index w/o a type has the type of the `mapval` binding it, if any,
+// and is otherwise free iff `free`
+func (p *typeParser[T]) index(free bool) (ExpressionJudgment[T, expr.Referable[T]], error) {
+	tok := p.peek()
+	var e expr.Expression[T]
+	var err error
+	if p.at("(") {
+		p.next()
+		if e, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if p.at(":") {
+			p.next()
+			ty, err := p.monotype(0)
+			if err != nil {
+				return nil, err
+			}
+			index := Judgment(asReferable(e), Type[T](ty))
+			return index, p.expect(")")
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+	} else if e, err = p.expressionAtom(); err != nil {
+		return nil, err
+	}
+
+	if v, ok := e.(expr.Variable[T]); ok {
+		for i := len(p.mapval) - 1; i >= 0; i-- {
+			if p.mapval[i].expression.GetReferred().GetName() == v.GetReferred().GetName() {
+				return Judgment(expr.Referable[T](v), p.mapval[i].ty), nil
+			}
+		}
+	}
+	if !free {
+		return nil, p.fail(tok, "index "+e.String()+" needs a type, e.g., ("+e.String()+": Uint)")
+	}
+	return FreeJudge(p.cxt, asReferable(e)), nil
+}
+
+// returns `e` if it is referable; otherwise, `e` is computed
+func asReferable[T nameable.Nameable](e expr.Expression[T]) expr.Referable[T] {
+	if r, ok := e.(expr.Referable[T]); ok {
+		return r
+	}
+	return expr.Compute(e)
+}
+
+// parses the expression of an index: an application of names, numbers, and
+// operators (e.g., `(+ n) 1`) up to a `:` or `)`
+func (p *typeParser[T]) expression() (expr.Expression[T], error) {
+	var es []expr.Expression[T]
+	for !p.at(":") && !p.at(")") {
+		e, err := p.expressionAtom()
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	switch len(es) {
+	case 0:
+		return nil, p.unexpected(p.peek(), "an expression")
+	case 1:
+		return es[0], nil
+	case 2:
+		return expr.Apply(es[0], es[1]), nil
+	}
+	return expr.Apply(es[0], es[1], es[2:]...), nil
+}
+
+func (p *typeParser[T]) expressionAtom() (expr.Expression[T], error) {
+	tok := p.next()
+	switch {
+	case tok.kind == identToken && isVariableName(tok.text) && !isKeyword(tok.text):
+		return expr.Var(p.makeName(tok.text)), nil
+	case tok.kind == identToken && !isKeyword(tok.text), tok.kind == numberToken, tok.kind == operatorToken:
+		return expr.MakeConst(p.makeName(tok.text)), nil
+	case tok.text == "(":
+		e, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	}
+	return nil, p.unexpected(tok, "an expression")
+}
@@ -9,10 +9,21 @@ import (
 
 type Variable[T nameable.Nameable] struct {
 	boundContext int32
-	name         T
+	// let-nesting depth at which variable was created; see (*Context).EnterLevel
+	level uint32
+	name  T
+	// true iff variable is a skolem; see (*Context).Skolem
+	rigid bool
+	// name a skolem is printed w/
+	display T
 }
 
+// returns `v` unless `v` is rigid; rigid variables are never substituted for,
+// so they are not free
 func (v Variable[T]) GetFreeVariables() []Variable[T] {
+	if v.rigid {
+		return []Variable[T]{}
+	}
 	return []Variable[T]{v}
 }
 
@@ -33,6 +44,37 @@ func (v Variable[T]) BoundIn(i int32) Variable[T] {
 	return v
 }
 
+// returns number of the context that binds `v`; see BoundIn
+func (v Variable[T]) GetBoundContext() int32 {
+	return v.boundContext
+}
+
+// returns let-nesting depth at which variable was created
+func (v Variable[T]) GetLevel() uint32 {
+	return v.level
+}
+
+// returns copy of `v` created at let-nesting depth `level`
+func (v Variable[T]) AtLevel(level uint32) Variable[T] {
+	v.level = level
+	return v
+}
+
+// creates a rigid type variable, or skolem, for variable `v` bound by a
+// polytype. A skolem stands for a single, unknown type: it is equal only to
+// itself and is never substituted for. Skolems have names as unique as those
+// of NewVar, but they are printed w/ the name of `v`
+func (cxt *Context[T]) Skolem(v Variable[T]) Variable[T] {
+	skolem := cxt.NewVar()
+	skolem.rigid, skolem.display = true, v.name
+	return skolem
+}
+
+// returns true iff `v` is a skolem; see (*Context).Skolem
+func (v Variable[T]) IsRigid() bool {
+	return v.rigid
+}
+
 func Var[T nameable.Nameable](name T) Variable[T] {
 	return Variable[T]{boundContext: 0, name: name}
 }
@@ -53,8 +95,12 @@ func (cxt *Context[T]) MakeDummyVars(n uint) []Variable[T] {
 	return fun.FMap(make([]Variable[T], n), cxt.dummyName)
 }
 
-// Var("a").BoundBy(x).String() = "a"
+// Var("a").BoundBy(x).String() = "a"; skolems are written w/ the name of the
+// variable they were created for
 func (v Variable[T]) String() string {
+	if v.rigid {
+		return v.display.GetName()
+	}
 	return v.name.GetName()
 }
 
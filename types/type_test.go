@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strconv"
 	"testing"
 
 	expr "github.com/petersalex27/yew-packages/expr"
@@ -23,8 +24,12 @@ func judgment[E expr.Expression[test_nameable]](e E, ty Type[test_nameable]) Typ
 var base = NewContext[test_nameable]().
 	SetNameMaker(test_nameable_fn)
 
-func TestString(t *testing.T) {
-	tests := []struct {
+// types and the strings (*Type).String writes for them
+func stringTests() []struct {
+	in     Type[test_nameable]
+	expect string
+} {
+	return []struct {
 		in     Type[test_nameable]
 		expect string
 	}{
@@ -106,15 +111,515 @@ func TestString(t *testing.T) {
 			},
 			expect: "mapval (n: Uint) . (Array a)",
 		},
+		// qualified types
+		{
+			in:     Qualify(_Forall("a").Bind(_Function(_Var("a"), _Var("a"))), Pred[test_nameable]("Eq", _Var("a"))),
+			expect: "forall a . Eq a => (a -> a)",
+		},
+		{
+			in: Qualify(
+				_Forall("a").Bind(_Function(_Var("a"), _Var("a"))),
+				Pred[test_nameable]("Eq", _App("List", _Var("a"))),
+				Pred[test_nameable]("Show", _Var("a")),
+			),
+			expect: "forall a . (Eq (List a), Show a) => (a -> a)",
+		},
+		// nested polytypes
+		{
+			in:     _Function(Nest(_Forall("a").Bind(_Function(_Var("a"), _Var("a")))), _Con("Int")),
+			expect: "((forall a . (a -> a)) -> Int)",
+		},
+		{
+			in: Nest(_Forall("a").Bind(_App("Pair", _Var("a"), _Var("b")))).ReplaceDependent(
+				[]Variable[test_nameable]{_Var("a"), _Var("b")},
+				[]Monotyped[test_nameable]{_Con("Int"), _Con("Int")},
+			),
+			expect: "(forall a . (Pair a Int))",
+		},
+		// records
+		{
+			in:     Closed(Field[test_nameable]("name", _Con("String")), Field[test_nameable]("age", _Con("Int"))),
+			expect: "{age: Int, name: String}",
+		},
+		{
+			in:     RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("name", _Var("a"))}, _Var("r")),
+			expect: "{name: a | r}",
+		},
+		{
+			in: RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("name", _Var("a"))}, _Var("r")).
+				Replace(_Var("r"), RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("age", _Con("Int"))}, _Var("s"))),
+			expect: "{age: Int, name: a | s}",
+		},
+		{
+			in:     Closed[test_nameable](),
+			expect: "{}",
+		},
 	}
+}
 
-	for testIndex, test := range tests {
+func TestString(t *testing.T) {
+	for testIndex, test := range stringTests() {
 		if actual := test.in.String(); actual != test.expect {
 			t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", testIndex+1, test.expect, actual)
 		}
 	}
 }
 
+func TestPretty(t *testing.T) {
+	index := func(name string) ExpressionJudgment[test_nameable, expr.Referable[test_nameable]] {
+		return Judgment[test_nameable, expr.Referable[test_nameable]](expr.Var(base.makeName(name)), _Con("Uint"))
+	}
+	list := func(m Monotyped[test_nameable]) Application[test_nameable] {
+		return Apply[test_nameable](base.EnclosingCon(1, "[]"), m)
+	}
+	and := func(a, b Monotyped[test_nameable]) Application[test_nameable] { return base.Infix(a, "&", b) }
+
+	tests := []struct {
+		in     []Type[test_nameable]
+		expect []string
+	}{
+		// renaming
+		{
+			in: []Type[test_nameable]{_Forall("$3", "$7").Bind(_Function(
+				_Function(_Var("$3"), _Var("$7")),
+				_Function(_App("List", _Var("$3")), _App("List", _Var("$7"))),
+			))},
+			expect: []string{"forall a b . (a -> b) -> List a -> List b"},
+		},
+		{in: []Type[test_nameable]{_Function(_Var("$5"), _Var("$2"))}, expect: []string{"a -> b"}},
+		{in: []Type[test_nameable]{_Function(_Var("$0"), _Var("a"))}, expect: []string{"b -> a"}},
+		{in: []Type[test_nameable]{_Forall("x").Bind(_Function(_Var("x"), _Var("a")))}, expect: []string{"forall b . b -> a"}},
+		{
+			in:     []Type[test_nameable]{_Function(_Var("$3"), _Con("Int")), _App("List", _Var("$3"))},
+			expect: []string{"a -> Int", "List a"},
+		},
+		{
+			in: []Type[test_nameable]{_Forall("a").Bind(_Function(
+				_Var("a"),
+				Nest(_Forall("a").Bind(_Var("a"))),
+			))},
+			expect: []string{"forall a . a -> (forall b . b)"},
+		},
+		// skolems
+		{in: []Type[test_nameable]{_Function(base.Skolem(_Var("a")), _Var("$4"))}, expect: []string{"a -> b"}},
+		{in: []Type[test_nameable]{_Function(base.Skolem(_Var("a")), _Var("a"))}, expect: []string{"a1 -> a"}},
+		{in: []Type[test_nameable]{_Function(base.Skolem(_Var("a")), base.Skolem(_Var("a")))}, expect: []string{"a -> a1"}},
+		// empty prefixes
+		{in: []Type[test_nameable]{_Con("Type").Generalize(base)}, expect: []string{"Type"}},
+		{in: []Type[test_nameable]{_Forall("a", "b").Bind(_App("Type", _Var("b")))}, expect: []string{"forall a . Type a"}},
+		{
+			in: []Type[test_nameable]{DependentType[test_nameable]{
+				nil,
+				Index[test_nameable](_App("Array", _Var("a"))),
+			}},
+			expect: []string{"Array a"},
+		},
+		// parentheses
+		{
+			in:     []Type[test_nameable]{_Function(_Function(_Con("Int"), _Con("Int")), _Function(_Con("Int"), _Con("Int")))},
+			expect: []string{"(Int -> Int) -> Int -> Int"},
+		},
+		{in: []Type[test_nameable]{_Function(and(_Con("Int"), _Con("Bool")), _Con("Int"))}, expect: []string{"Int & Bool -> Int"}},
+		{in: []Type[test_nameable]{and(_Function(_Con("Int"), _Con("Bool")), _Con("Int"))}, expect: []string{"(Int -> Bool) & Int"}},
+		{in: []Type[test_nameable]{base.Infix(base.Infix(_Con("A"), "+", _Con("B")), "+", _Con("C"))}, expect: []string{"(A + B) + C"}},
+		{
+			in:     []Type[test_nameable]{_App("Maybe", _Function(_Var("$1"), _App("Maybe", _Var("$1"))))},
+			expect: []string{"Maybe (a -> Maybe a)"},
+		},
+		{in: []Type[test_nameable]{list(_App("List", _Var("$1")))}, expect: []string{"[List a]"}},
+		{in: []Type[test_nameable]{base.Infix(_Var("a"), "->")}, expect: []string{"(->) a"}},
+		{
+			in:     []Type[test_nameable]{_Function(Nest(_Forall("a").Bind(_Function(_Var("a"), _Var("a")))), _Con("Int"))},
+			expect: []string{"(forall a . a -> a) -> Int"},
+		},
+		{
+			in: []Type[test_nameable]{Qualify(
+				_Forall("$0").Bind(_Function(_Var("$0"), _Var("$0"))),
+				Pred[test_nameable]("Eq", _App("List", _Var("$0"))),
+				Pred[test_nameable]("Show", _Var("$0")),
+			)},
+			expect: []string{"forall a . (Eq (List a), Show a) => a -> a"},
+		},
+		{
+			in:     []Type[test_nameable]{RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("f", _Function(_Var("$0"), _Var("$0")))}, _Var("$1"))},
+			expect: []string{"{f: a -> a | b}"},
+		},
+		// indexes
+		{in: []Type[test_nameable]{Index[test_nameable](_App("Array", _Con("Int")), index("n"))}, expect: []string{"(Array Int; n)"}},
+		{in: []Type[test_nameable]{Index[test_nameable](list(_Con("Int")), index("$4"))}, expect: []string{"[Int; n]"}},
+		{in: []Type[test_nameable]{Index[test_nameable](_App("Array", _Con("Int")), index("n"), index("$4"))}, expect: []string{"(Array Int; n m)"}},
+		{
+			in: []Type[test_nameable]{DependentType[test_nameable]{
+				[]TypeJudgment[test_nameable, expr.Variable[test_nameable]]{
+					judgment(expr.Var(base.makeName("$2")), _Con("Uint")),
+				},
+				Index[test_nameable](_App("Array", _Var("$0")), index("$2")),
+			}},
+			expect: []string{"mapval (n: Uint) . (Array a; n)"},
+		},
+	}
+
+	for testIndex, test := range tests {
+		actual := PrettyAll(test.in...)
+		for i := range test.expect {
+			if actual[i] != test.expect[i] {
+				t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", testIndex+1, test.expect[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestAlphaEquals(t *testing.T) {
+	a, b, c, x, y := _Var("a"), _Var("b"), _Var("c"), _Var("x"), _Var("y")
+	n, m := expr.Var(base.makeName("n")), expr.Var(base.makeName("m"))
+	array := func(t Monotyped[test_nameable], v expr.Variable[test_nameable]) DependentTypeInstance[test_nameable] {
+		return Index[test_nameable](_App("Array", t), Judgment[test_nameable, expr.Referable[test_nameable]](v, _Con("Uint")))
+	}
+	mapval := func(v expr.Variable[test_nameable], f TypeFunction[test_nameable]) DependentType[test_nameable] {
+		return Map[test_nameable](judgment(v, _Con("Uint"))).To(f)
+	}
+
+	tests := []struct {
+		description string
+		left        Type[test_nameable]
+		right       Type[test_nameable]
+		expect      bool
+	}{
+		{`forall a . a ≡ forall b . b`, _Forall("a").Bind(a), _Forall("b").Bind(b), true},
+		{`forall x1 x2 . x1 ≡ forall y1 y2 . y1`, _Forall("x1", "x2").Bind(_Var("x1")), _Forall("y1", "y2").Bind(_Var("y1")), true},
+		{`forall a b . a ≡ forall c . c`, _Forall("a", "b").Bind(a), _Forall("c").Bind(c), true},
+		{`forall _ . Int ≡ Int`, _Con("Int").Generalize(base), _Con("Int"), true},
+		{`forall a b . a -> b ≡ forall b a . b -> a`, _Forall("a", "b").Bind(_Function(a, b)), _Forall("b", "a").Bind(_Function(b, a)), true},
+		{`forall a b . a -> b ≢ forall c . c -> c`, _Forall("a", "b").Bind(_Function(a, b)), _Forall("c").Bind(_Function(c, c)), false},
+		{`forall a . a -> a ≢ forall a b . a -> b`, _Forall("a").Bind(_Function(a, a)), _Forall("a", "b").Bind(_Function(a, b)), false},
+		{`forall a . a -> b ≢ forall b . b -> b`, _Forall("a").Bind(_Function(a, b)), _Forall("b").Bind(_Function(b, b)), false},
+		{`forall a . a -> x ≡ forall b . b -> x`, _Forall("a").Bind(_Function(a, x)), _Forall("b").Bind(_Function(b, x)), true},
+		{`forall a . a -> x ≢ forall b . b -> y`, _Forall("a").Bind(_Function(a, x)), _Forall("b").Bind(_Function(b, y)), false},
+		{`forall a . a ≢ x`, _Forall("a").Bind(a), x, false},
+		{
+			`forall a . (forall b . b -> a) -> a ≡ forall c . (forall a . a -> c) -> c`,
+			_Forall("a").Bind(_Function(Nest(_Forall("b").Bind(_Function(b, a))), a)),
+			_Forall("c").Bind(_Function(Nest(_Forall("a").Bind(_Function(a, c))), c)),
+			true,
+		},
+		{
+			`forall a . (forall b . b -> a) ≢ forall a . (forall b . b -> b)`,
+			_Forall("a").Bind(Nest(_Forall("b").Bind(_Function(b, a)))),
+			_Forall("a").Bind(Nest(_Forall("b").Bind(_Function(b, b)))),
+			false,
+		},
+		{
+			`forall a . (Eq a, Show a) => a ≡ forall b . (Show b, Eq b) => b`,
+			Qualify(_Forall("a").Bind(a), Pred[test_nameable]("Eq", a), Pred[test_nameable]("Show", a)),
+			Qualify(_Forall("b").Bind(b), Pred[test_nameable]("Show", b), Pred[test_nameable]("Eq", b)),
+			true,
+		},
+		{
+			`forall a b . Eq a => a -> b ≢ forall a b . Eq b => a -> b`,
+			Qualify(_Forall("a", "b").Bind(_Function(a, b)), Pred[test_nameable]("Eq", a)),
+			Qualify(_Forall("a", "b").Bind(_Function(a, b)), Pred[test_nameable]("Eq", b)),
+			false,
+		},
+		{
+			`forall a . mapval (n: Uint) . (Array a; n) ≡ forall b . mapval (m: Uint) . (Array b; m)`,
+			_Forall("a").Bind(mapval(n, array(a, n))),
+			_Forall("b").Bind(mapval(m, array(b, m))),
+			true,
+		},
+		{
+			`forall a . (Array a; n) ≢ forall b . (Array b; m)`,
+			_Forall("a").Bind(array(a, n)),
+			_Forall("b").Bind(array(b, m)),
+			false,
+		},
+		{
+			`forall r . {x: Int | r} ≡ forall s . {x: Int | s}`,
+			_Forall("r").Bind(RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("x", _Con("Int"))}, _Var("r"))),
+			_Forall("s").Bind(RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("x", _Con("Int"))}, _Var("s"))),
+			true,
+		},
+	}
+
+	for testIndex, test := range tests {
+		var actual bool
+		switch left := test.left.(type) {
+		case Qualified[test_nameable]:
+			actual = left.AlphaEquals(test.right)
+		case Polytype[test_nameable]:
+			actual = left.AlphaEquals(test.right)
+		}
+		if actual != test.expect {
+			t.Fatalf("failed test #%d (%s):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.description, test.expect, actual)
+		}
+		// alpha-equivalence is symmetric
+		if reversed := alphaEquals[test_nameable](nil, test.right, test.left); reversed != test.expect {
+			t.Fatalf("failed test #%d (%s, reversed):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.description, test.expect, reversed)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	a, b := _Var("a"), _Var("b")
+	n := expr.Var(base.makeName("n"))
+	uint := _Con("Uint")
+	index := ExpressionJudgment[test_nameable, expr.Referable[test_nameable]](Judgment[test_nameable, expr.Referable[test_nameable]](n, uint))
+	mapval := []TypeJudgment[test_nameable, expr.Variable[test_nameable]]{judgment(n, uint)}
+	list := func(m Monotyped[test_nameable]) Application[test_nameable] {
+		return Apply[test_nameable](base.EnclosingCon(1, "[]"), m)
+	}
+	pred := func(class string, ms ...Monotyped[test_nameable]) Predicate[test_nameable] {
+		return Pred(base.makeName(class), ms...)
+	}
+
+	tests := []struct {
+		in     string
+		expect Type[test_nameable]
+	}{
+		{in: "Int", expect: _Con("Int")},
+		{in: "a", expect: a},
+		{in: "$0", expect: _Var("$0")},
+		{in: "Maybe (List a)", expect: _App("Maybe", _App("List", a))},
+		{in: "(a -> (b -> a))", expect: _Function(a, _Function(b, a))},
+		{in: "a -> b -> a", expect: _Function(a, _Function(b, a))},
+		{in: "(a -> b) -> a", expect: _Function(_Function(a, b), a)},
+		{in: "List a -> a | b & a", expect: _Function(_App("List", a), base.Join(a, base.Cons(b, a)))},
+		{in: "(->)", expect: Application[test_nameable]{c: base.InfixCon("->")}},
+		{in: "((->) a)", expect: base.Infix(a, "->")},
+		{in: "[]", expect: Application[test_nameable]{c: base.EnclosingCon(1, "[]")}},
+		{in: "[(Maybe a)]", expect: list(_App("Maybe", a))},
+		{in: "[Maybe a]", expect: Apply[test_nameable](base.EnclosingCon(1, "[]"), _Con("Maybe"), a)},
+		{in: "[{A}]", expect: list(Apply[test_nameable](base.EnclosingCon(1, "{}"), _Con("A")))},
+		{in: "(a -> b (List a))", expect: Apply[test_nameable](base.InfixCon("->"), a, b, _App("List", a))},
+		{in: "{}", expect: Closed[test_nameable]()},
+		{in: "{r}", expect: RecordOf[test_nameable](nil, _Var("r"))},
+		{
+			in:     "{name: String, age: Int -> Int | r}",
+			expect: RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("name", _Con("String")), Field[test_nameable]("age", _Function(_Con("Int"), _Con("Int")))}, _Var("r")),
+		},
+		{in: "forall a . a -> a", expect: _Forall("a").Bind(_Function(a, a))},
+		{in: "(forall a . a -> a) -> Int", expect: _Function(Nest(_Forall("a").Bind(_Function(a, a))), _Con("Int"))},
+		{in: "Eq a => a", expect: Qualify(Forall[test_nameable]().Bind(a), pred("Eq", a))},
+		{
+			in:     "forall a b . (Eq (List a), Show b) => a -> b",
+			expect: Qualify(_Forall("a", "b").Bind(_Function(a, b)), pred("Eq", _App("List", a)), pred("Show", b)),
+		},
+		{in: "(Array a; (n: Uint))", expect: Index[test_nameable](_App("Array", a), index)},
+		{in: "[a; (n: Uint)]", expect: Index[test_nameable](list(a), index)},
+		{
+			in: "(Vec a; (((+ n) 1): Uint))",
+			expect: Index[test_nameable](_App("Vec", a), Judgment[test_nameable, expr.Referable[test_nameable]](
+				expr.Compute[test_nameable](expr.Apply[test_nameable](expr.MakeConst(base.makeName("+")), n, expr.MakeConst(base.makeName("1")))),
+				uint,
+			)),
+		},
+		{
+			in:     "forall a . mapval (n: Uint) . (Array a; n) -> a",
+			expect: _Forall("a").Bind(MakeDependentType[test_nameable](mapval, _Function(Index[test_nameable](_App("Array", a), index), a))),
+		},
+	}
+
+	for testIndex, test := range tests {
+		actual, e := base.Parse(test.in)
+		if e != nil {
+			t.Fatalf("failed test #%d:\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.expect, e)
+		}
+		if !actual.Equals(test.expect) {
+			t.Fatalf("failed test #%d:\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.expect, actual)
+		}
+		// round-trip
+		if again, e := base.Parse(actual.String()); e != nil || !again.Equals(actual) {
+			t.Fatalf("failed test #%d (%s):\nexpected:\n%v\nactual:\n%v (%v)\n", testIndex+1, actual.String(), actual, again, e)
+		}
+	}
+}
+
+// parsing what String writes gives back the type written
+func TestParseString(t *testing.T) {
+	for testIndex, test := range stringTests() {
+		actual, e := base.Parse(test.expect)
+		if e != nil {
+			t.Fatalf("failed test #%d (%s):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.expect, test.in, e)
+		}
+		if actual.String() != test.expect {
+			t.Fatalf("failed test #%d:\nexpected:\n%s\nactual:\n%s\n", testIndex+1, test.expect, actual.String())
+		}
+		// an index w/o a type is typed by a new type variable, and a constant
+		// named like a variable is read back as a variable; only their strings
+		// are compared
+		if !hasFreeJudgment(test.in) && !hasVariableNamedConstant(test.in) && !actual.Equals(test.in) {
+			t.Fatalf("failed test #%d (%s):\nexpected:\n%#v\nactual:\n%#v\n", testIndex+1, test.expect, test.in, actual)
+		}
+	}
+}
+
+// returns true iff `ty` applies a constant whose name begins like a variable's
+func hasVariableNamedConstant(ty Type[test_nameable]) bool {
+	switch t := ty.(type) {
+	case Constant[test_nameable]:
+		return isVariableName(t.String())
+	case Application[test_nameable]:
+		for _, param := range t.ts {
+			if hasVariableNamedConstant(param) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// returns true iff an index of `ty` is a free judgment
+func hasFreeJudgment(ty Type[test_nameable]) bool {
+	dti, ok := ty.(DependentTypeInstance[test_nameable])
+	if !ok {
+		return false
+	}
+	for _, index := range dti.Indexes {
+		if _, free := index.(FreeJudgment[test_nameable, expr.Referable[test_nameable]]); free {
+			return true
+		}
+	}
+	return false
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{in: "", expect: "1:1: expected a type but found end of input"},
+		{in: "(Int", expect: "1:5: expected `)` but found end of input"},
+		{in: "forall . a", expect: "1:8: expected a variable but found `.`"},
+		{in: "forall a .\n  a -> ", expect: "2:8: expected a type but found end of input"},
+		{in: "Int Int)", expect: "1:8: expected end of input but found `)`"},
+		{in: "a + b + c", expect: "1:7: `+` does not associate; add parentheses"},
+		{in: "(Array a; n)", expect: "1:11: index n needs a type, e.g., (n: Uint)"},
+		{in: "mapval (n: Uint) . a", expect: "1:20: expected an application after `mapval`"},
+		{in: "{Int}", expect: "1:2: expected a variable but found `Int`"},
+		{in: "(forall a . Eq a => a)", expect: "1:1: nested polytypes cannot be constrained by classes"},
+		{in: "Int \u00a7", expect: "1:5: unexpected character `\u00a7`"},
+	}
+
+	for testIndex, test := range tests {
+		_, e := base.Parse(test.in)
+		if e == nil || e.Error() != test.expect {
+			t.Fatalf("failed test #%d (%s):\nexpected:\n%s\nactual:\n%v\n", testIndex+1, test.in, test.expect, e)
+		}
+	}
+}
+
+func TestInterner(t *testing.T) {
+	a, b := _Var("a"), _Var("b")
+	n := expr.Var(base.makeName("n"))
+	index := ExpressionJudgment[test_nameable, expr.Referable[test_nameable]](judgment[expr.Referable[test_nameable]](n, _Con("Uint")))
+	record := func(rest Monotyped[test_nameable]) Record[test_nameable] {
+		return RecordOf[test_nameable]([]FieldType[test_nameable]{Field[test_nameable]("f", _Function(a, b))}, rest)
+	}
+	nested := Nest(_Forall("a").Bind(_Function(a, b)))
+
+	tests := []struct {
+		left, right Monotyped[test_nameable]
+		equal       bool
+		frees       []string
+	}{
+		{left: a, right: _Var("a"), equal: true, frees: []string{"a"}},
+		{left: a, right: a.BoundIn(1), equal: false, frees: []string{"a"}},
+		{left: _Con("Int"), right: _Con("Int"), equal: true, frees: []string{}},
+		{left: _Con("Int"), right: base.InfixCon("Int"), equal: false, frees: []string{}},
+		{left: _Function(a, _Function(b, a)), right: _Function(a, _Function(b, a)), equal: true, frees: []string{"a", "b"}},
+		{left: _Function(a, b), right: _Function(b, a), equal: false, frees: []string{"a", "b"}},
+		{left: _App("List", a), right: _App("List", b), equal: false, frees: []string{"a"}},
+		{left: Index[test_nameable](_App("Array", a), index), right: Index[test_nameable](_App("Array", a), index), equal: true, frees: []string{"a"}},
+		{left: Index[test_nameable](_App("Array", a), index), right: _App("Array", a), equal: false, frees: []string{"a"}},
+		{left: record(_Var("r")), right: record(_Var("r")), equal: true, frees: []string{"a", "b", "r"}},
+		{left: record(_Var("r")), right: record(nil), equal: false, frees: []string{"a", "b", "r"}},
+		{left: nested, right: Nest(_Forall("a").Bind(_Function(a, b))), equal: true, frees: []string{"b"}},
+		{left: nested, right: Nest(_Forall("b").Bind(_Function(b, b))), equal: false, frees: []string{"b"}},
+	}
+
+	for testIndex, test := range tests {
+		in := NewInterner[test_nameable]()
+		left, right := in.Intern(test.left), in.Intern(test.right)
+		if (left == right) != test.equal || test.left.Equals(test.right) != test.equal {
+			t.Fatalf("failed test #%d (%v, %v):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.left, test.right, test.equal, left == right)
+		}
+		if !in.Type(left).Equals(test.left) {
+			t.Fatalf("failed test #%d:\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.left, in.Type(left))
+		}
+		frees := in.FreeVariables(left)
+		if len(frees) != len(test.frees) {
+			t.Fatalf("failed test #%d (free variables):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.frees, frees)
+		}
+		for i, v := range frees {
+			if v.GetName() != test.frees[i] {
+				t.Fatalf("failed test #%d (free variables):\nexpected:\n%v\nactual:\n%v\n", testIndex+1, test.frees, frees)
+			}
+		}
+	}
+}
+
+func TestInternerSharing(t *testing.T) {
+	a := _Var("a")
+	in := NewInterner[test_nameable]()
+	id := in.Intern(_Function(_App("List", a), _App("List", a)))
+	// a, List, (List a), (->), and ((List a) -> (List a))
+	if expect := 5; in.Len() != expect {
+		t.Fatalf("failed test #1:\nexpected:\n%d\nactual:\n%d\n", expect, in.Len())
+	}
+	if again := in.Intern(_Function(_App("List", a), _App("List", a))); again != id || in.Len() != 5 {
+		t.Fatalf("failed test #2:\nexpected:\n%d\nactual:\n%d\n", id, again)
+	}
+
+	// the arguments of the canonical function are the canonical `List a`
+	_, list := in.Canonical(_App("List", a))
+	function := in.Type(id).(Application[test_nameable])
+	for i, param := range function.ts {
+		if param.(Application[test_nameable]).ts[0] != list.(Application[test_nameable]).ts[0] {
+			t.Fatalf("failed test #%d:\nexpected shared subterm %v\n", i+3, list)
+		}
+	}
+}
+
+// returns `a0 -> (a1 -> .. (a{n-1} -> List a0) ..)`
+func makeLargeType(n int) Monotyped[test_nameable] {
+	var m Monotyped[test_nameable] = _App("List", _Var("a0"))
+	for i := n - 1; i >= 0; i-- {
+		m = _Function(_App("Pair", _Var("a"+strconv.Itoa(i)), _Con("Int")), m)
+	}
+	return m
+}
+
+func BenchmarkEquals(b *testing.B) {
+	left, right := makeLargeType(1000), makeLargeType(1000)
+	for i := 0; i < b.N; i++ {
+		left.Equals(right)
+	}
+}
+
+func BenchmarkInternedEquals(b *testing.B) {
+	in := NewInterner[test_nameable]()
+	left, right := in.Intern(makeLargeType(1000)), in.Intern(makeLargeType(1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = left == right
+	}
+}
+
+func BenchmarkGetFreeVariables(b *testing.B) {
+	m := makeLargeType(1000)
+	for i := 0; i < b.N; i++ {
+		m.GetFreeVariables()
+	}
+}
+
+func BenchmarkInternedFreeVariables(b *testing.B) {
+	in := NewInterner[test_nameable]()
+	id := in.Intern(makeLargeType(1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.FreeVariables(id)
+	}
+}
+
 func _Forall(vs ...string) binders[test_nameable] {
 	return base.Forall(vs...)
 }